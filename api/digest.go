@@ -0,0 +1,193 @@
+package api
+
+import (
+	"database/sql"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/digest"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDigestHour = 8
+	defaultDigestWeekday = time.Monday
+)
+
+type digestSettingsRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=off daily weekly"`
+	//Hour is local to the timezone of the user
+	Hour *int32 `json:"hour" binding:"required,min=0,max=23"`
+	//Weekday is only used by weekly digests, 0 is Sunday
+	Weekday *int32 `json:"weekday" binding:"omitempty,min=0,max=6"`
+}
+
+type digestSettingsResponse struct {
+	Frequency string `json:"frequency"`
+	Hour int32 `json:"hour"`
+	Weekday int32 `json:"weekday"`
+	Timezone string `json:"timezone"`
+	LastSentAt *string `json:"last_sent_at"`
+}
+
+type digestPreviewRequest struct {
+	//Frequency defaults to the one the user picked, or daily when digests are off
+	Frequency string `form:"frequency" binding:"omitempty,oneof=daily weekly"`
+	Format string `form:"format" binding:"omitempty,oneof=json text html"`
+}
+
+type digestPreviewResponse struct {
+	Subject string `json:"subject"`
+	Summary string `json:"summary"`
+	Text string `json:"text"`
+	HTML string `json:"html"`
+}
+
+func newDigestSettingsResponse(user db.User, settings db.DigestSetting) digestSettingsResponse {
+	res := digestSettingsResponse {
+		Frequency: string(settings.Frequency),
+		Hour: settings.Hour,
+		Weekday: settings.Weekday,
+		Timezone: user.Location().String(),
+	}
+
+	if settings.LastSentAt.Valid {
+		lastSentAt := settings.LastSentAt.Time.Format(time.RFC3339)
+		res.LastSentAt = &lastSentAt
+	}
+
+	return res
+}
+
+// digestSettings returns the digest settings of user. Users who never changed
+// them have digests turned off.
+func (server *Server) digestSettings(ctx *gin.Context, user db.User) (settings db.DigestSetting, ok bool) {
+	settings, err := server.store.GetDigestSettings(ctx, user.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.DigestSetting {
+				UserID: user.ID,
+				Frequency: db.DigestFrequencyOff,
+				Hour: defaultDigestHour,
+				Weekday: int32(defaultDigestWeekday),
+			}, true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return settings, true
+}
+
+func (server *Server) getDigestSettings(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	settings, ok := server.digestSettings(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDigestSettingsResponse(user, settings))
+}
+
+func (server *Server) updateDigestSettings(ctx *gin.Context) {
+	var req digestSettingsRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.UpsertDigestSettingsParams {
+		UserID: user.ID,
+		Frequency: db.DigestFrequency(req.Frequency),
+		Hour: *req.Hour,
+		Weekday: int32(defaultDigestWeekday),
+	}
+
+	if req.Weekday != nil {
+		arg.Weekday = *req.Weekday
+	}
+
+	settings, err := server.store.UpsertDigestSettings(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDigestSettingsResponse(user, settings))
+}
+
+// previewDigest renders the digest the user would get right now. format=text
+// and format=html return the rendered message as is, for viewing in a browser.
+func (server *Server) previewDigest(ctx *gin.Context) {
+	var req digestPreviewRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	frequency := db.DigestFrequency(req.Frequency)
+
+	if frequency == "" {
+		settings, ok := server.digestSettings(ctx, user)
+
+		if !ok {
+			return
+		}
+
+		frequency = settings.Frequency
+		if frequency == db.DigestFrequencyOff {
+			frequency = db.DigestFrequencyDaily
+		}
+	}
+
+	agenda, err := digest.Load(ctx, server.store, user, frequency, time.Now())
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	msg, err := agenda.Message(user)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	switch req.Format {
+	case "text":
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(msg.Text))
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+	default:
+		ctx.JSON(http.StatusOK, digestPreviewResponse {
+			Subject: msg.Subject,
+			Summary: agenda.Summary(),
+			Text: msg.Text,
+			HTML: msg.HTML,
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDigestSettingsApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetDigestSettings(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.DigestSetting{}, sql.ErrNoRows)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodGet, "/digest/settings", nil)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"frequency":"off","hour":8,"weekday":1,"timezone":"UTC","last_sent_at":null}`, recorder.Body.String())
+}
+
+func TestUpdateDigestSettingsApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Weekly",
+			body: gin.H{"frequency": "weekly", "hour": 0, "weekday": 5},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpsertDigestSettingsParams {
+					UserID: user.ID,
+					Frequency: db.DigestFrequencyWeekly,
+					Hour: 0,
+					Weekday: 5,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UpsertDigestSettings(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.DigestSetting{UserID: user.ID, Frequency: arg.Frequency, Hour: arg.Hour, Weekday: arg.Weekday}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res digestSettingsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, "weekly", res.Frequency)
+				require.Equal(t, int32(5), res.Weekday)
+			},
+		},
+		{
+			name: "MissingHour",
+			body: gin.H{"frequency": "daily"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertDigestSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidHour",
+			body: gin.H{"frequency": "daily", "hour": 24},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertDigestSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidFrequency",
+			body: gin.H{"frequency": "hourly", "hour": 8},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertDigestSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/digest/settings", bytes.NewReader(data))
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPreviewDigestApi(t *testing.T) {
+	user := randomUser()
+
+	overdue := randomTask(user)
+
+	testCases := []struct {
+		name string
+		query string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "JSON",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDigestSettings(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.DigestSetting{}, sql.ErrNoRows)
+				store.EXPECT().GetOpenTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{overdue}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res digestPreviewResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Contains(t, res.Subject, "Your agenda for")
+				require.Equal(t, "1 overdue, 0 due today, 0 upcoming reminders", res.Summary)
+				require.Contains(t, res.Text, overdue.Title)
+				require.Contains(t, res.HTML, overdue.Title)
+			},
+		},
+		{
+			name: "WeeklyHTML",
+			query: "?frequency=weekly&format=html",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDigestSettings(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetOpenTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+				require.Contains(t, recorder.Body.String(), "Nothing is due this week.")
+			},
+		},
+		{
+			name: "InvalidFormat",
+			query: "?format=pdf",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOpenTasksByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).AnyTimes().Return(user, nil)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodGet, "/digest/preview"+tc.query, nil)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"m1thrandir225/your_time/stream"
+	"m1thrandir225/your_time/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHeartbeatInterval = 15 * time.Second
+	//eventsRetry tells clients how long to wait before reconnecting, in milliseconds
+	eventsRetry = 3000
+	//eventResync asks the client to reload its state because events it
+	//missed are no longer available
+	eventResync = "resync"
+)
+
+// streamEvents is a Server-Sent Events stream of the task and reminder events
+// of the user. A client that reconnects with the Last-Event-ID header first
+// gets the events it missed. Comment lines are sent as heartbeats so proxies
+// don't close idle streams. The stream ends on the first heartbeat after the
+// access token is revoked.
+func (server *Server) streamEvents(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	sub, replay, complete := server.events.Subscribe(user.ID, ctx.GetHeader("Last-Event-ID"))
+	defer sub.Close()
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	//Stops nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+
+	ctx.Status(http.StatusOK)
+
+	fmt.Fprintf(ctx.Writer, "retry: %d\n\n", eventsRetry)
+
+	if !complete {
+		fmt.Fprintf(ctx.Writer, "event: %s\ndata: {}\n\n", eventResync)
+	}
+
+	for _, event := range replay {
+		writeEvent(ctx.Writer, event)
+	}
+
+	ctx.Writer.Flush()
+
+	ticker := time.NewTicker(server.heartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			//The stream was dropped, the client reconnects and resumes
+			if !ok {
+				return
+			}
+			writeEvent(ctx.Writer, event)
+		case <-ticker.C:
+			if server.revocations.IsRevoked(payload) {
+				return
+			}
+			fmt.Fprint(ctx.Writer, ": heartbeat\n\n")
+		}
+
+		ctx.Writer.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event stream.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+// heartbeatInterval is how often idle streams and WebSockets are pinged.
+func (server *Server) heartbeatInterval() time.Duration {
+	if server.config.EventsHeartbeatInterval <= 0 {
+		return defaultHeartbeatInterval
+	}
+	return server.config.EventsHeartbeatInterval
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	mockdb "m1thrandir225/your_time/db/mock"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// openEventStream connects to GET /events of server and returns a reader over the stream.
+func openEventStream(t *testing.T, server *Server, email string, lastEventID string) (*http.Response, *bufio.Reader) {
+	httpServer := httptest.NewServer(server.router)
+	t.Cleanup(httpServer.Close)
+
+	request, err := http.NewRequest(http.MethodGet, httpServer.URL + "/events", nil)
+	require.NoError(t, err)
+
+	if email != "" {
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, email, time.Minute)
+	}
+
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { response.Body.Close() })
+
+	return response, bufio.NewReader(response.Body)
+}
+
+// readEventBlock reads the stream up to the next blank line.
+func readEventBlock(t *testing.T, reader *bufio.Reader) string {
+	var block strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		if line == "\n" {
+			return block.String()
+		}
+
+		block.WriteString(line)
+	}
+}
+
+func TestStreamEventsApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+	server := newTestServer(t, store)
+
+	response, reader := openEventStream(t, server, user.Email, "")
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	require.Equal(t, "retry: 3000\n", readEventBlock(t, reader))
+
+	//The stream is subscribed before the first block is written
+
+	event := webhook.NewTaskEvent(webhook.EventTaskCreated, randomTask(user))
+	require.NoError(t, server.events.Publish(user.ID, event))
+
+	block := readEventBlock(t, reader)
+
+	require.Contains(t, block, "event: task.created\n")
+
+	data := block[strings.Index(block, "data: ") + len("data: "):]
+
+	var received webhook.Event
+	require.NoError(t, json.Unmarshal([]byte(data), &received))
+	require.Equal(t, event.ID, received.ID)
+}
+
+func TestStreamEventsResumeApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		lastEventID func(first uint64) string
+		checkStream func(t *testing.T, reader *bufio.Reader, first uint64)
+	}{
+		{
+			name: "Replay",
+			lastEventID: func(first uint64) string {
+				return fmt.Sprint(first)
+			},
+			checkStream: func(t *testing.T, reader *bufio.Reader, first uint64) {
+				require.Equal(t, "retry: 3000\n", readEventBlock(t, reader))
+
+				block := readEventBlock(t, reader)
+				require.Contains(t, block, fmt.Sprintf("id: %d\n", first + 1))
+				require.Contains(t, block, "event: task.completed\n")
+			},
+		},
+		{
+			name: "UnknownID",
+			lastEventID: func(first uint64) string {
+				return "42"
+			},
+			checkStream: func(t *testing.T, reader *bufio.Reader, first uint64) {
+				require.Equal(t, "retry: 3000\n", readEventBlock(t, reader))
+				require.Equal(t, "event: resync\ndata: {}\n", readEventBlock(t, reader))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+			server := newTestServer(t, store)
+
+			//Events published while the client was away
+
+			sub, _, _ := server.events.Subscribe(user.ID, "")
+			require.NoError(t, server.events.Publish(user.ID, webhook.NewTaskEvent(webhook.EventTaskUpdated, randomTask(user))))
+			require.NoError(t, server.events.Publish(user.ID, webhook.NewTaskEvent(webhook.EventTaskCompleted, randomTask(user))))
+			first := (<-sub.C).ID
+			sub.Close()
+
+			response, reader := openEventStream(t, server, user.Email, tc.lastEventID(first))
+
+			require.Equal(t, http.StatusOK, response.StatusCode)
+
+			tc.checkStream(t, reader, first)
+		})
+	}
+}
+
+func TestStreamEventsHeartbeatApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+	server := newTestServer(t, store)
+	server.config.EventsHeartbeatInterval = 10 * time.Millisecond
+
+	_, reader := openEventStream(t, server, user.Email, "")
+
+	require.Equal(t, "retry: 3000\n", readEventBlock(t, reader))
+	require.Equal(t, ": heartbeat\n", readEventBlock(t, reader))
+}
+
+func TestStreamEventsRevokedApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	server := newTestServer(t, store)
+	server.config.EventsHeartbeatInterval = 10 * time.Millisecond
+
+	response, reader := openEventStream(t, server, user.Email, "")
+
+	require.Equal(t, "retry: 3000\n", readEventBlock(t, reader))
+
+	accessToken := strings.TrimPrefix(response.Request.Header.Get(authorizationHeaderKey), authorizationTypeBearer + " ")
+
+	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	require.NoError(t, err)
+
+	require.NoError(t, server.revocations.Revoke(context.Background(), user.ID, payload))
+
+	//Heartbeats sent before the revocation was noticed may still come first
+	_, err = io.ReadAll(reader)
+	require.NoError(t, err)
+}
+
+func TestStreamEventsNoAuthorizationApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+
+	response, _ := openEventStream(t, server, "", "")
+
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/stream"
+	"m1thrandir225/your_time/token"
 	"m1thrandir225/your_time/util"
 	"os"
 	"testing"
@@ -15,9 +17,10 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config {
 		TokenSymmetricKey: util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		RefreshTokenDuration: time.Hour,
 	}
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, stream.NewBroker(stream.DefaultReplaySize), token.NewRevocationList(store), nil)
 
 	require.NoError(t, err)
 
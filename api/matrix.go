@@ -0,0 +1,85 @@
+package api
+
+import (
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultUrgencyHorizon is used when URGENCY_HORIZON isn't configured.
+const defaultUrgencyHorizon = 48 * time.Hour
+
+// taskMatrixResponse buckets open tasks into the four Eisenhower quadrants.
+type taskMatrixResponse struct {
+	Horizon string `json:"horizon"`
+	DoFirst []createTaskResponse `json:"do_first"`
+	Schedule []createTaskResponse `json:"schedule"`
+	Delegate []createTaskResponse `json:"delegate"`
+	Eliminate []createTaskResponse `json:"eliminate"`
+}
+
+// isUrgent reports whether a task is due before now plus the horizon, overdue
+// tasks included. Tasks explicitly marked with urgent priority are always urgent.
+func isUrgent(task db.Task, now time.Time, horizon time.Duration) bool {
+	return task.Priority == db.TaskPriorityUrgent || task.DueDate.Before(now.Add(horizon))
+}
+
+func (server *Server) urgencyHorizon() time.Duration {
+	if server.config.UrgencyHorizon > 0 {
+		return server.config.UrgencyHorizon
+	}
+
+	return defaultUrgencyHorizon
+}
+
+func (server *Server) getTaskMatrix(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	tasks, err := server.store.GetOpenTasksByUser(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	horizon := server.urgencyHorizon()
+	now := time.Now()
+
+	res := taskMatrixResponse {
+		Horizon: horizon.String(),
+		DoFirst: []createTaskResponse{},
+		Schedule: []createTaskResponse{},
+		Delegate: []createTaskResponse{},
+		Eliminate: []createTaskResponse{},
+	}
+
+	taskRes, err := server.taskResponses(ctx, tasks)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for i, task := range tasks {
+		urgent := isUrgent(task, now, horizon)
+
+		switch {
+		case urgent && task.Important:
+			res.DoFirst = append(res.DoFirst, taskRes[i])
+		case task.Important:
+			res.Schedule = append(res.Schedule, taskRes[i])
+		case urgent:
+			res.Delegate = append(res.Delegate, taskRes[i])
+		default:
+			res.Eliminate = append(res.Eliminate, taskRes[i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskMatrixApi(t *testing.T) {
+	user := randomUser()
+
+	now := time.Now()
+
+	doFirst := randomTask(user)
+	doFirst.DueDate = now.Add(time.Hour)
+	doFirst.Important = true
+
+	schedule := randomTask(user)
+	schedule.DueDate = now.Add(7 * 24 * time.Hour)
+	schedule.Important = true
+
+	delegate := randomTask(user)
+	delegate.DueDate = now.Add(7 * 24 * time.Hour)
+	delegate.Priority = db.TaskPriorityUrgent
+
+	eliminate := randomTask(user)
+	eliminate.DueDate = now.Add(7 * 24 * time.Hour)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().
+		GetOpenTasksByUser(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return([]db.Task{doFirst, schedule, delegate, eliminate}, nil)
+	store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+	store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+	store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/tasks/matrix", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res taskMatrixResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+
+	require.Equal(t, defaultUrgencyHorizon.String(), res.Horizon)
+
+	require.Len(t, res.DoFirst, 1)
+	require.Equal(t, doFirst.ID.String(), res.DoFirst[0].ID)
+
+	require.Len(t, res.Schedule, 1)
+	require.Equal(t, schedule.ID.String(), res.Schedule[0].ID)
+
+	require.Len(t, res.Delegate, 1)
+	require.Equal(t, delegate.ID.String(), res.Delegate[0].ID)
+
+	require.Len(t, res.Eliminate, 1)
+	require.Equal(t, eliminate.ID.String(), res.Eliminate[0].ID)
+}
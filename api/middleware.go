@@ -1,11 +1,15 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
 	"m1thrandir225/your_time/token"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 const (
@@ -14,7 +18,8 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware accepts requests with a valid access token that wasn't revoked.
+func authMiddleware(tokenMaker token.Maker, revocations *token.RevocationList) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -49,7 +54,55 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		//Refresh tokens aren't revoked along with access tokens, they only
+		//work through the session they belong to
+
+		if payload.Type != token.TokenTypeAccess {
+			ctx.AbortWithStatusJSON(401, errorResponse(token.ErrWrongTokenType))
+			return
+		}
+
+		if revocations.IsRevoked(payload) {
+			ctx.AbortWithStatusJSON(401, errorResponse(token.ErrRevokedToken))
+			return
+		}
+
 		ctx.Set(authorizationPayloadKey, payload)	 
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+// socketTokenMiddleware lets browsers, which can't set headers when opening a
+// WebSocket, pass the access token as a subprotocol: new WebSocket(url,
+// ["bearer", accessToken]). Unlike a query parameter the token doesn't end up
+// in access logs.
+func socketTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		protocols := websocket.Subprotocols(ctx.Request)
+
+		if ctx.GetHeader(authorizationHeaderKey) == "" && len(protocols) == 2 && protocols[0] == socketBearerProtocol {
+			ctx.Request.Header.Set(authorizationHeaderKey, authorizationTypeBearer + " " + protocols[1])
+		}
+
+		ctx.Next()
+	}
+}
+
+// authorizedUser loads the user the access token of the request was issued to.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) authorizedUser(ctx *gin.Context) (user db.User, ok bool) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, payload.Email)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("user of the access token no longer exists")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return user, true
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	mockdb "m1thrandir225/your_time/db/mock"
 	"m1thrandir225/your_time/token"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +11,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,7 +24,7 @@ func addAuthorization(
 	userEmail string,
 	duration time.Duration,
 ) {
-	accessToken, err := tokenMaker.CreateToken(userEmail, duration)
+	accessToken, _, err := tokenMaker.CreateToken(userEmail, token.TokenTypeAccess, duration)
 	require.NoError(t, err)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
@@ -78,6 +82,18 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("user@gmail.com", token.TokenTypeRefresh, time.Hour)
+				require.NoError(t, err)
+
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	} 
 
 	for i := range testCases {
@@ -90,7 +106,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocations),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -109,4 +125,40 @@ func TestAuthMiddleware(t *testing.T) {
 			tc.checkResponse(t, recorder)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestAuthMiddlewareRevokedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	server := newTestServer(t, store)
+
+	authPath := "/auth"
+
+	server.router.GET(
+		authPath,
+		authMiddleware(server.tokenMaker, server.revocations),
+		func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	accessToken, payload, err := server.tokenMaker.CreateToken("user@gmail.com", token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, server.revocations.Revoke(context.Background(), uuid.New(), payload))
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
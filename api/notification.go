@@ -0,0 +1,243 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type notificationResponse struct {
+	ID string `json:"id"`
+	Type string `json:"type"`
+	Title string `json:"title"`
+	Body string `json:"body"`
+	TaskID *string `json:"task_id"`
+	ReadAt *string `json:"read_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type listNotificationsRequest struct {
+	Unread bool `form:"unread"`
+	Cursor string `form:"cursor"`
+	Limit int32 `form:"limit" binding:"omitempty,min=1"`
+}
+
+type listNotificationsResponse struct {
+	Notifications []notificationResponse `json:"notifications"`
+	UnreadCount int64 `json:"unread_count"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+type notificationURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+var errNotificationNotOwned = errors.New("notification doesn't belong to the authenticated user")
+
+// notifySecurity tells the user about a change to how they sign in. The
+// change is made already, so failing to notify only gets logged. dedupeKey
+// may be empty.
+func (server *Server) notifySecurity(ctx *gin.Context, userID uuid.UUID, title string, body string, dedupeKey string) {
+	_, err := server.store.CreateNotification(ctx, db.CreateNotificationParams {
+		UserID: userID,
+		Type: db.NotificationTypeSecurity,
+		Title: title,
+		Body: body,
+		DedupeKey: sql.NullString{String: dedupeKey, Valid: dedupeKey != ""},
+	})
+
+	if err != nil {
+		log.Println("cannot create security notification:", err)
+	}
+}
+
+func newNotificationResponse(notification db.Notification) notificationResponse {
+	res := notificationResponse {
+		ID: notification.ID.String(),
+		Type: string(notification.Type),
+		Title: notification.Title,
+		Body: notification.Body,
+		CreatedAt: notification.CreatedAt.Format(time.RFC3339),
+	}
+
+	if notification.TaskID.Valid {
+		taskID := notification.TaskID.UUID.String()
+		res.TaskID = &taskID
+	}
+
+	if notification.ReadAt.Valid {
+		readAt := notification.ReadAt.Time.Format(time.RFC3339)
+		res.ReadAt = &readAt
+	}
+
+	return res
+}
+
+// getOwnedNotification loads the notification in the URI and makes sure it belongs to user.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) getOwnedNotification(ctx *gin.Context, user db.User) (notification db.Notification, ok bool) {
+	var uri notificationURIRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	notification, err := server.store.GetNotification(ctx, uuid.MustParse(uri.ID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if notification.UserID != user.ID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errNotificationNotOwned))
+		return
+	}
+
+	return notification, true
+}
+
+// listNotifications returns the inbox of the user, newest first, together
+// with how many notifications are unread in total.
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var req listNotificationsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	pageSize := pageLimit(req.Limit)
+
+	//Fetch one extra row to find out whether there is a next page
+
+	arg := db.ListNotificationsParams {
+		UserID: user.ID,
+		UnreadOnly: req.Unread,
+		PageSize: pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeTimeCursor(req.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.BeforeCreatedAt = sql.NullTime{Time: cursor.At, Valid: true}
+		arg.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	notifications, err := server.store.ListNotifications(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	unread, err := server.store.CountUnreadNotifications(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := listNotificationsResponse {
+		Notifications: []notificationResponse{},
+		UnreadCount: unread,
+	}
+
+	if len(notifications) > int(pageSize) {
+		notifications = notifications[:pageSize]
+		last := notifications[len(notifications)-1]
+		nextCursor := encodeTimeCursor(last.CreatedAt, last.ID)
+		res.NextCursor = &nextCursor
+	}
+
+	for _, notification := range notifications {
+		res.Notifications = append(res.Notifications, newNotificationResponse(notification))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (server *Server) markNotificationRead(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	notification, ok := server.getOwnedNotification(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	notification, err := server.store.MarkNotificationRead(ctx, notification.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newNotificationResponse(notification))
+}
+
+func (server *Server) markAllNotificationsRead(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	marked, err := server.store.MarkAllNotificationsRead(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"marked_read": marked})
+}
+
+func (server *Server) deleteNotification(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	notification, ok := server.getOwnedNotification(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteNotification(ctx, notification.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
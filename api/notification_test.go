@@ -0,0 +1,259 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNotificationsApi(t *testing.T) {
+	user := randomUser()
+
+	now := time.Now().Truncate(time.Second)
+
+	notifications := []db.Notification{
+		randomNotification(user, now),
+		randomNotification(user, now.Add(-time.Minute)),
+	}
+
+	testCases := []struct {
+		name 	string
+		query 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "UnreadPage",
+			query: "?unread=true&limit=1",
+			build: func(store *mockdb.MockStore) {
+				arg := db.ListNotificationsParams {
+					UserID: user.ID,
+					UnreadOnly: true,
+					PageSize: 2,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).Times(1).Return(notifications, nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(7), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listNotificationsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, []notificationResponse{newNotificationResponse(notifications[0])}, res.Notifications)
+				require.Equal(t, int64(7), res.UnreadCount)
+				require.NotNil(t, res.NextCursor)
+				require.Equal(t, encodeTimeCursor(notifications[0].CreatedAt, notifications[0].ID), *res.NextCursor)
+			},
+		},
+		{
+			name: "Empty",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return([]db.Notification{}, nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"notifications":[],"unread_count":0,"next_cursor":null}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "InvalidCursor",
+			query: "?cursor=nope",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodGet, "/notifications"+tc.query, nil)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMarkNotificationReadApi(t *testing.T) {
+	user := randomUser()
+
+	notification := randomNotification(user, time.Now())
+
+	read := notification
+	read.ReadAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	other := randomNotification(randomUser(), time.Now())
+
+	testCases := []struct {
+		name 	string
+		notificationID 	uuid.UUID
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			notificationID: notification.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotification(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(notification, nil)
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(read, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res notificationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotNil(t, res.ReadAt)
+			},
+		},
+		{
+			name: "NotOwned",
+			notificationID: other.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotification(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			notificationID: notification.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotification(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(db.Notification{}, sql.ErrNoRows)
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/notifications/%s/read", tc.notificationID)
+
+			request := httptest.NewRequest(http.MethodPost, url, nil)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMarkAllNotificationsReadApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(3), nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodPost, "/notifications/read_all", nil)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"marked_read":3}`, recorder.Body.String())
+}
+
+func TestDeleteNotificationApi(t *testing.T) {
+	user := randomUser()
+
+	notification := randomNotification(user, time.Now())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetNotification(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(notification, nil)
+	store.EXPECT().DeleteNotification(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodDelete, "/notifications/"+notification.ID.String(), nil)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func randomNotification(user db.User, createdAt time.Time) db.Notification {
+	return db.Notification {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Type: db.NotificationTypeReminder,
+		Title: util.RandomString(8),
+		Body: "Due soon",
+		TaskID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import "encoding/json"
+
+// nullableString lets PATCH handlers tell an absent JSON field apart from
+// one that was explicitly set to null.
+type nullableString struct {
+	Set   bool
+	Valid bool
+	Value string
+}
+
+func (n *nullableString) UnmarshalJSON(data []byte) error {
+	n.Set = true
+
+	if string(data) == "null" {
+		n.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// nullableRecurrence is nullableString for the recurrence object of a task.
+type nullableRecurrence struct {
+	Set   bool
+	Value *recurrenceRequest
+}
+
+func (n *nullableRecurrence) UnmarshalJSON(data []byte) error {
+	n.Set = true
+
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	n.Value = &recurrenceRequest{}
+
+	return json.Unmarshal(data, n.Value)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize = 100
+)
+
+var errInvalidCursor = errors.New("cursor is invalid")
+
+// taskCursor is what an opaque next_cursor decodes to. The sort field is
+// stored too, so a cursor can't be replayed against a differently sorted listing.
+type taskCursor struct {
+	Sort string `json:"s"`
+	Value string `json:"v"`
+	ID uuid.UUID `json:"id"`
+}
+
+func encodeTaskCursor(sortBy db.TaskSortField, task db.Task) string {
+	cursor := taskCursor {
+		Sort: string(sortBy),
+		ID: task.ID,
+	}
+
+	switch sortBy {
+	case db.TaskSortTitle:
+		cursor.Value = task.Title
+	case db.TaskSortCreatedAt:
+		cursor.Value = task.CreatedAt.Format(time.RFC3339Nano)
+	case db.TaskSortUpdatedAt:
+		cursor.Value = task.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = task.DueDate.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTaskCursor(sortBy db.TaskSortField, encoded string) (*db.TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var cursor taskCursor
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != string(sortBy) {
+		return nil, errInvalidCursor
+	}
+
+	if sortBy == db.TaskSortTitle {
+		return &db.TaskCursor{Value: cursor.Value, ID: cursor.ID}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &db.TaskCursor{Value: value, ID: cursor.ID}, nil
+}
+
+// timeCursor is what next_cursor decodes to for listings ordered newest first,
+// by a timestamp with the id breaking ties.
+type timeCursor struct {
+	At time.Time `json:"t"`
+	ID uuid.UUID `json:"id"`
+}
+
+func encodeTimeCursor(at time.Time, id uuid.UUID) string {
+	data, _ := json.Marshal(timeCursor{At: at, ID: id})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTimeCursor(encoded string) (timeCursor, error) {
+	var cursor timeCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.At.IsZero() {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// pageLimit resolves the limit a client asked for.
+func pageLimit(limit int32) int32 {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+
+	return limit
+}
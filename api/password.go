@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPasswordResetDuration = time.Hour
+	//maxPasswordResets is how many reset emails a user gets per passwordResetWindow
+	maxPasswordResets = 3
+	passwordResetWindow = time.Hour
+	mailTimeout = 30 * time.Second
+)
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// forgotPasswordResponse is the same whether or not the email belongs to a
+// user, so the endpoint can't be used to find out who has an account.
+var forgotPasswordResponse = gin.H{"message": "if the email belongs to an account, a link to reset its password was sent to it"}
+
+// forgotPassword emails the user a link to reset their password. Only a hash
+// of the token in the link is stored.
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Email)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusAccepted, forgotPasswordResponse)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	recent, err := server.store.CountPasswordResetsSince(ctx, db.CountPasswordResetsSinceParams {
+		UserID: user.ID,
+		CreatedAt: time.Now().Add(-passwordResetWindow),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//Telling the client it was throttled would tell it the account exists
+
+	if recent >= maxPasswordResets {
+		ctx.JSON(http.StatusAccepted, forgotPasswordResponse)
+		return
+	}
+
+	resetToken, err := util.NewSecretToken()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	duration := server.passwordResetDuration()
+
+	_, err = server.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams {
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(resetToken),
+		ExpiresAt: time.Now().Add(duration),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	msg, err := notify.PasswordResetMessage(user.Email, notify.PasswordResetData {
+		FirstName: user.FirstName,
+		Link: server.appLink("/reset-password", url.Values{"token": {resetToken}}),
+		ExpiresInMinutes: int(duration.Minutes()),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.sendMail(msg)
+
+	ctx.JSON(http.StatusAccepted, forgotPasswordResponse)
+}
+
+// resetPassword sets a new password with the token of a reset link. Every
+// session of the user is signed out, since whoever knew the old password may
+// still be signed in.
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	reset, err := server.store.GetPasswordResetByTokenHash(ctx, util.HashSecretToken(req.Token))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrPasswordResetInvalid))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if reset.UsedAt.Valid || time.Now().After(reset.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrPasswordResetInvalid))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams {
+		ResetID: reset.ID,
+		UserID: reset.UserID,
+		Password: hashedPassword,
+	})
+
+	if err != nil {
+		//Another request redeemed the same token first
+		if err == db.ErrPasswordResetInvalid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//The password is changed already, access tokens that can't be revoked
+	//now still expire soon
+
+	if err := server.revocations.RevokeUser(ctx, reset.UserID); err != nil {
+		log.Println("cannot revoke access tokens:", err)
+	}
+
+	server.notifySecurity(ctx, reset.UserID, "Your password was reset", "Your password was reset with a link sent to your email and all of your devices were signed out. If this wasn't you, reset it again right away.", "password-reset:" + reset.ID.String())
+
+	ctx.JSON(http.StatusOK, gin.H{"signed_out_sessions": result.SignedOutSessions})
+}
+
+func (server *Server) passwordResetDuration() time.Duration {
+	if server.config.PasswordResetDuration <= 0 {
+		return defaultPasswordResetDuration
+	}
+	return server.config.PasswordResetDuration
+}
+
+// appLink is the link to path in the web app, for emails.
+func (server *Server) appLink(path string, query url.Values) string {
+	return server.config.AppURL + path + "?" + query.Encode()
+}
+
+// sendMail sends msg in the background, so how long a request takes doesn't
+// tell whether an email was sent. Without a mailer emails are dropped.
+func (server *Server) sendMail(msg notify.Message) {
+	if server.mailer == nil {
+		log.Printf("no mailer configured, dropping %q to %s", msg.Subject, msg.To)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+
+		if err := server.mailer.Send(ctx, msg); err != nil {
+			log.Printf("cannot send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
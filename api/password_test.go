@@ -0,0 +1,313 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	sent chan notify.Message
+}
+
+func newFakeMailer() *fakeMailer {
+	return &fakeMailer{sent: make(chan notify.Message, 1)}
+}
+
+func (mailer *fakeMailer) Send(ctx context.Context, msg notify.Message) error {
+	mailer.sent <- msg
+	return nil
+}
+
+// expectMail waits for the message that is sent in the background.
+func (mailer *fakeMailer) expectMail(t *testing.T) notify.Message {
+	select {
+	case msg := <-mailer.sent:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail was sent")
+		return notify.Message{}
+	}
+}
+
+func (mailer *fakeMailer) expectNoMail(t *testing.T) {
+	select {
+	case msg := <-mailer.sent:
+		t.Fatalf("unexpected mail %q", msg.Subject)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// linkToken is the token query parameter of the first link in text.
+func linkToken(t *testing.T, text string) string {
+	start := strings.Index(text, "http")
+	require.NotEqual(t, -1, start)
+
+	link, err := url.Parse(strings.Fields(text[start:])[0])
+	require.NoError(t, err)
+
+	return link.Query().Get("token")
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestForgotPasswordApi(t *testing.T) {
+	user := randomUser()
+
+	//tokenHash is what the OK case stored
+	var tokenHash string
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"email": user.Email},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CountPasswordResetsSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePasswordResetParams) (db.PasswordReset, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.WithinDuration(t, time.Now().Add(defaultPasswordResetDuration), arg.ExpiresAt, time.Second)
+						tokenHash = arg.TokenHash
+						return db.PasswordReset{ID: uuid.New(), UserID: arg.UserID, TokenHash: arg.TokenHash, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				msg := mailer.expectMail(t)
+				require.Equal(t, user.Email, msg.To)
+				require.Contains(t, msg.Text, "https://app.example.com/reset-password?token=")
+				//Only the hash of the token in the link is stored
+				resetToken := linkToken(t, msg.Text)
+				require.NotEqual(t, resetToken, tokenHash)
+				require.Equal(t, util.HashSecretToken(resetToken), tokenHash)
+			},
+		},
+		{
+			name: "UnknownEmail",
+			body: gin.H{"email": user.Email},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.JSONEq(t, mustMarshal(t, forgotPasswordResponse), recorder.Body.String())
+				mailer.expectNoMail(t)
+			},
+		},
+		{
+			name: "Throttled",
+			body: gin.H{"email": user.Email},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CountPasswordResetsSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(maxPasswordResets), nil)
+				store.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.JSONEq(t, mustMarshal(t, forgotPasswordResponse), recorder.Body.String())
+				mailer.expectNoMail(t)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"email": "not-an-email"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+			server.config.AppURL = "https://app.example.com"
+
+			mailer := newFakeMailer()
+			server.mailer = mailer
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewReader(data))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder, mailer)
+		})
+	}
+}
+
+func TestResetPasswordApi(t *testing.T) {
+	user := randomUser()
+
+	resetToken, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	reset := db.PasswordReset {
+		ID: uuid.New(),
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(resetToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	password := util.RandomString(8)
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"token": resetToken, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Eq(reset.TokenHash)).Times(1).Return(reset, nil)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+						require.Equal(t, reset.ID, arg.ResetID)
+						require.Equal(t, user.ID, arg.UserID)
+						require.NoError(t, util.ComparePassword(arg.Password, password))
+						return db.ResetPasswordTxResult{User: user, SignedOutSessions: 2}, nil
+					})
+				store.EXPECT().RevokeUserAccessTokens(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.RevokeUserAccessTokensRow{}, nil)
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"signed_out_sessions":2}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"token": "unknown", "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Any()).Times(1).Return(db.PasswordReset{}, sql.ErrNoRows)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Used",
+			body: gin.H{"token": resetToken, "password": password},
+			build: func(store *mockdb.MockStore) {
+				used := reset
+				used.UsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Any()).Times(1).Return(used, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{"token": resetToken, "password": password},
+			build: func(store *mockdb.MockStore) {
+				expired := reset
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Any()).Times(1).Return(expired, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "RedeemedConcurrently",
+			body: gin.H{"token": resetToken, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Any()).Times(1).Return(reset, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ResetPasswordTxResult{}, db.ErrPasswordResetInvalid)
+				store.EXPECT().RevokeUserAccessTokens(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ShortPassword",
+			body: gin.H{"token": resetToken, "password": "abc"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPasswordResetByTokenHash(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewReader(data))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
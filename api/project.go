@@ -0,0 +1,382 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultProjectColor = "#808080"
+
+type projectResponse struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+	Color string `json:"color"`
+	Icon string `json:"icon"`
+	Archived bool `json:"archived"`
+	SortOrder int32 `json:"sort_order"`
+	OpenCount int64 `json:"open_count"`
+	DoneCount int64 `json:"done_count"`
+}
+
+type createProjectRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+	Icon *string `json:"icon" binding:"omitempty,max=50"`
+	SortOrder *int32 `json:"sort_order"`
+}
+
+type updateProjectRequest struct {
+	Name *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Color *string `json:"color" binding:"omitempty,hexcolor"`
+	Icon nullableString `json:"icon"`
+	SortOrder *int32 `json:"sort_order"`
+}
+
+type listProjectsRequest struct {
+	IncludeArchived bool `form:"include_archived"`
+}
+
+type projectURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+var (
+	errProjectNotOwned = errors.New("project doesn't belong to the authenticated user")
+	errProjectArchived = errors.New("tasks can't be added to an archived project")
+)
+
+func newProjectResponse(project db.Project) projectResponse {
+	return projectResponse {
+		ID: project.ID.String(),
+		Name: project.Name,
+		Color: project.Color,
+		Icon: project.Icon.String,
+		Archived: project.Archived,
+		SortOrder: project.SortOrder,
+	}
+}
+
+// projectResponses builds the responses for projects, counting their tasks with a single query.
+func (server *Server) projectResponses(ctx *gin.Context, user db.User, projects []db.Project) ([]projectResponse, error) {
+	counts, err := server.store.GetProjectTaskCounts(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	countsByProject := make(map[uuid.UUID]db.GetProjectTaskCountsRow, len(counts))
+	for _, count := range counts {
+		countsByProject[count.ProjectID] = count
+	}
+
+	res := []projectResponse{}
+
+	for _, project := range projects {
+		projectRes := newProjectResponse(project)
+		projectRes.OpenCount = countsByProject[project.ID].OpenCount
+		projectRes.DoneCount = countsByProject[project.ID].DoneCount
+		res = append(res, projectRes)
+	}
+
+	return res, nil
+}
+
+// respondWithProject writes a single project, including its task counts, as the response.
+func (server *Server) respondWithProject(ctx *gin.Context, user db.User, project db.Project) {
+	res, err := server.projectResponses(ctx, user, []db.Project{project})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res[0])
+}
+
+// getOwnedProject loads a project and makes sure it belongs to user.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) getOwnedProject(ctx *gin.Context, user db.User, projectID uuid.UUID) (project db.Project, ok bool) {
+	project, err := server.store.GetProjectByID(ctx, projectID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if project.UserID != user.ID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errProjectNotOwned))
+		return
+	}
+
+	return project, true
+}
+
+// getProjectFromURI is getOwnedProject for the project in the URI.
+func (server *Server) getProjectFromURI(ctx *gin.Context, user db.User) (project db.Project, ok bool) {
+	var uri projectURIRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	return server.getOwnedProject(ctx, user, uuid.MustParse(uri.ID))
+}
+
+// taskProject resolves the project a task is being moved into. An empty id
+// means the task doesn't belong to any project.
+func (server *Server) taskProject(ctx *gin.Context, user db.User, id string) (projectID uuid.NullUUID, ok bool) {
+	if id == "" {
+		return projectID, true
+	}
+
+	parsed, err := uuid.Parse(id)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	project, ok := server.getOwnedProject(ctx, user, parsed)
+
+	if !ok {
+		return
+	}
+
+	if project.Archived {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errProjectArchived))
+		return projectID, false
+	}
+
+	return uuid.NullUUID{UUID: project.ID, Valid: true}, true
+}
+
+func (server *Server) createProject(ctx *gin.Context) {
+	var req createProjectRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.CreateProjectParams {
+		UserID: user.ID,
+		Name: req.Name,
+		Color: defaultProjectColor,
+	}
+
+	if req.Color != "" {
+		arg.Color = req.Color
+	}
+
+	if req.Icon != nil {
+		arg.Icon = sql.NullString{String: *req.Icon, Valid: true}
+	}
+
+	//New projects go to the end of the sidebar unless a position is given
+
+	if req.SortOrder != nil {
+		arg.SortOrder = sql.NullInt32{Int32: *req.SortOrder, Valid: true}
+	}
+
+	project, err := server.store.CreateProject(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newProjectResponse(project))
+}
+
+func (server *Server) listProjects(ctx *gin.Context) {
+	var req listProjectsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.ListProjectsByUserParams {
+		UserID: user.ID,
+		IncludeArchived: req.IncludeArchived,
+	}
+
+	projects, err := server.store.ListProjectsByUser(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res, err := server.projectResponses(ctx, user, projects)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (server *Server) getProject(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	project, ok := server.getProjectFromURI(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	server.respondWithProject(ctx, user, project)
+}
+
+func (server *Server) updateProject(ctx *gin.Context) {
+	var req updateProjectRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	project, ok := server.getProjectFromURI(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateProjectParams {
+		ID: project.ID,
+	}
+
+	if req.Name != nil {
+		arg.Name = sql.NullString{String: *req.Name, Valid: true}
+	}
+
+	if req.Color != nil {
+		arg.Color = sql.NullString{String: *req.Color, Valid: true}
+	}
+
+	if req.Icon.Set {
+		arg.SetIcon = true
+		arg.Icon = sql.NullString{String: req.Icon.Value, Valid: req.Icon.Valid}
+	}
+
+	if req.SortOrder != nil {
+		arg.SortOrder = sql.NullInt32{Int32: *req.SortOrder, Valid: true}
+	}
+
+	project, err := server.store.UpdateProject(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondWithProject(ctx, user, project)
+}
+
+func (server *Server) archiveProject(ctx *gin.Context) {
+	server.setProjectArchived(ctx, true)
+}
+
+func (server *Server) unarchiveProject(ctx *gin.Context) {
+	server.setProjectArchived(ctx, false)
+}
+
+func (server *Server) setProjectArchived(ctx *gin.Context, archived bool) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	project, ok := server.getProjectFromURI(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	arg := db.SetProjectArchivedParams {
+		ID: project.ID,
+		Archived: archived,
+	}
+
+	project, err := server.store.SetProjectArchived(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondWithProject(ctx, user, project)
+}
+
+// deleteProject removes a project. Its tasks are kept and no longer belong to any project.
+func (server *Server) deleteProject(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	project, ok := server.getProjectFromURI(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteProject(ctx, project.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newProjectResponse(project))
+}
+
+func (server *Server) getProjectTasks(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	project, ok := server.getProjectFromURI(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	server.listTasksOfUser(ctx, user, uuid.NullUUID{UUID: project.ID, Valid: true})
+}
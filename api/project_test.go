@@ -0,0 +1,325 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProjectApi(t *testing.T) {
+	user := randomUser()
+
+	project := randomProject(user)
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H {
+				"name": project.Name,
+				"color": project.Color,
+				"icon": project.Icon.String,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateProjectParams {
+					UserID: user.ID,
+					Name: project.Name,
+					Color: project.Color,
+					Icon: project.Icon,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateProject(gomock.Any(), gomock.Eq(arg)).Times(1).Return(project, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res projectResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, newProjectResponse(project), res)
+			},
+		},
+		{
+			name: "ExplicitSortOrder",
+			body: gin.H {
+				"name": project.Name,
+				"sort_order": 0,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateProjectParams {
+					UserID: user.ID,
+					Name: project.Name,
+					Color: defaultProjectColor,
+					SortOrder: sql.NullInt32{Int32: 0, Valid: true},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateProject(gomock.Any(), gomock.Eq(arg)).Times(1).Return(project, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingName",
+			body: gin.H {
+				"color": project.Color,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateProject(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/projects", bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListProjectsApi(t *testing.T) {
+	user := randomUser()
+
+	projects := []db.Project{randomProject(user), randomProject(user)}
+
+	counts := []db.GetProjectTaskCountsRow{
+		{ProjectID: projects[1].ID, OpenCount: 3, DoneCount: 2},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	arg := db.ListProjectsByUserParams {
+		UserID: user.ID,
+		IncludeArchived: true,
+	}
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().ListProjectsByUser(gomock.Any(), gomock.Eq(arg)).Times(1).Return(projects, nil)
+	store.EXPECT().GetProjectTaskCounts(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(counts, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/projects?include_archived=true", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []projectResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res, 2)
+
+	require.Zero(t, res[0].OpenCount)
+	require.Zero(t, res[0].DoneCount)
+	require.Equal(t, int64(3), res[1].OpenCount)
+	require.Equal(t, int64(2), res[1].DoneCount)
+}
+
+func TestArchiveProjectApi(t *testing.T) {
+	user := randomUser()
+
+	project := randomProject(user)
+
+	otherProject := randomProject(randomUser())
+
+	testCases := []struct {
+		name 	string
+		projectID 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			projectID: project.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				arg := db.SetProjectArchivedParams {
+					ID: project.ID,
+					Archived: true,
+				}
+
+				archived := project
+				archived.Archived = true
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetProjectByID(gomock.Any(), gomock.Eq(project.ID)).Times(1).Return(project, nil)
+				store.EXPECT().SetProjectArchived(gomock.Any(), gomock.Eq(arg)).Times(1).Return(archived, nil)
+				store.EXPECT().GetProjectTaskCounts(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.GetProjectTaskCountsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res projectResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, res.Archived)
+			},
+		},
+		{
+			name: "Forbidden",
+			projectID: otherProject.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetProjectByID(gomock.Any(), gomock.Eq(otherProject.ID)).Times(1).Return(otherProject, nil)
+				store.EXPECT().SetProjectArchived(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			projectID: project.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetProjectByID(gomock.Any(), gomock.Eq(project.ID)).Times(1).Return(db.Project{}, sql.ErrNoRows)
+				store.EXPECT().SetProjectArchived(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/projects/" + tc.projectID + "/archive"
+
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetProjectTasksApi(t *testing.T) {
+	user := randomUser()
+
+	project := randomProject(user)
+
+	task := randomTask(user)
+	task.ProjectID = uuid.NullUUID{UUID: project.ID, Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetProjectByID(gomock.Any(), gomock.Eq(project.ID)).Times(1).Return(project, nil)
+	store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+			require.Equal(t, uuid.NullUUID{UUID: project.ID, Valid: true}, got.ProjectID)
+			return []db.Task{task}, nil
+		})
+	store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+	store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+	store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/projects/" + project.ID.String() + "/tasks", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res listTasksResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res.Tasks, 1)
+	require.NotNil(t, res.Tasks[0].ProjectID)
+	require.Equal(t, project.ID.String(), *res.Tasks[0].ProjectID)
+}
+
+func randomProject(user db.User) db.Project {
+	return db.Project {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Name: util.RandomString(6),
+		Color: "#3366ff",
+		Icon: sql.NullString{String: "inbox", Valid: true},
+		SortOrder: int32(util.RandomInt(0, 10)),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	//maxDoNotDisturb is how far into the future do-not-disturb can be set
+	maxDoNotDisturb = 365 * 24 * time.Hour
+	clockLayout = "15:04"
+)
+
+type quietWindowRequest struct {
+	//Weekday is the day the window starts on, 0 is Sunday
+	Weekday *int32 `json:"weekday" binding:"required,min=0,max=6"`
+	Start string `json:"start" binding:"required"`
+	//End may be before Start, the window then runs into the next day
+	End string `json:"end" binding:"required"`
+}
+
+type replaceQuietHoursRequest struct {
+	Windows []quietWindowRequest `json:"windows" binding:"required,max=50,dive"`
+}
+
+type doNotDisturbRequest struct {
+	Until string `json:"until" binding:"required"`
+}
+
+type resolveDeliveryRequest struct {
+	At string `form:"at" binding:"required"`
+	TaskID string `form:"task_id" binding:"omitempty,uuid"`
+}
+
+type quietWindowResponse struct {
+	Weekday int32 `json:"weekday"`
+	Start string `json:"start"`
+	End string `json:"end"`
+}
+
+type quietHoursResponse struct {
+	Timezone string `json:"timezone"`
+	Windows []quietWindowResponse `json:"windows"`
+	DndUntil *string `json:"dnd_until"`
+}
+
+type resolveDeliveryResponse struct {
+	RequestedAt string `json:"requested_at"`
+	DeliverAt string `json:"deliver_at"`
+	Deferred bool `json:"deferred"`
+	BreakThrough bool `json:"break_through"`
+}
+
+var (
+	errEmptyQuietWindow = errors.New("a quiet hours window can't start and end at the same time")
+	errDoNotDisturbInPast = errors.New("do-not-disturb can only be set until a time in the future")
+	errDoNotDisturbTooFar = errors.New("do-not-disturb can't be set for more than a year")
+)
+
+func newQuietHoursResponse(user db.User, windows []db.QuietHour) quietHoursResponse {
+	res := quietHoursResponse {
+		Timezone: user.Location().String(),
+		Windows: []quietWindowResponse{},
+	}
+
+	for _, window := range windows {
+		res.Windows = append(res.Windows, quietWindowResponse {
+			Weekday: window.Weekday,
+			Start: formatClock(window.StartMinute),
+			End: formatClock(window.EndMinute),
+		})
+	}
+
+	if user.DndUntil.Valid {
+		dndUntil := user.DndUntil.Time.Format(time.RFC3339)
+		res.DndUntil = &dndUntil
+	}
+
+	return res
+}
+
+// parseClock turns a local time of day like 22:30 into minutes after midnight.
+func parseClock(value string) (int32, error) {
+	clock, err := time.Parse(clockLayout, value)
+
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a time of day like 22:30", value)
+	}
+
+	return int32(clock.Hour() * 60 + clock.Minute()), nil
+}
+
+func formatClock(minutes int32) string {
+	return fmt.Sprintf("%02d:%02d", minutes / 60, minutes % 60)
+}
+
+func parseQuietWindows(reqs []quietWindowRequest) ([]db.QuietHour, error) {
+	windows := []db.QuietHour{}
+
+	for _, req := range reqs {
+		start, err := parseClock(req.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := parseClock(req.End)
+		if err != nil {
+			return nil, err
+		}
+
+		if start == end {
+			return nil, errEmptyQuietWindow
+		}
+
+		windows = append(windows, db.QuietHour{Weekday: *req.Weekday, StartMinute: start, EndMinute: end})
+	}
+
+	return windows, nil
+}
+
+// respondQuietHours writes the quiet hours of user, loading their windows.
+func (server *Server) respondQuietHours(ctx *gin.Context, user db.User) {
+	windows, err := server.store.ListQuietHours(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newQuietHoursResponse(user, windows))
+}
+
+func (server *Server) getQuietHours(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	server.respondQuietHours(ctx, user)
+}
+
+// replaceQuietHours sets the weekly windows, in the timezone of the user,
+// during which reminders are held back.
+func (server *Server) replaceQuietHours(ctx *gin.Context) {
+	var req replaceQuietHoursRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	windows, err := parseQuietWindows(req.Windows)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	windows, err = server.store.ReplaceQuietHoursTx(ctx, db.ReplaceQuietHoursTxParams {
+		UserID: user.ID,
+		Windows: windows,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newQuietHoursResponse(user, windows))
+}
+
+// setDoNotDisturb holds back every reminder of the user until the given time.
+func (server *Server) setDoNotDisturb(ctx *gin.Context) {
+	var req doNotDisturbRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	until, err := time.Parse(time.RFC3339, req.Until)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+
+	if !until.After(now) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errDoNotDisturbInPast))
+		return
+	}
+
+	if until.Sub(now) > maxDoNotDisturb {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errDoNotDisturbTooFar))
+		return
+	}
+
+	server.updateDoNotDisturb(ctx, sql.NullTime{Time: until, Valid: true})
+}
+
+func (server *Server) clearDoNotDisturb(ctx *gin.Context) {
+	server.updateDoNotDisturb(ctx, sql.NullTime{})
+}
+
+func (server *Server) updateDoNotDisturb(ctx *gin.Context, until sql.NullTime) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	user, err := server.store.SetDoNotDisturb(ctx, db.SetDoNotDisturbParams {
+		ID: user.ID,
+		DndUntil: until,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondQuietHours(ctx, user)
+}
+
+// resolveDelivery tells when a reminder due at the given time would actually be
+// delivered, after the quiet hours and do-not-disturb of the user. A task that
+// breaks through quiet hours is delivered on time.
+func (server *Server) resolveDelivery(ctx *gin.Context) {
+	var req resolveDeliveryRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, req.At)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	res := resolveDeliveryResponse {
+		RequestedAt: at.Format(time.RFC3339),
+		DeliverAt: at.Format(time.RFC3339),
+	}
+
+	if req.TaskID != "" {
+		task, ok := server.getOwnedTask(ctx, user, uuid.MustParse(req.TaskID))
+
+		if !ok {
+			return
+		}
+
+		if task.BreakThroughQuietHours {
+			res.BreakThrough = true
+			ctx.JSON(http.StatusOK, res)
+			return
+		}
+	}
+
+	windows, err := server.store.ListQuietHours(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	deliverAt := user.QuietHours(windows).DeliveryTime(at)
+
+	res.DeliverAt = deliverAt.Format(time.RFC3339)
+	res.Deferred = deliverAt.After(at)
+
+	ctx.JSON(http.StatusOK, res)
+}
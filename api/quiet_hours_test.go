@@ -0,0 +1,315 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceQuietHoursApi(t *testing.T) {
+	user := randomUser()
+	user.Timezone = "Europe/Skopje"
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"windows": []gin.H{
+				{"weekday": 0, "start": "22:00", "end": "07:30"},
+				{"weekday": 6, "start": "13:00", "end": "15:00"},
+			}},
+			build: func(store *mockdb.MockStore) {
+				arg := db.ReplaceQuietHoursTxParams {
+					UserID: user.ID,
+					Windows: []db.QuietHour{
+						{Weekday: 0, StartMinute: 22 * 60, EndMinute: 7 * 60 + 30},
+						{Weekday: 6, StartMinute: 13 * 60, EndMinute: 15 * 60},
+					},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ReplaceQuietHoursTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(arg.Windows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{
+					"timezone": "Europe/Skopje",
+					"windows": [
+						{"weekday": 0, "start": "22:00", "end": "07:30"},
+						{"weekday": 6, "start": "13:00", "end": "15:00"}
+					],
+					"dnd_until": null
+				}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "Clear",
+			body: gin.H{"windows": []gin.H{}},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ReplaceQuietHoursTx(gomock.Any(), gomock.Eq(db.ReplaceQuietHoursTxParams{UserID: user.ID, Windows: []db.QuietHour{}})).
+					Times(1).
+					Return([]db.QuietHour{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidClock",
+			body: gin.H{"windows": []gin.H{{"weekday": 1, "start": "25:00", "end": "07:00"}}},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ReplaceQuietHoursTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyWindow",
+			body: gin.H{"windows": []gin.H{{"weekday": 1, "start": "07:00", "end": "07:00"}}},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ReplaceQuietHoursTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingWeekday",
+			body: gin.H{"windows": []gin.H{{"start": "22:00", "end": "07:00"}}},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ReplaceQuietHoursTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/quiet_hours", bytes.NewReader(data))
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetDoNotDisturbApi(t *testing.T) {
+	user := randomUser()
+
+	until := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name string
+		until time.Time
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			until: until,
+			build: func(store *mockdb.MockStore) {
+				updated := user
+				updated.DndUntil = sql.NullTime{Time: until, Valid: true}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().SetDoNotDisturb(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SetDoNotDisturbParams) (db.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.True(t, arg.DndUntil.Time.Equal(until))
+						return updated, nil
+					})
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res quietHoursResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotNil(t, res.DndUntil)
+				require.Equal(t, until.Format(time.RFC3339), *res.DndUntil)
+			},
+		},
+		{
+			name: "InPast",
+			until: time.Now().Add(-time.Minute),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().SetDoNotDisturb(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"until": tc.until.Format(time.RFC3339)})
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/quiet_hours/dnd", bytes.NewReader(data))
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResolveDeliveryApi(t *testing.T) {
+	user := randomUser()
+	user.Timezone = "America/New_York"
+
+	task := randomTask(user)
+
+	breakThrough := randomTask(user)
+	breakThrough.BreakThroughQuietHours = true
+
+	//22:00-07:00 starting on Tuesday, New York time
+	windows := []db.QuietHour{{ID: uuid.New(), UserID: user.ID, Weekday: 2, StartMinute: 22 * 60, EndMinute: 7 * 60}}
+
+	//Wednesday 03:00 in New York
+	at := "2024-05-15T07:00:00Z"
+
+	testCases := []struct {
+		name string
+		taskID string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Deferred",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(windows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{
+					"requested_at": "2024-05-15T07:00:00Z",
+					"deliver_at": "2024-05-15T07:00:00-04:00",
+					"deferred": true,
+					"break_through": false
+				}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "Task",
+			taskID: task.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(windows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res resolveDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, res.Deferred)
+			},
+		},
+		{
+			name: "BreakThrough",
+			taskID: breakThrough.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(breakThrough.ID)).Times(1).Return(breakThrough, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res resolveDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.False(t, res.Deferred)
+				require.True(t, res.BreakThrough)
+				require.Equal(t, at, res.DeliverAt)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			query := url.Values{"at": {at}}
+			if tc.taskID != "" {
+				query.Set("task_id", tc.taskID)
+			}
+
+			request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/quiet_hours/resolve?%s", query.Encode()), nil)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultOccurrenceWindow = 90 * 24 * time.Hour
+	maxOccurrenceWindow = 366 * 24 * time.Hour
+	defaultOccurrenceLimit = 100
+	maxOccurrenceLimit = 500
+)
+
+type recurrenceRequest struct {
+	//Rule is an RFC 5545 RRULE such as FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR
+	Rule string `json:"rule" binding:"required"`
+	//Start is the DTSTART of the rule and defaults to the due date of the task
+	Start *string `json:"start"`
+	//Timezone is the IANA zone occurrences are computed in and defaults to UTC
+	Timezone string `json:"timezone"`
+	ExDates []string `json:"exdates"`
+}
+
+type recurrenceResponse struct {
+	Rule string `json:"rule"`
+	Start string `json:"start"`
+	Timezone string `json:"timezone"`
+	ExDates []string `json:"exdates"`
+}
+
+type listOccurrencesRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit int `form:"limit" binding:"omitempty,min=1"`
+}
+
+type listOccurrencesResponse struct {
+	Occurrences []string `json:"occurrences"`
+}
+
+var errInvalidOccurrenceWindow = errors.New("to must be after from and at most a year later")
+
+func newRecurrenceResponse(task db.Task) *recurrenceResponse {
+	if !task.RecurrenceRule.Valid {
+		return nil
+	}
+
+	recurrence, err := task.Recurrence()
+
+	if err != nil {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(recurrence.Timezone)
+
+	if err != nil {
+		loc = time.UTC
+	}
+
+	res := &recurrenceResponse {
+		Rule: recurrence.Rule,
+		Start: recurrence.Start.In(loc).Format(time.RFC3339),
+		Timezone: loc.String(),
+		ExDates: []string{},
+	}
+
+	for _, exdate := range recurrence.ExDates {
+		res.ExDates = append(res.ExDates, exdate.In(loc).Format(time.RFC3339))
+	}
+
+	return res
+}
+
+// parseRecurrence validates a recurrence sent by the client for a task due at due.
+func parseRecurrence(req *recurrenceRequest, due time.Time) (util.Recurrence, error) {
+	recurrence := util.Recurrence {
+		Rule: req.Rule,
+		Start: due,
+		Timezone: req.Timezone,
+	}
+
+	if recurrence.Timezone == "" {
+		recurrence.Timezone = "UTC"
+	}
+
+	if req.Start != nil {
+		start, err := time.Parse(time.RFC3339, *req.Start)
+		if err != nil {
+			return recurrence, err
+		}
+		recurrence.Start = start
+	}
+
+	for _, value := range req.ExDates {
+		exdate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return recurrence, err
+		}
+		recurrence.ExDates = append(recurrence.ExDates, exdate)
+	}
+
+	return recurrence, recurrence.Validate()
+}
+
+// recurrenceColumns splits a recurrence into the nullable columns of tasks.
+// A nil recurrence clears them.
+func recurrenceColumns(recurrence *util.Recurrence) (rule sql.NullString, start sql.NullTime, timezone sql.NullString, exdates sql.NullString) {
+	if recurrence == nil {
+		return
+	}
+
+	rule = sql.NullString{String: recurrence.Rule, Valid: true}
+	start = sql.NullTime{Time: recurrence.Start, Valid: true}
+	timezone = sql.NullString{String: recurrence.Timezone, Valid: true}
+	exdates = sql.NullString{String: util.FormatExDates(recurrence.ExDates), Valid: len(recurrence.ExDates) > 0}
+
+	return
+}
+
+// listTaskOccurrences expands the occurrences of a task within a date window.
+// Tasks that don't repeat have a single occurrence on their due date.
+func (server *Server) listTaskOccurrences(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listOccurrencesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.From.IsZero() {
+		req.From = time.Now()
+	}
+
+	if req.To.IsZero() {
+		req.To = req.From.Add(defaultOccurrenceWindow)
+	}
+
+	if !req.To.After(req.From) || req.To.Sub(req.From) > maxOccurrenceWindow {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidOccurrenceWindow))
+		return
+	}
+
+	limit := defaultOccurrenceLimit
+
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	if limit > maxOccurrenceLimit {
+		limit = maxOccurrenceLimit
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	res := listOccurrencesResponse {
+		Occurrences: []string{},
+	}
+
+	if !task.RecurrenceRule.Valid {
+		if !task.DueDate.Before(req.From) && task.DueDate.Before(req.To) {
+			res.Occurrences = append(res.Occurrences, task.DueDate.Format(time.RFC3339))
+		}
+
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
+	recurrence, err := task.Recurrence()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	occurrences, err := recurrence.Between(req.From, req.To, limit)
+
+	if err != nil {
+		//The window is too far past the start of the rule to expand
+		if errors.Is(err, util.ErrRecurrenceTooLong) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for _, occurrence := range occurrences {
+		res.Occurrences = append(res.Occurrences, occurrence.Format(time.RFC3339))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTaskOccurrencesApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	recurring := randomTask(user)
+	recurring.RecurrenceRule = sql.NullString{String: "FREQ=DAILY", Valid: true}
+	recurring.RecurrenceStart = sql.NullTime{Time: time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC), Valid: true}
+	recurring.RecurrenceTimezone = sql.NullString{String: "Europe/Berlin", Valid: true}
+	recurring.RecurrenceExdates = sql.NullString{String: "20240330T080000Z", Valid: true}
+
+	otherTask := randomTask(randomUser())
+
+	testCases := []struct {
+		name 	string
+		taskID 	string
+		query 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Recurring",
+			taskID: recurring.ID.String(),
+			query: "?from=2024-03-29T00:00:00Z&to=2024-04-02T00:00:00Z",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(recurring.ID)).Times(1).Return(recurring, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listOccurrencesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+
+				//The exdate drops the 30th and the wall clock time survives the DST change
+				require.Equal(t, []string{
+					"2024-03-29T09:00:00+01:00",
+					"2024-03-31T09:00:00+02:00",
+					"2024-04-01T09:00:00+02:00",
+				}, res.Occurrences)
+			},
+		},
+		{
+			name: "Limit",
+			taskID: recurring.ID.String(),
+			query: "?from=2024-03-29T00:00:00Z&to=2024-04-02T00:00:00Z&limit=1",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(recurring.ID)).Times(1).Return(recurring, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listOccurrencesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Occurrences, 1)
+			},
+		},
+		{
+			name: "NotRecurring",
+			taskID: task.ID.String(),
+			query: "?from=2021-07-01T00:00:00Z&to=2021-08-01T00:00:00Z",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listOccurrencesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, []string{task.DueDate.Format(time.RFC3339)}, res.Occurrences)
+			},
+		},
+		{
+			name: "InvalidWindow",
+			taskID: recurring.ID.String(),
+			query: "?from=2024-03-29T00:00:00Z&to=2026-03-29T00:00:00Z",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooFarPastStart",
+			taskID: recurring.ID.String(),
+			query: "?from=2300-01-01T00:00:00Z&to=2300-02-01T00:00:00Z",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(recurring.ID)).Times(1).Return(recurring, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwned",
+			taskID: otherTask.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).Times(1).Return(otherTask, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/tasks/" + tc.taskID + "/occurrences" + tc.query
+
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxTaskReminders caps how many reminders a single task can have.
+const maxTaskReminders = 10
+
+type reminderRequest struct {
+	//RemindAt makes an absolute reminder that fires at a fixed time
+	RemindAt *string `json:"remind_at"`
+	//MinutesBefore makes a relative reminder that follows the due date of the task
+	MinutesBefore *int32 `json:"minutes_before" binding:"omitempty,min=0,max=525600"`
+}
+
+type reminderResponse struct {
+	ID string `json:"id"`
+	RemindAt string `json:"remind_at"`
+	MinutesBefore *int32 `json:"minutes_before"`
+	//OriginalRemindAt is when the reminder was due before it was first snoozed
+	OriginalRemindAt *string `json:"original_remind_at"`
+	SnoozeCount int32 `json:"snooze_count"`
+	DismissedAt *string `json:"dismissed_at"`
+}
+
+type listRemindersResponse struct {
+	Reminders []reminderResponse `json:"reminders"`
+}
+
+type taskReminderURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+	ReminderID string `uri:"reminder_id" binding:"required,uuid"`
+}
+
+type defaultRemindersRequest struct {
+	MinutesBefore []int32 `json:"minutes_before" binding:"required,max=10,dive,min=0,max=525600"`
+}
+
+type defaultRemindersResponse struct {
+	MinutesBefore []int32 `json:"minutes_before"`
+}
+
+var (
+	errInvalidReminder = errors.New("a reminder needs exactly one of remind_at or minutes_before")
+	errTooManyReminders = fmt.Errorf("a task can't have more than %d reminders", maxTaskReminders)
+	errConflictingReminders = errors.New("reminder_date and reminders can't be used together")
+	errReminderNotFound = errors.New("reminder not found")
+)
+
+func newReminderResponse(reminder db.TaskReminder) reminderResponse {
+	res := reminderResponse {
+		ID: reminder.ID.String(),
+		RemindAt: reminder.RemindAt.Format(time.RFC3339),
+		SnoozeCount: reminder.SnoozeCount,
+	}
+
+	if reminder.IsRelative() {
+		minutes := reminder.OffsetSeconds.Int32 / 60
+		res.MinutesBefore = &minutes
+	}
+
+	if reminder.OriginalRemindAt.Valid {
+		original := reminder.OriginalRemindAt.Time.Format(time.RFC3339)
+		res.OriginalRemindAt = &original
+	}
+
+	if reminder.DismissedAt.Valid {
+		dismissed := reminder.DismissedAt.Time.Format(time.RFC3339)
+		res.DismissedAt = &dismissed
+	}
+
+	return res
+}
+
+func newReminderResponses(reminders []db.TaskReminder) []reminderResponse {
+	res := []reminderResponse{}
+
+	for _, reminder := range reminders {
+		res = append(res, newReminderResponse(reminder))
+	}
+
+	return res
+}
+
+// parseReminder validates a reminder sent by the client for a task due at due.
+func parseReminder(req reminderRequest, due time.Time) (db.ReminderSpec, error) {
+	if (req.RemindAt == nil) == (req.MinutesBefore == nil) {
+		return db.ReminderSpec{}, errInvalidReminder
+	}
+
+	if req.MinutesBefore != nil {
+		return db.ReminderSpec{OffsetSeconds: sql.NullInt32{Int32: *req.MinutesBefore * 60, Valid: true}}, nil
+	}
+
+	return parseReminderDate(*req.RemindAt, due)
+}
+
+func parseReminders(reqs []reminderRequest, due time.Time) ([]db.ReminderSpec, error) {
+	if len(reqs) > maxTaskReminders {
+		return nil, errTooManyReminders
+	}
+
+	reminders := []db.ReminderSpec{}
+
+	for _, req := range reqs {
+		reminder, err := parseReminder(req, due)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// parseReminderDate turns the reminder_date of a task into an absolute reminder.
+func parseReminderDate(value string, due time.Time) (db.ReminderSpec, error) {
+	remindAt, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return db.ReminderSpec{}, err
+	}
+
+	if !isValidReminder(remindAt, due) {
+		return db.ReminderSpec{}, errReminderAfterDue
+	}
+
+	return db.ReminderSpec{RemindAt: remindAt}, nil
+}
+
+// defaultReminders returns the reminders createTask gives a task that was
+// created without any. Users who haven't picked defaults are reminded on the due date.
+func (server *Server) defaultReminders(ctx *gin.Context, user db.User) ([]db.ReminderSpec, error) {
+	defaults, err := server.store.ListDefaultReminders(ctx, user.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(defaults) == 0 {
+		return []db.ReminderSpec{{OffsetSeconds: sql.NullInt32{Int32: 0, Valid: true}}}, nil
+	}
+
+	reminders := make([]db.ReminderSpec, len(defaults))
+	for i, reminder := range defaults {
+		reminders[i] = db.ReminderSpec{OffsetSeconds: sql.NullInt32{Int32: reminder.OffsetSeconds, Valid: true}}
+	}
+
+	return reminders, nil
+}
+
+func (server *Server) listTaskReminders(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	reminders, err := server.store.ListTaskReminders(ctx, task.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listRemindersResponse{Reminders: newReminderResponses(reminders)})
+}
+
+func (server *Server) addTaskReminder(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req reminderRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	spec, err := parseReminder(req, task.DueDate)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	existing, err := server.store.ListTaskReminders(ctx, task.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if len(existing) >= maxTaskReminders {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTooManyReminders))
+		return
+	}
+
+	reminder, err := server.store.CreateTaskReminder(ctx, spec.Params(task))
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReminderResponse(reminder))
+}
+
+func (server *Server) deleteTaskReminder(ctx *gin.Context) {
+	var uri taskReminderURIRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, uuid.MustParse(uri.ID))
+
+	if !ok {
+		return
+	}
+
+	reminder, err := server.store.GetTaskReminderByID(ctx, uuid.MustParse(uri.ReminderID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(errReminderNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if reminder.TaskID != task.ID {
+		ctx.JSON(http.StatusNotFound, errorResponse(errReminderNotFound))
+		return
+	}
+
+	if err := server.store.DeleteTaskReminder(ctx, reminder.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (server *Server) getDefaultReminders(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	defaults, err := server.store.ListDefaultReminders(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDefaultRemindersResponse(defaults))
+}
+
+// replaceDefaultReminders sets the reminders, in minutes before the due date,
+// that tasks created without reminders get.
+func (server *Server) replaceDefaultReminders(ctx *gin.Context) {
+	var req defaultRemindersRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.ReplaceDefaultRemindersTxParams {
+		UserID: user.ID,
+		OffsetSeconds: make([]int32, len(req.MinutesBefore)),
+	}
+
+	for i, minutes := range req.MinutesBefore {
+		arg.OffsetSeconds[i] = minutes * 60
+	}
+
+	defaults, err := server.store.ReplaceDefaultRemindersTx(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDefaultRemindersResponse(defaults))
+}
+
+func newDefaultRemindersResponse(defaults []db.DefaultReminder) defaultRemindersResponse {
+	res := defaultRemindersResponse {
+		MinutesBefore: []int32{},
+	}
+
+	for _, reminder := range defaults {
+		res.MinutesBefore = append(res.MinutesBefore, reminder.OffsetSeconds / 60)
+	}
+
+	return res
+}
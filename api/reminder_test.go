@@ -0,0 +1,331 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomReminder(task db.Task) db.TaskReminder {
+	return db.TaskReminder {
+		ID: uuid.New(),
+		TaskID: task.ID,
+		RemindAt: task.DueDate.Add(-time.Hour),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestAddTaskReminderApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	full := make([]db.TaskReminder, maxTaskReminders)
+	for i := range full {
+		full[i] = randomReminder(task)
+	}
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Relative",
+			body: gin.H {
+				"minutes_before": 15,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskReminderParams {
+					TaskID: task.ID,
+					OffsetSeconds: sql.NullInt32{Int32: 900, Valid: true},
+					RemindAt: task.DueDate.Add(-15 * time.Minute),
+				}
+
+				reminder := db.TaskReminder {
+					ID: uuid.New(),
+					TaskID: task.ID,
+					OffsetSeconds: arg.OffsetSeconds,
+					RemindAt: arg.RemindAt,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListTaskReminders(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().CreateTaskReminder(gomock.Any(), gomock.Eq(arg)).Times(1).Return(reminder, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res reminderResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotNil(t, res.MinutesBefore)
+				require.Equal(t, int32(15), *res.MinutesBefore)
+			},
+		},
+		{
+			name: "Absolute",
+			body: gin.H {
+				"remind_at": task.DueDate.Add(-24 * time.Hour).Format(time.RFC3339),
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskReminderParams {
+					TaskID: task.ID,
+					RemindAt: task.DueDate.Add(-24 * time.Hour).Truncate(time.Second),
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListTaskReminders(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().
+					CreateTaskReminder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, got db.CreateTaskReminderParams) (db.TaskReminder, error) {
+						require.Equal(t, arg.TaskID, got.TaskID)
+						require.False(t, got.OffsetSeconds.Valid)
+						require.True(t, arg.RemindAt.Equal(got.RemindAt))
+						return db.TaskReminder{ID: uuid.New(), TaskID: task.ID, RemindAt: got.RemindAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res reminderResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Nil(t, res.MinutesBefore)
+			},
+		},
+		{
+			name: "AfterDueDate",
+			body: gin.H {
+				"remind_at": task.DueDate.Add(time.Hour).Format(time.RFC3339),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CreateTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BothFields",
+			body: gin.H {
+				"remind_at": task.DueDate.Add(-time.Hour).Format(time.RFC3339),
+				"minutes_before": 15,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CreateTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeOffset",
+			body: gin.H {
+				"minutes_before": -5,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooMany",
+			body: gin.H {
+				"minutes_before": 5,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListTaskReminders(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(full, nil)
+				store.EXPECT().CreateTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			url := "/tasks/" + task.ID.String() + "/reminders"
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTaskReminderApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	reminder := randomReminder(task)
+
+	otherReminder := randomReminder(randomTask(user))
+
+	testCases := []struct {
+		name 	string
+		reminderID 	uuid.UUID
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			reminderID: reminder.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().DeleteTaskReminder(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OtherTask",
+			reminderID: otherReminder.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(otherReminder.ID)).Times(1).Return(otherReminder, nil)
+				store.EXPECT().DeleteTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			reminderID: reminder.ID,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(db.TaskReminder{}, sql.ErrNoRows)
+				store.EXPECT().DeleteTaskReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/tasks/" + task.ID.String() + "/reminders/" + tc.reminderID.String()
+
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReplaceDefaultRemindersApi(t *testing.T) {
+	user := randomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	arg := db.ReplaceDefaultRemindersTxParams {
+		UserID: user.ID,
+		OffsetSeconds: []int32{86400, 900},
+	}
+
+	defaults := []db.DefaultReminder{
+		{ID: uuid.New(), UserID: user.ID, OffsetSeconds: 86400},
+		{ID: uuid.New(), UserID: user.ID, OffsetSeconds: 900},
+	}
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().ReplaceDefaultRemindersTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(defaults, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"minutes_before": []int32{1440, 15}})
+
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPut, "/reminders/defaults", bytes.NewReader(data))
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res defaultRemindersResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Equal(t, []int32{1440, 15}, res.MinutesBefore)
+}
@@ -1,32 +1,54 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/stream"
 	token "m1thrandir225/your_time/token"
 	"m1thrandir225/your_time/util"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 type Server struct {
 	config util.Config
 	store db.Store
 	tokenMaker token.Maker
+	emailVerifier *token.EmailVerifier
+	events *stream.Broker
+	revocations *token.RevocationList
+	//mailer sends emails to users, it is nil when no SMTP server is configured
+	mailer notify.Notifier
 	router *gin.Engine
 }
 
-func NewServer(config util.Config, store db.Store)( *Server, error) {
+func NewServer(config util.Config, store db.Store, events *stream.Broker, revocations *token.RevocationList, mailer notify.Notifier)( *Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	emailVerifier, err := token.NewEmailVerifier(config.TokenSymmetricKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot create email verifier: %w", err)
+	}
+
 	server := &Server {
 		config: config,
 		store: store,
 		tokenMaker: tokenMaker,
+		emailVerifier: emailVerifier,
+		events: events,
+		revocations: revocations,
+		mailer: mailer,
 	}
 
 	server.SetupRouter()
@@ -38,25 +60,140 @@ func NewServer(config util.Config, store db.Store)( *Server, error) {
 func (server *Server) SetupRouter() {
 	router := gin.Default();
 
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("task_status", validTaskStatus)
+		v.RegisterValidation("task_priority", validTaskPriority)
+		v.RegisterValidation("webhook_event", validWebhookEvent)
+	}
+
 	//Register
 	router.POST("/users", server.createUser)
 
 	//Login
 	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login/2fa", server.verifyLoginChallenge)
+
+	router.POST("/tokens/renew_access", server.renewAccessToken)
+
+	router.POST("/users/password/forgot", server.forgotPassword)
+	router.POST("/users/password/reset", server.resetPassword)
+
+	router.GET("/users/verify", server.verifyEmail)
+	router.POST("/users/verify/resend", server.resendVerification)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations))
 
-	router.GET("/tasks/:id", server.getTaskByID);
-	router.POST("/tasks", server.createTask)
-	router.GET("/tasks/user/:user_id", server.getTasksByUser)
+	//Features that send data elsewhere need a verified email, if the server is configured so
+	verifiedRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations), server.requireVerifiedEmail())
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	router.GET("/ws", socketTokenMiddleware(), authMiddleware(server.tokenMaker, server.revocations), server.serveWebSocket)
 
+	authRoutes.POST("/users/logout", server.logoutUser)
+	authRoutes.POST("/users/logout_all", server.logoutUserEverywhere)
+	authRoutes.PUT("/users/email", server.changeEmail)
+	authRoutes.GET("/users/2fa", server.getTwoFactorStatus)
+	authRoutes.DELETE("/users/2fa", server.disableTwoFactor)
+	authRoutes.POST("/users/2fa/totp", server.enrollTOTP)
+	authRoutes.POST("/users/2fa/totp/confirm", server.confirmTOTP)
+	authRoutes.POST("/users/2fa/recovery_codes", server.regenerateRecoveryCodes)
 	authRoutes.GET("/users/:id", server.getUser)
+	authRoutes.PATCH("/users/:id", server.updateUser)
+
+	authRoutes.GET("/tasks/:id", server.getTaskByID);
+	authRoutes.POST("/tasks", server.createTask)
+	authRoutes.GET("/tasks", server.listTasks)
+	authRoutes.GET("/tasks/user/:user_id", server.getTasksByUser)
+	authRoutes.PATCH("/tasks/:id", server.updateTask)
+	authRoutes.PUT("/tasks/:id", server.replaceTask)
+	authRoutes.POST("/tasks/:id/complete", server.completeTask)
+	authRoutes.POST("/tasks/:id/reopen", server.reopenTask)
+	authRoutes.GET("/tasks/trash", server.getTrashedTasks)
+	authRoutes.GET("/tasks/matrix", server.getTaskMatrix)
+	authRoutes.DELETE("/tasks/:id", server.trashTask)
+	authRoutes.POST("/tasks/:id/restore", server.restoreTask)
+	authRoutes.GET("/tasks/:id/children", server.getTaskChildren)
+	authRoutes.POST("/tasks/:id/move", server.moveTask)
+	authRoutes.GET("/tasks/:id/occurrences", server.listTaskOccurrences)
+	authRoutes.GET("/tasks/:id/reminders", server.listTaskReminders)
+	authRoutes.POST("/tasks/:id/reminders", server.addTaskReminder)
+	authRoutes.DELETE("/tasks/:id/reminders/:reminder_id", server.deleteTaskReminder)
+	authRoutes.POST("/tasks/:id/reminder/snooze", server.snoozeTaskReminder)
+	authRoutes.POST("/tasks/:id/reminder/dismiss", server.dismissTaskReminder)
+	authRoutes.GET("/tasks/:id/reminder/snoozes", server.listReminderSnoozes)
+	authRoutes.GET("/reminders/defaults", server.getDefaultReminders)
+	authRoutes.PUT("/reminders/defaults", server.replaceDefaultReminders)
+	authRoutes.GET("/quiet_hours", server.getQuietHours)
+	authRoutes.PUT("/quiet_hours", server.replaceQuietHours)
+	authRoutes.PUT("/quiet_hours/dnd", server.setDoNotDisturb)
+	authRoutes.DELETE("/quiet_hours/dnd", server.clearDoNotDisturb)
+	authRoutes.GET("/quiet_hours/resolve", server.resolveDelivery)
+	authRoutes.GET("/digest/settings", server.getDigestSettings)
+	verifiedRoutes.PUT("/digest/settings", server.updateDigestSettings)
+	authRoutes.GET("/digest/preview", server.previewDigest)
+
+	authRoutes.POST("/tags", server.createTag)
+	authRoutes.GET("/tags", server.listTags)
+	authRoutes.PATCH("/tags/:id", server.updateTag)
+	authRoutes.DELETE("/tags/:id", server.deleteTag)
+
+	authRoutes.POST("/projects", server.createProject)
+	authRoutes.GET("/projects", server.listProjects)
+	authRoutes.GET("/projects/:id", server.getProject)
+	authRoutes.PATCH("/projects/:id", server.updateProject)
+	authRoutes.DELETE("/projects/:id", server.deleteProject)
+	authRoutes.POST("/projects/:id/archive", server.archiveProject)
+	authRoutes.POST("/projects/:id/unarchive", server.unarchiveProject)
+	authRoutes.GET("/projects/:id/tasks", server.getProjectTasks)
+
+	authRoutes.GET("/notifications", server.listNotifications)
+	authRoutes.POST("/notifications/read_all", server.markAllNotificationsRead)
+	authRoutes.POST("/notifications/:id/read", server.markNotificationRead)
+	authRoutes.DELETE("/notifications/:id", server.deleteNotification)
+
+	authRoutes.GET("/events", server.streamEvents)
+
+	verifiedRoutes.POST("/webhooks", server.createWebhook)
+	authRoutes.GET("/webhooks", server.listWebhooks)
+	authRoutes.GET("/webhooks/:id", server.getWebhook)
+	verifiedRoutes.PATCH("/webhooks/:id", server.updateWebhook)
+	authRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	verifiedRoutes.POST("/webhooks/:id/test", server.sendTestWebhook)
 
 	server.router = router
 }
 
-func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+// shutdownTimeout is how long requests in flight get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// Start serves requests on address until ctx is cancelled, then shuts down gracefully.
+func (server *Server) Start(ctx context.Context, address string) error {
+	httpServer := &http.Server {
+		Addr: address,
+		Handler: server.router,
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	//Event streams and WebSockets never finish on their own, closing the
+	//broker ends them so clients reconnect to another instance
+
+	server.events.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return httpServer.Shutdown(shutdownCtx)
 }
 
 func errorResponse(err error) gin.H {
@@ -0,0 +1,328 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	snoozePreset10Minutes = "10m"
+	snoozePresetHour = "1h"
+	snoozePresetTomorrow = "tomorrow"
+	snoozePresetCustom = "custom"
+
+	//snoozeMorningHour is the local hour the tomorrow preset snoozes to
+	snoozeMorningHour = 9
+	//maxSnooze is how far into the future a custom snooze can go
+	maxSnooze = 365 * 24 * time.Hour
+)
+
+type snoozeReminderRequest struct {
+	Preset string `json:"preset" binding:"required,oneof=10m 1h tomorrow custom"`
+	//Until is only used by the custom preset
+	Until *string `json:"until"`
+	//ReminderID picks the reminder to snooze, by default it's the one that fired last
+	ReminderID *string `json:"reminder_id" binding:"omitempty,uuid"`
+}
+
+type dismissReminderRequest struct {
+	ReminderID *string `json:"reminder_id" binding:"omitempty,uuid"`
+}
+
+type reminderSnoozeResponse struct {
+	ID string `json:"id"`
+	ReminderID *string `json:"reminder_id"`
+	Preset string `json:"preset"`
+	SnoozedFrom string `json:"snoozed_from"`
+	SnoozedUntil string `json:"snoozed_until"`
+	CreatedAt string `json:"created_at"`
+}
+
+type snoozeReminderResponse struct {
+	Reminder reminderResponse `json:"reminder"`
+	Snooze reminderSnoozeResponse `json:"snooze"`
+}
+
+type listReminderSnoozesResponse struct {
+	Snoozes []reminderSnoozeResponse `json:"snoozes"`
+}
+
+var (
+	errNoFiredReminder = errors.New("the task has no reminder that has fired")
+	errTaskClosed = errors.New("reminders of done or cancelled tasks can't be snoozed")
+	errSnoozeUntilRequired = errors.New("until is required for a custom snooze")
+	errSnoozeUntilUnused = errors.New("until can only be used with a custom snooze")
+	errSnoozeInPast = errors.New("a reminder can only be snoozed to a time in the future")
+	errSnoozeTooFar = errors.New("a reminder can't be snoozed for more than a year")
+)
+
+func newReminderSnoozeResponse(snooze db.ReminderSnooze) reminderSnoozeResponse {
+	res := reminderSnoozeResponse {
+		ID: snooze.ID.String(),
+		Preset: snooze.Preset,
+		SnoozedFrom: snooze.SnoozedFrom.Format(time.RFC3339),
+		SnoozedUntil: snooze.SnoozedUntil.Format(time.RFC3339),
+		CreatedAt: snooze.CreatedAt.Format(time.RFC3339),
+	}
+
+	if snooze.ReminderID.Valid {
+		reminderID := snooze.ReminderID.UUID.String()
+		res.ReminderID = &reminderID
+	}
+
+	return res
+}
+
+// snoozeUntil works out when a reminder snoozed at now with preset fires again.
+// The tomorrow preset is the next morning in loc, the timezone of the user.
+func snoozeUntil(preset string, until *string, now time.Time, loc *time.Location) (time.Time, error) {
+	if preset != snoozePresetCustom && until != nil {
+		return time.Time{}, errSnoozeUntilUnused
+	}
+
+	switch preset {
+	case snoozePreset10Minutes:
+		return now.Add(10 * time.Minute), nil
+	case snoozePresetHour:
+		return now.Add(time.Hour), nil
+	case snoozePresetTomorrow:
+		local := now.In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day()+1, snoozeMorningHour, 0, 0, 0, loc), nil
+	}
+
+	if until == nil {
+		return time.Time{}, errSnoozeUntilRequired
+	}
+
+	at, err := time.Parse(time.RFC3339, *until)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !at.After(now) {
+		return time.Time{}, errSnoozeInPast
+	}
+
+	if at.Sub(now) > maxSnooze {
+		return time.Time{}, errSnoozeTooFar
+	}
+
+	return at, nil
+}
+
+// getSnoozableReminder returns the reminder of task with reminderID, or the reminder
+// that fired last when reminderID is nil. It writes the error response itself.
+func (server *Server) getSnoozableReminder(ctx *gin.Context, task db.Task, reminderID *string) (reminder db.TaskReminder, ok bool) {
+	var err error
+
+	if reminderID != nil {
+		reminder, err = server.store.GetTaskReminderByID(ctx, uuid.MustParse(*reminderID))
+
+		if err == nil && reminder.TaskID != task.ID {
+			err = sql.ErrNoRows
+		}
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusNotFound, errorResponse(errReminderNotFound))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		return reminder, true
+	}
+
+	reminder, err = server.store.GetLatestFiredReminder(ctx, db.GetLatestFiredReminderParams{
+		TaskID: task.ID,
+		Now: time.Now(),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(errNoFiredReminder))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return reminder, true
+}
+
+// snoozeTaskReminder postpones a reminder of the task, the one that fired last
+// unless the client names one. The original time of the reminder is kept.
+func (server *Server) snoozeTaskReminder(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req snoozeReminderRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	until, err := snoozeUntil(req.Preset, req.Until, time.Now(), user.Location())
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	if task.Status == db.TaskStatusDone || task.Status == db.TaskStatusCancelled {
+		ctx.JSON(http.StatusConflict, errorResponse(errTaskClosed))
+		return
+	}
+
+	reminder, ok := server.getSnoozableReminder(ctx, task, req.ReminderID)
+
+	if !ok {
+		return
+	}
+
+	result, err := server.store.SnoozeReminderTx(ctx, db.SnoozeReminderTxParams {
+		Reminder: reminder,
+		Preset: req.Preset,
+		Until: until,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, snoozeReminderResponse {
+		Reminder: newReminderResponse(result.Reminder),
+		Snooze: newReminderSnoozeResponse(result.Snooze),
+	})
+}
+
+// dismissTaskReminder marks a fired reminder as handled so it isn't retried.
+// The body is optional, without one the reminder that fired last is dismissed.
+func (server *Server) dismissTaskReminder(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req dismissReminderRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	reminder, ok := server.getSnoozableReminder(ctx, task, req.ReminderID)
+
+	if !ok {
+		return
+	}
+
+	reminder, err = server.store.DismissReminderTx(ctx, reminder.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newReminderResponse(reminder))
+}
+
+func (server *Server) listReminderSnoozes(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	snoozes, err := server.store.ListReminderSnoozes(ctx, task.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := listReminderSnoozesResponse {
+		Snoozes: []reminderSnoozeResponse{},
+	}
+
+	for _, snooze := range snoozes {
+		res.Snoozes = append(res.Snoozes, newReminderSnoozeResponse(snooze))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
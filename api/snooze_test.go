@@ -0,0 +1,266 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnoozeUntil(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	//23:30 in New York is already the next day in UTC
+	now := time.Date(2024, time.March, 9, 4, 30, 0, 0, time.UTC)
+
+	until, err := snoozeUntil(snoozePresetTomorrow, nil, now, newYork)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, time.March, 9, 9, 0, 0, 0, newYork), until)
+
+	//The clocks move forward on the night of March 10th
+	until, err = snoozeUntil(snoozePresetTomorrow, nil, until, newYork)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC), until.UTC())
+
+	until, err = snoozeUntil(snoozePreset10Minutes, nil, now, newYork)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(10*time.Minute), until)
+
+	custom := now.Add(3 * time.Hour).Format(time.RFC3339)
+	until, err = snoozeUntil(snoozePresetCustom, &custom, now, newYork)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(3*time.Hour), until.UTC())
+
+	past := now.Add(-time.Minute).Format(time.RFC3339)
+	_, err = snoozeUntil(snoozePresetCustom, &past, now, newYork)
+	require.ErrorIs(t, err, errSnoozeInPast)
+
+	far := now.Add(2 * maxSnooze).Format(time.RFC3339)
+	_, err = snoozeUntil(snoozePresetCustom, &far, now, newYork)
+	require.ErrorIs(t, err, errSnoozeTooFar)
+
+	_, err = snoozeUntil(snoozePresetCustom, nil, now, newYork)
+	require.ErrorIs(t, err, errSnoozeUntilRequired)
+
+	_, err = snoozeUntil(snoozePresetHour, &custom, now, newYork)
+	require.ErrorIs(t, err, errSnoozeUntilUnused)
+}
+
+func TestSnoozeTaskReminderApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	reminder := randomReminder(task)
+
+	otherReminder := randomReminder(randomTask(user))
+
+	done := task
+	done.Status = db.TaskStatusDone
+
+	snoozed := func(arg db.SnoozeReminderTxParams) db.SnoozeReminderTxResult {
+		result := db.SnoozeReminderTxResult {
+			Reminder: arg.Reminder,
+			Snooze: db.ReminderSnooze {
+				ID: uuid.New(),
+				TaskID: arg.Reminder.TaskID,
+				ReminderID: uuid.NullUUID{UUID: arg.Reminder.ID, Valid: true},
+				Preset: arg.Preset,
+				SnoozedFrom: arg.Reminder.RemindAt,
+				SnoozedUntil: arg.Until,
+				CreatedAt: time.Now(),
+			},
+		}
+		result.Reminder.RemindAt = arg.Until
+		result.Reminder.OriginalRemindAt = sql.NullTime{Time: arg.Reminder.RemindAt, Valid: true}
+		result.Reminder.SnoozeCount++
+		return result
+	}
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "LatestFired",
+			body: gin.H{"preset": "10m"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetLatestFiredReminder(gomock.Any(), gomock.Any()).Times(1).Return(reminder, nil)
+				store.EXPECT().SnoozeReminderTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SnoozeReminderTxParams) (db.SnoozeReminderTxResult, error) {
+						require.Equal(t, reminder, arg.Reminder)
+						require.Equal(t, "10m", arg.Preset)
+						require.WithinDuration(t, time.Now().Add(10*time.Minute), arg.Until, time.Minute)
+						return snoozed(arg), nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res snoozeReminderResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int32(1), res.Reminder.SnoozeCount)
+				require.NotNil(t, res.Reminder.OriginalRemindAt)
+				require.Equal(t, reminder.RemindAt.Format(time.RFC3339), *res.Reminder.OriginalRemindAt)
+				require.Equal(t, res.Reminder.RemindAt, res.Snooze.SnoozedUntil)
+				require.Equal(t, "10m", res.Snooze.Preset)
+			},
+		},
+		{
+			name: "ByReminderID",
+			body: gin.H{"preset": "tomorrow", "reminder_id": reminder.ID},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetLatestFiredReminder(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SnoozeReminderTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SnoozeReminderTxParams) (db.SnoozeReminderTxResult, error) {
+						require.Equal(t, snoozeMorningHour, arg.Until.Hour())
+						require.True(t, arg.Until.After(time.Now()))
+						return snoozed(arg), nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ReminderOfOtherTask",
+			body: gin.H{"preset": "1h", "reminder_id": otherReminder.ID},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(otherReminder.ID)).Times(1).Return(otherReminder, nil)
+				store.EXPECT().SnoozeReminderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NothingFired",
+			body: gin.H{"preset": "1h"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetLatestFiredReminder(gomock.Any(), gomock.Any()).Times(1).Return(db.TaskReminder{}, sql.ErrNoRows)
+				store.EXPECT().SnoozeReminderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "TaskDone",
+			body: gin.H{"preset": "1h"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(done, nil)
+				store.EXPECT().GetLatestFiredReminder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "CustomInPast",
+			body: gin.H{"preset": "custom", "until": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SnoozeReminderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownPreset",
+			body: gin.H{"preset": "next-week"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).AnyTimes().Return(user, nil)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/tasks/"+task.ID.String()+"/reminder/snooze", bytes.NewReader(data))
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDismissTaskReminderApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	reminder := randomReminder(task)
+
+	dismissed := reminder
+	dismissed.DismissedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+	store.EXPECT().GetLatestFiredReminder(gomock.Any(), gomock.Any()).Times(1).Return(reminder, nil)
+	store.EXPECT().DismissReminderTx(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(dismissed, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	//Without a body the reminder that fired last is dismissed
+	request := httptest.NewRequest(http.MethodPost, "/tasks/"+task.ID.String()+"/reminder/dismiss", nil)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res reminderResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.NotNil(t, res.DismissedAt)
+}
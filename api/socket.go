@@ -0,0 +1,457 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"m1thrandir225/your_time/stream"
+	"m1thrandir225/your_time/token"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	//maxSocketMessageSize caps the size of a message a client sends
+	maxSocketMessageSize = 4096
+	//socketSendBuffer is how many replies may wait for the client to read them.
+	//Once it is full the connection stops reading until the client catches up
+	socketSendBuffer = 16
+	socketWriteWait = 10 * time.Second
+
+	socketMessageSubscribe = "subscribe"
+	socketMessageUnsubscribe = "unsubscribe"
+	socketMessageReply = "reply"
+	socketMessageEvent = "event"
+)
+
+//socketBearerProtocol is the subprotocol browsers offer along with their access token, see socketTokenMiddleware
+const socketBearerProtocol = "bearer"
+
+var upgrader = websocket.Upgrader {
+	ReadBufferSize: 1024,
+	WriteBufferSize: 1024,
+	//Browsers drop the connection unless one of the offered subprotocols is picked
+	Subprotocols: []string{socketBearerProtocol},
+	//Connections are authorized by access token rather than cookies, so
+	//pages on other origins can't act on behalf of a user
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// socketMutation is the REST endpoint a mutation sent over a WebSocket is routed to.
+type socketMutation struct {
+	method string
+	path string
+	body func(msg socketMessage) (interface{}, error)
+}
+
+var socketMutations = map[string]socketMutation {
+	"complete": {method: http.MethodPost, path: "/tasks/%s/complete"},
+	"reopen": {method: http.MethodPost, path: "/tasks/%s/reopen"},
+	"move": {
+		method: http.MethodPost,
+		path: "/tasks/%s/move",
+		body: func(msg socketMessage) (interface{}, error) {
+			return moveTaskRequest{ParentID: msg.ParentID}, nil
+		},
+	},
+	"reorder": {
+		method: http.MethodPatch,
+		path: "/projects/%s",
+		body: func(msg socketMessage) (interface{}, error) {
+			if msg.SortOrder == nil {
+				return nil, errors.New("sort_order is required")
+			}
+			return gin.H{"sort_order": *msg.SortOrder}, nil
+		},
+	},
+}
+
+// socketMessage is a message a client sends. Every message gets a reply with
+// the same ref.
+type socketMessage struct {
+	Type string `json:"type"`
+	Ref string `json:"ref"`
+	//ProjectIDs and TaskIDs are what subscribe and unsubscribe act on
+	ProjectIDs []uuid.UUID `json:"project_ids"`
+	TaskIDs []uuid.UUID `json:"task_ids"`
+	//ID is the task or project a mutation acts on
+	ID string `json:"id"`
+	ParentID *string `json:"parent_id"`
+	SortOrder *int32 `json:"sort_order"`
+}
+
+type socketReply struct {
+	Type string `json:"type"`
+	Ref string `json:"ref"`
+	Status int `json:"status"`
+	Body json.RawMessage `json:"body"`
+}
+
+type socketEvent struct {
+	Type string `json:"type"`
+	ID uint64 `json:"id"`
+	Event json.RawMessage `json:"event"`
+}
+
+type socketSubscriptions struct {
+	ProjectIDs []uuid.UUID `json:"project_ids"`
+	TaskIDs []uuid.UUID `json:"task_ids"`
+}
+
+// scopedTask is the part of the task of an event that subscriptions match on.
+type scopedTask struct {
+	ID uuid.UUID `json:"id"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type eventScope struct {
+	Data struct {
+		scopedTask
+		//Task is set by reminder events
+		Task *scopedTask `json:"task"`
+	} `json:"data"`
+}
+
+// socketConn is a WebSocket connection of a user. Replies and events are only
+// ever written by writeLoop, and messages are handled one at a time, so a
+// client that doesn't read is eventually not read from either.
+type socketConn struct {
+	server *Server
+	conn *websocket.Conn
+	authorization string
+	send chan []byte
+	//closed is closed once writeLoop stops, nothing is sent after that
+	closed chan struct{}
+
+	mu sync.Mutex
+	projects map[uuid.UUID]bool
+	tasks map[uuid.UUID]bool
+}
+
+// serveWebSocket upgrades the request to a WebSocket. Clients subscribe to
+// projects and tasks to get their change events, and send mutations that are
+// handled by the same handlers as the REST endpoints.
+func (server *Server) serveWebSocket(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+
+	//The upgrader has already responded
+	if err != nil {
+		return
+	}
+
+	sub, _, _ := server.events.Subscribe(user.ID, "")
+	defer sub.Close()
+
+	socket := &socketConn {
+		server: server,
+		conn: conn,
+		authorization: ctx.GetHeader(authorizationHeaderKey),
+		send: make(chan []byte, socketSendBuffer),
+		closed: make(chan struct{}),
+		projects: make(map[uuid.UUID]bool),
+		tasks: make(map[uuid.UUID]bool),
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		socket.writeLoop(sub, payload, done)
+	}()
+
+	socket.readLoop(ctx)
+
+	close(done)
+	wg.Wait()
+}
+
+func (socket *socketConn) readLoop(ctx *gin.Context) {
+	heartbeat := socket.server.heartbeatInterval()
+
+	socket.conn.SetReadLimit(maxSocketMessageSize)
+	socket.conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	socket.conn.SetPongHandler(func(string) error {
+		return socket.conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+
+	for {
+		_, data, err := socket.conn.ReadMessage()
+
+		if err != nil {
+			return
+		}
+
+		var msg socketMessage
+
+		if err := json.Unmarshal(data, &msg); err != nil {
+			socket.reply(msg, http.StatusBadRequest, errorResponse(err))
+			continue
+		}
+
+		socket.handle(ctx, msg)
+	}
+}
+
+// writeLoop writes replies and the events the client subscribed to until the
+// client goes away, the event stream is dropped or the access token expires.
+// Revocation is checked on every heartbeat, so logging out closes the
+// connection within one interval.
+func (socket *socketConn) writeLoop(sub *stream.Subscription, payload *token.Payload, done <-chan struct{}) {
+	ticker := time.NewTicker(socket.server.heartbeatInterval())
+	defer ticker.Stop()
+
+	expiry := time.NewTimer(time.Until(payload.ExpiredAt))
+	defer expiry.Stop()
+
+	//Closing the connection ends readLoop too
+	defer socket.conn.Close()
+	defer close(socket.closed)
+
+	for {
+		select {
+		case <-done:
+			return
+		case data := <-socket.send:
+			if err := socket.write(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case event, ok := <-sub.C:
+			//The client fell behind or the server is shutting down,
+			//either way it has to reconnect and reload
+			if !ok {
+				socket.close(websocket.CloseGoingAway, "event stream closed")
+				return
+			}
+
+			if !socket.subscribed(event) {
+				continue
+			}
+
+			data, err := json.Marshal(socketEvent{Type: socketMessageEvent, ID: event.ID, Event: event.Data})
+
+			if err != nil {
+				continue
+			}
+
+			if err := socket.write(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if socket.server.revocations.IsRevoked(payload) {
+				socket.close(websocket.ClosePolicyViolation, token.ErrRevokedToken.Error())
+				return
+			}
+
+			if err := socket.write(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-expiry.C:
+			socket.close(websocket.ClosePolicyViolation, token.ErrExpiredToken.Error())
+			return
+		}
+	}
+}
+
+func (socket *socketConn) write(messageType int, data []byte) error {
+	socket.conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+	return socket.conn.WriteMessage(messageType, data)
+}
+
+func (socket *socketConn) close(code int, reason string) {
+	socket.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(socketWriteWait))
+}
+
+func (socket *socketConn) handle(ctx *gin.Context, msg socketMessage) {
+	switch msg.Type {
+	case socketMessageSubscribe, socketMessageUnsubscribe:
+		socket.reply(msg, http.StatusOK, socket.updateSubscriptions(msg))
+		return
+	}
+
+	mutation, ok := socketMutations[msg.Type]
+
+	if !ok {
+		socket.reply(msg, http.StatusBadRequest, errorResponse(fmt.Errorf("unknown message type %q", msg.Type)))
+		return
+	}
+
+	//Parsing the ID keeps clients from routing the mutation anywhere else
+
+	id, err := uuid.Parse(msg.ID)
+
+	if err != nil {
+		socket.reply(msg, http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var body bytes.Buffer
+
+	if mutation.body != nil {
+		data, err := mutation.body(msg)
+
+		if err != nil {
+			socket.reply(msg, http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		if err := json.NewEncoder(&body).Encode(data); err != nil {
+			socket.reply(msg, http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx.Request.Context(), mutation.method, fmt.Sprintf(mutation.path, id), &body)
+
+	if err != nil {
+		socket.reply(msg, http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	req.Header.Set(authorizationHeaderKey, socket.authorization)
+	req.Header.Set("Content-Type", "application/json")
+
+	res := newSocketResponse()
+
+	socket.server.router.ServeHTTP(res, req)
+
+	socket.replyRaw(msg, res.status, res.body.Bytes())
+}
+
+func (socket *socketConn) updateSubscriptions(msg socketMessage) socketSubscriptions {
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+
+	subscribe := msg.Type == socketMessageSubscribe
+
+	for _, id := range msg.ProjectIDs {
+		setSubscribed(socket.projects, id, subscribe)
+	}
+
+	for _, id := range msg.TaskIDs {
+		setSubscribed(socket.tasks, id, subscribe)
+	}
+
+	res := socketSubscriptions {
+		ProjectIDs: []uuid.UUID{},
+		TaskIDs: []uuid.UUID{},
+	}
+
+	for id := range socket.projects {
+		res.ProjectIDs = append(res.ProjectIDs, id)
+	}
+
+	for id := range socket.tasks {
+		res.TaskIDs = append(res.TaskIDs, id)
+	}
+
+	return res
+}
+
+func setSubscribed(subscriptions map[uuid.UUID]bool, id uuid.UUID, subscribe bool) {
+	if subscribe {
+		subscriptions[id] = true
+	} else {
+		delete(subscriptions, id)
+	}
+}
+
+// subscribed reports whether event is about a project or task the client
+// subscribed to. Events of subtasks go to the subscribers of their parent.
+func (socket *socketConn) subscribed(event stream.Event) bool {
+	var scope eventScope
+
+	if err := json.Unmarshal(event.Data, &scope); err != nil {
+		return false
+	}
+
+	task := scope.Data.scopedTask
+	if scope.Data.Task != nil {
+		task = *scope.Data.Task
+	}
+
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+
+	if socket.tasks[task.ID] {
+		return true
+	}
+
+	if task.ParentID != nil && socket.tasks[*task.ParentID] {
+		return true
+	}
+
+	return task.ProjectID != nil && socket.projects[*task.ProjectID]
+}
+
+func (socket *socketConn) reply(msg socketMessage, status int, body interface{}) {
+	data, err := json.Marshal(body)
+
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(errorResponse(err))
+	}
+
+	socket.replyRaw(msg, status, data)
+}
+
+func (socket *socketConn) replyRaw(msg socketMessage, status int, body []byte) {
+	if len(body) == 0 {
+		body = []byte("null")
+	}
+
+	data, err := json.Marshal(socketReply{Type: socketMessageReply, Ref: msg.Ref, Status: status, Body: body})
+
+	if err != nil {
+		return
+	}
+
+	select {
+	case socket.send <- data:
+	case <-socket.closed:
+	}
+}
+
+// socketResponse collects the response of the handler a mutation was routed to.
+type socketResponse struct {
+	header http.Header
+	status int
+	body bytes.Buffer
+}
+
+func newSocketResponse() *socketResponse {
+	return &socketResponse{header: make(http.Header)}
+}
+
+func (res *socketResponse) Header() http.Header {
+	return res.header
+}
+
+func (res *socketResponse) Write(data []byte) (int, error) {
+	if res.status == 0 {
+		res.status = http.StatusOK
+	}
+	return res.body.Write(data)
+}
+
+func (res *socketResponse) WriteHeader(status int) {
+	if res.status == 0 {
+		res.status = status
+	}
+}
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/token"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type testSocketMessage struct {
+	Type string `json:"type"`
+	Ref string `json:"ref"`
+	Status int `json:"status"`
+	Body json.RawMessage `json:"body"`
+	Event *webhook.Event `json:"event"`
+}
+
+// dialSocket opens a WebSocket to server, passing accessToken as a subprotocol
+// the way browsers do, if it is set.
+func dialSocket(t *testing.T, server *Server, accessToken string) (*websocket.Conn, *http.Response, error) {
+	httpServer := httptest.NewServer(server.router)
+	t.Cleanup(httpServer.Close)
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+
+	dialer := *websocket.DefaultDialer
+
+	if accessToken != "" {
+		dialer.Subprotocols = []string{socketBearerProtocol, accessToken}
+	}
+
+	conn, response, err := dialer.Dial(url, nil)
+
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+
+	return conn, response, err
+}
+
+func readSocketMessage(t *testing.T, conn *websocket.Conn) testSocketMessage {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg testSocketMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+
+	return msg
+}
+
+func newSocketTestServer(t *testing.T, user db.User, build func(store *mockdb.MockStore)) (*Server, string) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).AnyTimes().Return(user, nil)
+
+	build(store)
+
+	server := newTestServer(t, store)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	return server, accessToken
+}
+
+func TestSocketSubscriptions(t *testing.T) {
+	user := randomUser()
+
+	projectID := uuid.New()
+
+	inProject := randomTask(user)
+	inProject.ProjectID = uuid.NullUUID{UUID: projectID, Valid: true}
+
+	subtask := randomTask(user)
+
+	parent := randomTask(user)
+	subtask.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+
+	server, accessToken := newSocketTestServer(t, user, func(store *mockdb.MockStore) {})
+
+	conn, _, err := dialSocket(t, server, accessToken)
+	require.NoError(t, err)
+	require.Equal(t, socketBearerProtocol, conn.Subprotocol())
+
+	require.NoError(t, conn.WriteJSON(socketMessage{Type: "subscribe", Ref: "1", ProjectIDs: []uuid.UUID{projectID}, TaskIDs: []uuid.UUID{parent.ID}}))
+
+	reply := readSocketMessage(t, conn)
+	require.Equal(t, "reply", reply.Type)
+	require.Equal(t, "1", reply.Ref)
+	require.Equal(t, http.StatusOK, reply.Status)
+
+	//Only the last two events are about something the client subscribed to
+
+	require.NoError(t, server.events.Publish(user.ID, webhook.NewTaskEvent(webhook.EventTaskUpdated, randomTask(user))))
+	require.NoError(t, server.events.Publish(user.ID, webhook.NewTaskEvent(webhook.EventTaskUpdated, inProject)))
+	require.NoError(t, server.events.Publish(user.ID, webhook.NewTaskEvent(webhook.EventTaskCreated, subtask)))
+
+	msg := readSocketMessage(t, conn)
+	require.Equal(t, "event", msg.Type)
+	require.Equal(t, webhook.EventTaskUpdated, msg.Event.Type)
+
+	msg = readSocketMessage(t, conn)
+	require.Equal(t, "event", msg.Type)
+	require.Equal(t, webhook.EventTaskCreated, msg.Event.Type)
+
+	require.NoError(t, conn.WriteJSON(socketMessage{Type: "unsubscribe", Ref: "2", ProjectIDs: []uuid.UUID{projectID}}))
+
+	reply = readSocketMessage(t, conn)
+	require.JSONEq(t, `{"project_ids":[],"task_ids":["` + parent.ID.String() + `"]}`, string(reply.Body))
+}
+
+func TestSocketMutations(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name string
+		msg socketMessage
+		build func(store *mockdb.MockStore)
+		checkReply func(t *testing.T, reply testSocketMessage)
+	}{
+		{
+			name: "Complete",
+			msg: socketMessage{Type: "complete", Ref: "a", ID: task.ID.String()},
+			build: func(store *mockdb.MockStore) {
+				done := task
+				done.Status = db.TaskStatusDone
+				done.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CompleteTaskTx(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.TaskTreeTxResult{Task: done}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCompleted)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkReply: func(t *testing.T, reply testSocketMessage) {
+				require.Equal(t, http.StatusOK, reply.Status)
+
+				var res createTaskResponse
+				require.NoError(t, json.Unmarshal(reply.Body, &res))
+				require.Equal(t, task.ID.String(), res.ID)
+				require.NotNil(t, res.CompletedAt)
+			},
+		},
+		{
+			name: "NotOwned",
+			msg: socketMessage{Type: "complete", Ref: "a", ID: task.ID.String()},
+			build: func(store *mockdb.MockStore) {
+				other := task
+				other.UserID = uuid.New()
+
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(other, nil)
+				store.EXPECT().CompleteTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkReply: func(t *testing.T, reply testSocketMessage) {
+				require.Equal(t, http.StatusForbidden, reply.Status)
+			},
+		},
+		{
+			name: "ReorderWithoutSortOrder",
+			msg: socketMessage{Type: "reorder", Ref: "a", ID: uuid.New().String()},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateProject(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkReply: func(t *testing.T, reply testSocketMessage) {
+				require.Equal(t, http.StatusBadRequest, reply.Status)
+			},
+		},
+		{
+			name: "InvalidID",
+			msg: socketMessage{Type: "complete", Ref: "a", ID: "../../users/me"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkReply: func(t *testing.T, reply testSocketMessage) {
+				require.Equal(t, http.StatusBadRequest, reply.Status)
+			},
+		},
+		{
+			name: "UnknownType",
+			msg: socketMessage{Type: "delete", Ref: "a", ID: task.ID.String()},
+			build: func(store *mockdb.MockStore) {
+			},
+			checkReply: func(t *testing.T, reply testSocketMessage) {
+				require.Equal(t, http.StatusBadRequest, reply.Status)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server, accessToken := newSocketTestServer(t, user, tc.build)
+
+			conn, _, err := dialSocket(t, server, accessToken)
+			require.NoError(t, err)
+
+			require.NoError(t, conn.WriteJSON(tc.msg))
+
+			reply := readSocketMessage(t, conn)
+			require.Equal(t, "reply", reply.Type)
+			require.Equal(t, tc.msg.Ref, reply.Ref)
+
+			tc.checkReply(t, reply)
+		})
+	}
+}
+
+func TestSocketNoAuthorization(t *testing.T) {
+	user := randomUser()
+
+	server, _ := newSocketTestServer(t, user, func(store *mockdb.MockStore) {})
+
+	_, response, err := dialSocket(t, server, "")
+
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestSocketQueryTokenRejected(t *testing.T) {
+	user := randomUser()
+
+	server, accessToken := newSocketTestServer(t, user, func(store *mockdb.MockStore) {})
+
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	//Tokens in the URL end up in access logs, so they aren't accepted
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws?access_token=" + accessToken
+
+	_, response, err := websocket.DefaultDialer.Dial(url, nil)
+
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestSocketClose(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		duration time.Duration
+		buildStubs func(store *mockdb.MockStore)
+		close func(t *testing.T, server *Server, accessToken string)
+		code int
+	}{
+		{
+			name: "Shutdown",
+			duration: time.Minute,
+			buildStubs: func(store *mockdb.MockStore) {},
+			close: func(t *testing.T, server *Server, accessToken string) {
+				server.events.Close()
+			},
+			code: websocket.CloseGoingAway,
+		},
+		{
+			name: "TokenExpired",
+			duration: 200 * time.Millisecond,
+			buildStubs: func(store *mockdb.MockStore) {},
+			close: func(t *testing.T, server *Server, accessToken string) {},
+			code: websocket.ClosePolicyViolation,
+		},
+		{
+			name: "TokenRevoked",
+			duration: time.Minute,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			close: func(t *testing.T, server *Server, accessToken string) {
+				payload, err := server.tokenMaker.VerifyToken(accessToken)
+				require.NoError(t, err)
+
+				require.NoError(t, server.revocations.Revoke(context.Background(), user.ID, payload))
+			},
+			code: websocket.ClosePolicyViolation,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server, _ := newSocketTestServer(t, user, tc.buildStubs)
+			server.config.EventsHeartbeatInterval = 50 * time.Millisecond
+
+			accessToken, _, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, tc.duration)
+			require.NoError(t, err)
+
+			conn, _, err := dialSocket(t, server, accessToken)
+			require.NoError(t, err)
+
+			tc.close(t, server, accessToken)
+
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+			_, _, err = conn.ReadMessage()
+			require.True(t, websocket.IsCloseError(err, tc.code), err)
+		})
+	}
+}
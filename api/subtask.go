@@ -0,0 +1,128 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type moveTaskRequest struct {
+	//ParentID is the new parent of the task, or null to move it to the top level
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+func (server *Server) getTaskChildren(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	children, err := server.store.GetChildTasks(ctx, task.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res, err := server.taskResponses(ctx, children)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listTasksResponse{Tasks: res})
+}
+
+// moveTask moves a task, along with all of its subtasks, under another task or to the top level.
+func (server *Server) moveTask(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req moveTaskRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	arg := db.MoveTaskParams {
+		ID: task.ID,
+	}
+
+	if req.ParentID != nil {
+		parent, ok := server.getOwnedTask(ctx, user, uuid.MustParse(*req.ParentID))
+		if !ok {
+			return
+		}
+		arg.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	task, err = server.store.MoveTaskTx(ctx, arg)
+
+	if err != nil {
+		if errors.Is(err, db.ErrTaskCycle) || errors.Is(err, db.ErrTaskTooDeep) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishTaskEvent(ctx, webhook.EventTaskUpdated, task)
+
+	server.respondWithTask(ctx, task)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	parent := randomTask(user)
+
+	otherTask := randomTask(randomUser())
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H {
+				"parent_id": parent.ID.String(),
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.MoveTaskParams {
+					ID: task.ID,
+					ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true},
+				}
+
+				moved := task
+				moved.ParentID = arg.ParentID
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().MoveTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(moved, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res createTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotNil(t, res.ParentID)
+				require.Equal(t, parent.ID.String(), *res.ParentID)
+			},
+		},
+		{
+			name: "ToTopLevel",
+			body: gin.H {
+				"parent_id": nil,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.MoveTaskParams {
+					ID: task.ID,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().MoveTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(task, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Cycle",
+			body: gin.H {
+				"parent_id": parent.ID.String(),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().MoveTaskTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Task{}, db.ErrTaskCycle)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooDeep",
+			body: gin.H {
+				"parent_id": parent.ID.String(),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().MoveTaskTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Task{}, db.ErrTaskTooDeep)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ParentOfOtherUser",
+			body: gin.H {
+				"parent_id": otherTask.ID.String(),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).Times(1).Return(otherTask, nil)
+				store.EXPECT().MoveTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			url := "/tasks/" + task.ID.String() + "/move"
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTaskChildrenApi(t *testing.T) {
+	user := randomUser()
+
+	parent := randomTask(user)
+
+	child := randomTask(user)
+	child.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+
+	grandchild := randomTask(user)
+
+	rollups := []db.GetSubtaskRollupsRow{
+		{ParentID: child.ID, ChildCount: 2, DoneCount: 1, EarliestDueDate: grandchild.DueDate},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+	store.EXPECT().GetChildTasks(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return([]db.Task{child}, nil)
+	store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Eq([]uuid.UUID{child.ID})).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+	store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+	store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Eq([]uuid.UUID{child.ID})).Times(1).Return(rollups, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/tasks/" + parent.ID.String() + "/children", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res listTasksResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res.Tasks, 1)
+
+	require.Equal(t, child.ID.String(), res.Tasks[0].ID)
+	require.Equal(t, int64(2), res.Tasks[0].ChildCount)
+	require.Equal(t, int64(1), res.Tasks[0].DoneChildCount)
+	require.NotNil(t, res.Tasks[0].EarliestChildDueDate)
+	require.Equal(t, grandchild.DueDate.Format(time.RFC3339), *res.Tasks[0].EarliestChildDueDate)
+}
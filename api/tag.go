@@ -0,0 +1,211 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const defaultTagColor = "#808080"
+
+type tagResponse struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+	Color string `json:"color"`
+}
+
+type createTagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type updateTagRequest struct {
+	Name *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type tagURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+var errTagNotOwned = errors.New("tag doesn't belong to the authenticated user")
+
+func newTagResponse(tag db.Tag) tagResponse {
+	return tagResponse {
+		ID: tag.ID.String(),
+		Name: tag.Name,
+		Color: tag.Color,
+	}
+}
+
+func newTaskTagResponses(rows []db.GetTagsForTasksRow) []tagResponse {
+	tags := []tagResponse{}
+
+	for _, row := range rows {
+		tags = append(tags, tagResponse{ID: row.ID.String(), Name: row.Name, Color: row.Color})
+	}
+
+	return tags
+}
+
+// getOwnedTag loads a tag and makes sure it belongs to user.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) getOwnedTag(ctx *gin.Context, user db.User) (tag db.Tag, ok bool) {
+	var uri tagURIRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	tag, err := server.store.GetTagByID(ctx, uuid.MustParse(uri.ID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if tag.UserID != user.ID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errTagNotOwned))
+		return
+	}
+
+	return tag, true
+}
+
+func (server *Server) createTag(ctx *gin.Context) {
+	var req createTagRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.CreateTagParams {
+		UserID: user.ID,
+		Name: req.Name,
+		Color: defaultTagColor,
+	}
+
+	if req.Color != "" {
+		arg.Color = req.Color
+	}
+
+	tag, err := server.store.CreateTag(ctx, arg)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTagResponse(tag))
+}
+
+func (server *Server) listTags(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	tags, err := server.store.ListTagsByUser(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := []tagResponse{}
+
+	for _, tag := range tags {
+		res = append(res, newTagResponse(tag))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (server *Server) updateTag(ctx *gin.Context) {
+	var req updateTagRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	tag, ok := server.getOwnedTag(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateTagParams {
+		ID: tag.ID,
+	}
+
+	if req.Name != nil {
+		arg.Name = sql.NullString{String: *req.Name, Valid: true}
+	}
+
+	if req.Color != nil {
+		arg.Color = sql.NullString{String: *req.Color, Valid: true}
+	}
+
+	tag, err := server.store.UpdateTag(ctx, arg)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTagResponse(tag))
+}
+
+func (server *Server) deleteTag(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	tag, ok := server.getOwnedTag(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteTag(ctx, tag.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTagResponse(tag))
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTagApi(t *testing.T) {
+	user := randomUser()
+
+	tag := randomTag(user)
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H {
+				"name": tag.Name,
+				"color": tag.Color,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTagParams {
+					UserID: user.ID,
+					Name: tag.Name,
+					Color: tag.Color,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTag(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tag, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTag(t, recorder, tag)
+			},
+		},
+		{
+			name: "DefaultColor",
+			body: gin.H {
+				"name": tag.Name,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTagParams {
+					UserID: user.ID,
+					Name: tag.Name,
+					Color: defaultTagColor,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTag(gomock.Any(), gomock.Eq(arg)).Times(1).Return(tag, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidColor",
+			body: gin.H {
+				"name": tag.Name,
+				"color": "blue",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTag(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateName",
+			body: gin.H {
+				"name": tag.Name,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTag(gomock.Any(), gomock.Any()).Times(1).Return(db.Tag{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tags", bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTagsApi(t *testing.T) {
+	user := randomUser()
+
+	tags := []db.Tag{randomTag(user), randomTag(user)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().ListTagsByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(tags, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/tags", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []tagResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Equal(t, []tagResponse{newTagResponse(tags[0]), newTagResponse(tags[1])}, res)
+}
+
+func TestUpdateTagApi(t *testing.T) {
+	user := randomUser()
+
+	tag := randomTag(user)
+
+	otherTag := randomTag(randomUser())
+
+	testCases := []struct {
+		name 	string
+		tagID 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			tagID: tag.ID.String(),
+			body: gin.H {
+				"color": "#00ff00",
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpdateTagParams {
+					ID: tag.ID,
+					Color: sql.NullString{String: "#00ff00", Valid: true},
+				}
+
+				updated := tag
+				updated.Color = "#00ff00"
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTagByID(gomock.Any(), gomock.Eq(tag.ID)).Times(1).Return(tag, nil)
+				store.EXPECT().UpdateTag(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden",
+			tagID: otherTag.ID.String(),
+			body: gin.H {
+				"name": "renamed",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTagByID(gomock.Any(), gomock.Eq(otherTag.ID)).Times(1).Return(otherTag, nil)
+				store.EXPECT().UpdateTag(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			tagID: tag.ID.String(),
+			body: gin.H {
+				"name": "renamed",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTagByID(gomock.Any(), gomock.Eq(tag.ID)).Times(1).Return(db.Tag{}, sql.ErrNoRows)
+				store.EXPECT().UpdateTag(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			tagID: "invalid",
+			body: gin.H {
+				"name": "renamed",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTagByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/tags/" + tc.tagID, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTagApi(t *testing.T) {
+	user := randomUser()
+
+	tag := randomTag(user)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetTagByID(gomock.Any(), gomock.Eq(tag.ID)).Times(1).Return(tag, nil)
+	store.EXPECT().DeleteTag(gomock.Any(), gomock.Eq(tag.ID)).Times(1).Return(nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, "/tags/" + tag.ID.String(), nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchTag(t, recorder, tag)
+}
+
+func randomTag(user db.User) db.Tag {
+	return db.Tag {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Name: util.RandomString(6),
+		Color: "#ff8800",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func requireBodyMatchTag(t *testing.T, recorder *httptest.ResponseRecorder, tag db.Tag) {
+	var res tagResponse
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Equal(t, newTagResponse(tag), res)
+}
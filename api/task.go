@@ -1,9 +1,14 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"m1thrandir225/your_time/webhook"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,8 +20,18 @@ type createTaskRequest struct {
 	Title string `json:"title" binding:"required"`
 	Description *string `json:"description,omitempty"`
 	DueDate string `json:"due_date" binding:"required"`
+	//ReminderDate adds a single absolute reminder and is kept for clients that predate Reminders
 	ReminderDate *string `json:"reminder_date,omitempty"`
-	UserID string `json:"user_id" binding:"required"`
+	//Reminders defaults to the default reminders of the user when omitted
+	Reminders *[]reminderRequest `json:"reminders" binding:"omitempty,dive"`
+	Priority string `json:"priority" binding:"omitempty,task_priority"`
+	Important bool `json:"important"`
+	//BreakThroughQuietHours delivers the reminders of the task even during quiet hours
+	BreakThroughQuietHours bool `json:"break_through_quiet_hours"`
+	Tags []string `json:"tags" binding:"omitempty,dive,min=1,max=50"`
+	ProjectID *string `json:"project_id" binding:"omitempty,uuid"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+	Recurrence *recurrenceRequest `json:"recurrence"`
 }
 
 type createTaskResponse struct {
@@ -24,8 +39,23 @@ type createTaskResponse struct {
 	Title string `json:"title"`
 	Description string `json:"description"`
 	DueDate string `json:"due_date"`
-	ReminderDate string `json:"reminder_date"`
+	//ReminderDate is the earliest reminder of the task
+	ReminderDate *string `json:"reminder_date"`
+	Reminders []reminderResponse `json:"reminders"`
 	UserID string `json:"user_id"`
+	Status string `json:"status"`
+	Priority string `json:"priority"`
+	Important bool `json:"important"`
+	BreakThroughQuietHours bool `json:"break_through_quiet_hours"`
+	ProjectID *string `json:"project_id"`
+	ParentID *string `json:"parent_id"`
+	ChildCount int64 `json:"child_count"`
+	DoneChildCount int64 `json:"done_child_count"`
+	EarliestChildDueDate *string `json:"earliest_child_due_date"`
+	Recurrence *recurrenceResponse `json:"recurrence"`
+	CompletedAt *string `json:"completed_at,omitempty"`
+	DeletedAt *string `json:"deleted_at,omitempty"`
+	Tags []tagResponse `json:"tags"`
 }
 
 type getTaskByIDRequest struct {
@@ -36,11 +66,226 @@ type getTasksByUser struct {
 	UserID string `uri:"user_id" binding:"required,min=1"`
 }
 
-type getTasksByUserResponse struct {
-	UserID string `json:"user_id"`
+type listTasksRequest struct {
+	ProjectID string `form:"project_id" binding:"omitempty,uuid"`
+	Status string `form:"status" binding:"omitempty,task_status"`
+	DueFrom time.Time `form:"due_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueTo time.Time `form:"due_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	HasReminder *bool `form:"has_reminder"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedFrom time.Time `form:"updated_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo time.Time `form:"updated_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Query string `form:"q"`
+	Tags string `form:"tags"`
+	TagMode string `form:"tag_mode" binding:"omitempty,oneof=any all"`
+	Sort string `form:"sort" binding:"omitempty,oneof=due_date created_at updated_at title"`
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor string `form:"cursor"`
+	Limit int32 `form:"limit" binding:"omitempty,min=1"`
+}
+
+type listTasksResponse struct {
 	Tasks []createTaskResponse `json:"tasks"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+type updateTaskRequest struct {
+	Title *string `json:"title" binding:"omitempty,min=1"`
+	Description nullableString `json:"description"`
+	DueDate *string `json:"due_date"`
+	ReminderDate nullableString `json:"reminder_date"`
+	Reminders *[]reminderRequest `json:"reminders" binding:"omitempty,dive"`
+	Status *string `json:"status" binding:"omitempty,task_status"`
+	Priority *string `json:"priority" binding:"omitempty,task_priority"`
+	Important *bool `json:"important"`
+	BreakThroughQuietHours *bool `json:"break_through_quiet_hours"`
+	Tags *[]string `json:"tags" binding:"omitempty,dive,min=1,max=50"`
+	ProjectID nullableString `json:"project_id"`
+	Recurrence nullableRecurrence `json:"recurrence"`
+}
+
+type replaceTaskRequest struct {
+	Title string `json:"title" binding:"required"`
+	Description *string `json:"description"`
+	DueDate string `json:"due_date" binding:"required"`
+	ReminderDate *string `json:"reminder_date"`
+	Reminders []reminderRequest `json:"reminders" binding:"omitempty,dive"`
+	Priority string `json:"priority" binding:"omitempty,task_priority"`
+	Important bool `json:"important"`
+	BreakThroughQuietHours bool `json:"break_through_quiet_hours"`
+	Tags []string `json:"tags" binding:"omitempty,dive,min=1,max=50"`
+	ProjectID *string `json:"project_id" binding:"omitempty,uuid"`
+	Recurrence *recurrenceRequest `json:"recurrence"`
+}
+
+var (
+	errReminderAfterDue = errors.New("reminder_date must not be after due_date")
+	errTaskNotOwned = errors.New("task doesn't belong to the authenticated user")
+)
+
+func newTaskResponse(task db.Task) createTaskResponse {
+	res := createTaskResponse {
+		ID: task.ID.String(),
+		Title: task.Title,
+		Description: task.Description.String,
+		DueDate: task.DueDate.Format(time.RFC3339),
+		Reminders: []reminderResponse{},
+		UserID: task.UserID.String(),
+		Status: string(task.Status),
+		Priority: string(task.Priority),
+		Important: task.Important,
+		BreakThroughQuietHours: task.BreakThroughQuietHours,
+		Tags: []tagResponse{},
+	}
+
+	if task.ProjectID.Valid {
+		projectID := task.ProjectID.UUID.String()
+		res.ProjectID = &projectID
+	}
+
+	res.Recurrence = newRecurrenceResponse(task)
+
+	if task.ParentID.Valid {
+		parentID := task.ParentID.UUID.String()
+		res.ParentID = &parentID
+	}
+
+	if task.CompletedAt.Valid {
+		completedAt := task.CompletedAt.Time.Format(time.RFC3339)
+		res.CompletedAt = &completedAt
+	}
+
+	if task.DeletedAt.Valid {
+		deletedAt := task.DeletedAt.Time.Format(time.RFC3339)
+		res.DeletedAt = &deletedAt
+	}
+
+	return res
+}
+
+func newTaskTxResponse(result db.TaskTxResult) createTaskResponse {
+	res := newTaskResponse(result.Task)
+	res.Tags = newTaskTagResponses(result.Tags)
+	res.setReminders(result.Reminders)
+
+	return res
+}
+
+// setReminders attaches reminders, sorted by time, to the response.
+func (res *createTaskResponse) setReminders(reminders []db.TaskReminder) {
+	res.Reminders = newReminderResponses(reminders)
+
+	if len(res.Reminders) > 0 {
+		res.ReminderDate = &res.Reminders[0].RemindAt
+	}
+}
+
+// taskResponses builds the responses for tasks, loading their tags, reminders and
+// subtask roll-ups with one query each regardless of how many tasks there are.
+func (server *Server) taskResponses(ctx *gin.Context, tasks []db.Task) ([]createTaskResponse, error) {
+	res := []createTaskResponse{}
+
+	if len(tasks) == 0 {
+		return res, nil
+	}
+
+	taskIDs := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	rows, err := server.store.GetTagsForTasks(ctx, taskIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tagsByTask := make(map[uuid.UUID][]db.GetTagsForTasksRow)
+	for _, row := range rows {
+		tagsByTask[row.TaskID] = append(tagsByTask[row.TaskID], row)
+	}
+
+	reminders, err := server.store.GetRemindersForTasks(ctx, taskIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remindersByTask := make(map[uuid.UUID][]db.TaskReminder)
+	for _, reminder := range reminders {
+		remindersByTask[reminder.TaskID] = append(remindersByTask[reminder.TaskID], reminder)
+	}
+
+	rollups, err := server.store.GetSubtaskRollups(ctx, taskIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rollupsByTask := make(map[uuid.UUID]db.GetSubtaskRollupsRow, len(rollups))
+	for _, rollup := range rollups {
+		rollupsByTask[rollup.ParentID] = rollup
+	}
+
+	for _, task := range tasks {
+		taskRes := newTaskResponse(task)
+		taskRes.Tags = newTaskTagResponses(tagsByTask[task.ID])
+		taskRes.setReminders(remindersByTask[task.ID])
+
+		if rollup, ok := rollupsByTask[task.ID]; ok {
+			taskRes.ChildCount = rollup.ChildCount
+			taskRes.DoneChildCount = rollup.DoneCount
+			earliestDueDate := rollup.EarliestDueDate.Format(time.RFC3339)
+			taskRes.EarliestChildDueDate = &earliestDueDate
+		}
+
+		res = append(res, taskRes)
+	}
+
+	return res, nil
 }
 
+// respondWithTask writes a single task, including its tags and reminders, as the response.
+func (server *Server) respondWithTask(ctx *gin.Context, task db.Task) {
+	res, err := server.taskResponses(ctx, []db.Task{task})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res[0])
+}
+
+// isValidReminder reports whether a reminder can be stored for a task due at
+// due. A reminder equal to the due date is allowed since createTask defaults to it.
+func isValidReminder(reminder time.Time, due time.Time) bool {
+	return reminder.Equal(due) || util.IsReminderBeforeDue(reminder, due)
+}
+
+
+// getOwnedTask loads a task and makes sure it belongs to user.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) getOwnedTask(ctx *gin.Context, user db.User, taskID uuid.UUID) (task db.Task, ok bool) {
+	task, err := server.store.GetTaskByID(ctx, taskID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if task.UserID != user.ID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errTaskNotOwned))
+		return
+	}
+
+	return task, true
+}
 
 func (server *Server) createTask(ctx *gin.Context) {
 	var req createTaskRequest
@@ -57,33 +302,44 @@ func (server *Server) createTask(ctx *gin.Context) {
 		return;
 	}
 
-	//ReminderDate is optional aka the user doesn't have to set a reminder date, so we need to check if it's nil
+	//Reminders are optional, tasks created without any get the default reminders of the user
 
-	var reminderDate sql.NullTime;
+	var reminders []db.ReminderSpec;
 
 	if req.ReminderDate != nil {
-		reminderDate.Time, err = time.Parse(time.RFC3339, *req.ReminderDate);
+		if req.Reminders != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errConflictingReminders));
+			return;
+		}
+
+		reminderDate, err := time.Parse(time.RFC3339, *req.ReminderDate);
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err));
 			return;
 		}
-		reminderDate.Valid = true;
-	} else {
-		reminderDate.Time = dueDate;
-		reminderDate.Valid = true;
-	}
 
+		if !isValidReminder(reminderDate, dueDate) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errReminderAfterDue));
+			return;
+		}
+
+		reminders = []db.ReminderSpec{{RemindAt: reminderDate}};
+	}
 
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err));
-		return;
+	if req.Reminders != nil {
+		reminders, err = parseReminders(*req.Reminders, dueDate);
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err));
+			return;
+		}
 	}
 
-	userUUID, err := uuid.Parse(req.UserID);
+	//Tasks are always created for the authenticated user
 
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err));
-		return;
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
 	}
 
 	//Description is optional, so we need to check if it's nil
@@ -97,31 +353,80 @@ func (server *Server) createTask(ctx *gin.Context) {
 		description.Valid = false;
 	}
 
+	var projectID uuid.NullUUID
+
+	if req.ProjectID != nil {
+		projectID, ok = server.taskProject(ctx, user, *req.ProjectID)
+		if !ok {
+			return
+		}
+	}
+
+	//Subtasks can only be added under the user's own tasks
+
+	var parentID uuid.NullUUID
+
+	if req.ParentID != nil {
+		parent, ok := server.getOwnedTask(ctx, user, uuid.MustParse(*req.ParentID))
+		if !ok {
+			return
+		}
+		parentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	var recurrence *util.Recurrence
+
+	if req.Recurrence != nil {
+		parsed, err := parseRecurrence(req.Recurrence, dueDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err));
+			return;
+		}
+		recurrence = &parsed
+	}
+
+	if reminders == nil {
+		reminders, err = server.defaultReminders(ctx, user);
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err));
+			return;
+		}
+	}
+
 	arg := db.CreateTaskParams {
 		Title: req.Title,
 		Description: description,
 		DueDate: dueDate,
-		ReminderDate: reminderDate,
-		UserID: userUUID,
+		UserID: user.ID,
+		Priority: db.TaskPriorityNone,
+		Important: req.Important,
+		BreakThroughQuietHours: req.BreakThroughQuietHours,
+		ProjectID: projectID,
+		ParentID: parentID,
 	}
 
-	task, err := server.store.CreateTask(ctx, arg);
+	arg.RecurrenceRule, arg.RecurrenceStart, arg.RecurrenceTimezone, arg.RecurrenceExdates = recurrenceColumns(recurrence)
+
+	if req.Priority != "" {
+		arg.Priority = db.TaskPriority(req.Priority)
+	}
+
+	//Tags are referenced by name and created on the fly when the user doesn't have them yet
+
+	result, err := server.store.CreateTaskTx(ctx, db.CreateTaskTxParams{CreateTaskParams: arg, Tags: req.Tags, Reminders: reminders});
 
 	if err != nil {
+		if errors.Is(err, db.ErrTaskTooDeep) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err));
+			return;
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err));
 		return;
 	}
 
-	res := createTaskResponse {
-		ID: task.ID.String(),
-		Title: task.Title,
-		Description: task.Description.String,
-		DueDate: task.DueDate.String(),
-		ReminderDate: task.ReminderDate.Time.String(),
-		UserID: task.UserID.String(),
-	}
+	server.publishTaskEvent(ctx, webhook.EventTaskCreated, result.Task)
 
-	ctx.JSON(http.StatusOK, res);
+	ctx.JSON(http.StatusOK, newTaskTxResponse(result));
 }
 
 func (server *Server) getTaskByID (context *gin.Context) {
@@ -132,27 +437,26 @@ func (server *Server) getTaskByID (context *gin.Context) {
 		return;
 	}
 
-	task, err := server.store.GetTaskByID(context, uuid.MustParse(req.ID));
+	taskID, err := uuid.Parse(req.ID)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			context.JSON(http.StatusNotFound, errorResponse(err));
-			return;
-		}
-		context.JSON(http.StatusInternalServerError, errorResponse(err));
-		return;
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
-	res := createTaskResponse {
-		ID: task.ID.String(),
-		Title: task.Title,
-		Description: task.Description.String,
-		DueDate: task.DueDate.Format(time.RFC3339),
-		ReminderDate: task.ReminderDate.Time.Format(time.RFC3339),
-		UserID: task.UserID.String(),
+	user, ok := server.authorizedUser(context)
+
+	if !ok {
+		return
 	}
 
-	context.JSON(http.StatusOK, res);
+	task, ok := server.getOwnedTask(context, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	server.respondWithTask(context, task)
 }
 
 func (server *Server) getTasksByUser (context *gin.Context) {
@@ -163,35 +467,612 @@ func (server *Server) getTasksByUser (context *gin.Context) {
 		return;
 	}
 
+	userID, err := uuid.Parse(req.UserID)
+
+	if err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(context)
+
+	if !ok {
+		return
+	}
+
+	if userID != user.ID {
+		context.JSON(http.StatusForbidden, errorResponse(errors.New("tasks of other users can't be listed")))
+		return
+	}
+
+	server.listTasksOfUser(context, user, uuid.NullUUID{})
+}
+
+func (server *Server) listTasks(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	server.listTasksOfUser(ctx, user, uuid.NullUUID{})
+}
+
+// listTasksOfUser responds with one page of the user's tasks, filtered and sorted by the query string.
+// A valid projectID limits the listing to that project and takes precedence over the project_id filter.
+func (server *Server) listTasksOfUser(ctx *gin.Context, user db.User, projectID uuid.NullUUID) {
+	var req listTasksRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
-	tasks, err := server.store.GetTasksByUser(context, uuid.MustParse(req.UserID));
+	arg := db.ListTasksParams {
+		UserID: user.ID,
+		SortBy: db.TaskSortDueDate,
+		Descending: req.Order == "desc",
+		Limit: pageLimit(req.Limit),
+	}
+
+	if req.Sort != "" {
+		arg.SortBy = db.TaskSortField(req.Sort)
+	}
+
+	if projectID.Valid {
+		arg.ProjectID = projectID
+	} else if req.ProjectID != "" {
+		arg.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(req.ProjectID), Valid: true}
+	}
+
+	if req.Status != "" {
+		arg.Status = db.NullTaskStatus{TaskStatus: db.TaskStatus(req.Status), Valid: true}
+	}
+
+	arg.DueFrom = sql.NullTime{Time: req.DueFrom, Valid: !req.DueFrom.IsZero()}
+	arg.DueTo = sql.NullTime{Time: req.DueTo, Valid: !req.DueTo.IsZero()}
+	arg.CreatedFrom = sql.NullTime{Time: req.CreatedFrom, Valid: !req.CreatedFrom.IsZero()}
+	arg.CreatedTo = sql.NullTime{Time: req.CreatedTo, Valid: !req.CreatedTo.IsZero()}
+	arg.UpdatedFrom = sql.NullTime{Time: req.UpdatedFrom, Valid: !req.UpdatedFrom.IsZero()}
+	arg.UpdatedTo = sql.NullTime{Time: req.UpdatedTo, Valid: !req.UpdatedTo.IsZero()}
+
+	if req.HasReminder != nil {
+		arg.HasReminder = sql.NullBool{Bool: *req.HasReminder, Valid: true}
+	}
+
+	if req.Query != "" {
+		arg.Search = sql.NullString{String: req.Query, Valid: true}
+	}
+
+	//Clients tend to send "work, home" or a trailing comma
+	for _, name := range strings.Split(req.Tags, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			arg.Tags = append(arg.Tags, name)
+		}
+	}
+
+	if len(arg.Tags) > 0 {
+		arg.MatchAllTags = req.TagMode != "any"
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeTaskCursor(arg.SortBy, req.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.After = cursor
+	}
+
+	//Fetch one extra row to find out whether there is a next page
+
+	pageSize := arg.Limit
+	arg.Limit++
+
+	tasks, err := server.store.ListTasks(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := listTasksResponse {
+		Tasks: []createTaskResponse{},
+	}
+
+	if len(tasks) > int(pageSize) {
+		tasks = tasks[:pageSize]
+		nextCursor := encodeTaskCursor(arg.SortBy, tasks[len(tasks)-1])
+		response.NextCursor = &nextCursor
+	}
+
+	response.Tasks, err = server.taskResponses(ctx, tasks)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) updateTask(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateTaskRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	task, ok := server.getOwnedTask(ctx, user, taskID)
+
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateTaskTxParams {
+		UpdateTaskParams: db.UpdateTaskParams {
+			ID: task.ID,
+		},
+	}
+
+	//Keep track of the resulting due date so new reminders can be validated against it
+
+	dueDate := task.DueDate
+
+	if req.Title != nil {
+		arg.Title = sql.NullString{String: *req.Title, Valid: true}
+	}
+
+	if req.DueDate != nil {
+		dueDate, err = time.Parse(time.RFC3339, *req.DueDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.DueDate = sql.NullTime{Time: dueDate, Valid: true}
+	}
+
+	if req.Description.Set {
+		arg.SetDescription = true
+		arg.Description = sql.NullString{String: req.Description.Value, Valid: req.Description.Valid}
+	}
+
+	//Setting reminder_date replaces all reminders of the task with that one, or none when it's null
+
+	if req.ReminderDate.Set && req.Reminders != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errConflictingReminders))
+		return
+	}
+
+	if req.ReminderDate.Set {
+		arg.SetReminders = true
+		arg.Reminders = []db.ReminderSpec{}
+
+		if req.ReminderDate.Valid {
+			reminder, err := parseReminderDate(req.ReminderDate.Value, dueDate)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			arg.Reminders = append(arg.Reminders, reminder)
+		}
+	}
+
+	if req.Reminders != nil {
+		arg.SetReminders = true
+		arg.Reminders, err = parseReminders(*req.Reminders, dueDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if req.Status != nil {
+		arg.Status = db.NullTaskStatus{TaskStatus: db.TaskStatus(*req.Status), Valid: true}
+	}
+
+	if req.Priority != nil {
+		arg.Priority = db.NullTaskPriority{TaskPriority: db.TaskPriority(*req.Priority), Valid: true}
+	}
+
+	if req.Important != nil {
+		arg.Important = sql.NullBool{Bool: *req.Important, Valid: true}
+	}
+
+	if req.BreakThroughQuietHours != nil {
+		arg.BreakThroughQuietHours = sql.NullBool{Bool: *req.BreakThroughQuietHours, Valid: true}
+	}
+
+	if req.Tags != nil {
+		arg.SetTags = true
+		arg.Tags = *req.Tags
+	}
+
+	if req.ProjectID.Set {
+		arg.SetProjectID = true
+
+		if req.ProjectID.Valid {
+			arg.ProjectID, ok = server.taskProject(ctx, user, req.ProjectID.Value)
+			if !ok {
+				return
+			}
+		}
+	}
+
+	if req.Recurrence.Set {
+		var recurrence *util.Recurrence
+
+		if req.Recurrence.Value != nil {
+			parsed, err := parseRecurrence(req.Recurrence.Value, dueDate)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			recurrence = &parsed
+		}
+
+		arg.SetRecurrence = true
+		arg.RecurrenceRule, arg.RecurrenceStart, arg.RecurrenceTimezone, arg.RecurrenceExdates = recurrenceColumns(recurrence)
+	}
+
+	//Relative reminders follow the due date, absolute ones have to stay before it
+
+	if arg.DueDate.Valid && !arg.SetReminders {
+		reminders, err := server.store.ListTaskReminders(ctx, task.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		for _, reminder := range reminders {
+			if !reminder.IsRelative() && !isValidReminder(reminder.RemindAt, dueDate) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(errReminderAfterDue))
+				return
+			}
+		}
+	}
+
+	result, err := server.store.UpdateTaskTx(ctx, arg)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			context.JSON(http.StatusNotFound, errorResponse(err));
-			return;
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
 		}
-		context.JSON(http.StatusInternalServerError, errorResponse(err));
-		return;
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	var res []createTaskResponse
+	eventType := webhook.EventTaskUpdated
 
-	for _, task := range tasks {
-		res = append(res, createTaskResponse {
-			ID: task.ID.String(),
-			Title: task.Title,
-			Description: task.Description.String,
-			DueDate: task.DueDate.Format(time.RFC3339),
-			ReminderDate: task.ReminderDate.Time.Format(time.RFC3339),
-			UserID: task.UserID.String(),
-		})
-	}
-
-	response := getTasksByUserResponse {
-		UserID: req.UserID,
+	if arg.Status.Valid && arg.Status.TaskStatus == db.TaskStatusDone && task.Status != db.TaskStatusDone {
+		eventType = webhook.EventTaskCompleted
+	}
+
+	server.publishTaskTreeEvents(ctx, eventType, result.Task, result.Descendants)
+
+	server.respondWithTask(ctx, result.Task)
+}
+
+func (server *Server) replaceTask(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req replaceTaskRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if _, ok := server.getOwnedTask(ctx, user, taskID); !ok {
+		return
+	}
+
+	dueDate, err := time.Parse(time.RFC3339, req.DueDate)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	//PUT replaces the whole task, so omitted optional fields are cleared
+
+	reminders, err := parseReminders(req.Reminders, dueDate)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ReminderDate != nil {
+		reminder, err := parseReminderDate(*req.ReminderDate, dueDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if len(reminders) > maxTaskReminders {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTooManyReminders))
+		return
+	}
+
+	var description sql.NullString
+
+	if req.Description != nil {
+		description = sql.NullString{String: *req.Description, Valid: true}
+	}
+
+	var projectID uuid.NullUUID
+
+	if req.ProjectID != nil {
+		projectID, ok = server.taskProject(ctx, user, *req.ProjectID)
+		if !ok {
+			return
+		}
+	}
+
+	var recurrence *util.Recurrence
+
+	if req.Recurrence != nil {
+		parsed, err := parseRecurrence(req.Recurrence, dueDate)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		recurrence = &parsed
+	}
+
+	priority := db.TaskPriorityNone
+
+	if req.Priority != "" {
+		priority = db.TaskPriority(req.Priority)
+	}
+
+	arg := db.UpdateTaskTxParams {
+		UpdateTaskParams: db.UpdateTaskParams {
+			ID: taskID,
+			Title: sql.NullString{String: req.Title, Valid: true},
+			SetDescription: true,
+			Description: description,
+			DueDate: sql.NullTime{Time: dueDate, Valid: true},
+			Priority: db.NullTaskPriority{TaskPriority: priority, Valid: true},
+			Important: sql.NullBool{Bool: req.Important, Valid: true},
+			BreakThroughQuietHours: sql.NullBool{Bool: req.BreakThroughQuietHours, Valid: true},
+			SetProjectID: true,
+			ProjectID: projectID,
+			SetRecurrence: true,
+		},
+		SetTags: true,
+		Tags: req.Tags,
+		SetReminders: true,
+		Reminders: reminders,
+	}
+
+	arg.RecurrenceRule, arg.RecurrenceStart, arg.RecurrenceTimezone, arg.RecurrenceExdates = recurrenceColumns(recurrence)
+
+	result, err := server.store.UpdateTaskTx(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishTaskEvent(ctx, webhook.EventTaskUpdated, result.Task)
+
+	server.respondWithTask(ctx, result.Task)
+}
+
+func (server *Server) trashTask(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.TrashTaskParams {
+		ID: taskID,
+		UserID: user.ID,
+	}
+
+	result, err := server.store.TrashTaskTx(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishTaskTreeEvents(ctx, webhook.EventTaskDeleted, result.Task, result.Descendants)
+
+	server.respondWithTask(ctx, result.Task)
+}
+
+func (server *Server) getTrashedTasks(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	tasks, err := server.store.GetTrashedTasksByUser(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res, err := server.taskResponses(ctx, tasks)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := listTasksResponse {
 		Tasks: res,
 	}
 
-	context.JSON(http.StatusOK, response);
-}
\ No newline at end of file
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (server *Server) restoreTask(ctx *gin.Context) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	arg := db.RestoreTaskParams {
+		ID: taskID,
+		UserID: user.ID,
+	}
+
+	result, err := server.store.RestoreTaskTx(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishTaskTreeEvents(ctx, webhook.EventTaskUpdated, result.Task, result.Descendants)
+
+	server.respondWithTask(ctx, result.Task)
+}
+
+func (server *Server) completeTask(ctx *gin.Context) {
+	server.setTaskCompletion(ctx, server.store.CompleteTaskTx, webhook.EventTaskCompleted)
+}
+
+func (server *Server) reopenTask(ctx *gin.Context) {
+	//Subtasks stay done when their parent is reopened
+	reopen := func(ctx context.Context, id uuid.UUID) (db.TaskTreeTxResult, error) {
+		task, err := server.store.ReopenTask(ctx, id)
+		return db.TaskTreeTxResult{Task: task}, err
+	}
+
+	server.setTaskCompletion(ctx, reopen, webhook.EventTaskUpdated)
+}
+
+// setTaskCompletion runs one of the complete/reopen queries against the task in
+// the URI and publishes eventType for it and the subtasks that changed along
+// with it when it succeeds.
+func (server *Server) setTaskCompletion(ctx *gin.Context, update func(context.Context, uuid.UUID) (db.TaskTreeTxResult, error), eventType webhook.EventType) {
+	var uri getTaskByIDRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	taskID, err := uuid.Parse(uri.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if _, ok := server.getOwnedTask(ctx, user, taskID); !ok {
+		return
+	}
+
+	result, err := update(ctx, taskID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishTaskTreeEvents(ctx, eventType, result.Task, result.Descendants)
+
+	server.respondWithTask(ctx, result.Task)
+}
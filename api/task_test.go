@@ -2,13 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	mockdb "m1thrandir225/your_time/db/mock"
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"m1thrandir225/your_time/token"
 	"m1thrandir225/your_time/util"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -23,9 +27,15 @@ func TestCreateTaskApi(t *testing.T) {
 
 	task := randomTask(user);
 
+	archivedProject := randomProject(user)
+	archivedProject.Archived = true
+
+	reminders := []db.ReminderSpec{{RemindAt: task.DueDate}}
+
 	testCases := []struct {
 		name 	string
 		body 	gin.H
+		setupAuth 	func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		build 	func(store *mockdb.MockStore)
 		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
@@ -36,24 +46,195 @@ func TestCreateTaskApi(t *testing.T) {
 				"description": task.Description.String,
 				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
 				"due_date": "2021-07-13T15:28:51.818095+00:00",
-				"user_id": user.ID.String(),
 			},
 			build: func(store *mockdb.MockStore) {
 				arg := db.CreateTaskParams {
 					Title: 	 task.Title,
 					Description: task.Description,
-					ReminderDate: task.ReminderDate,
 					DueDate: task.DueDate,
 					UserID: user.ID,
+					Priority: db.TaskPriorityNone,
 				}
 
-				store.EXPECT().CreateTask(gomock.Any(), gomock.Eq(arg)).Times(1).Return(task, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(db.CreateTaskTxParams{CreateTaskParams: arg, Reminders: reminders})).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCreated)
 			},	
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				
 			},
 		},
+		{
+			name: "WithTags",
+			body: gin.H {
+				"title": task.Title,
+				"description": task.Description.String,
+				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"tags": []string{"work", "home"},
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskTxParams {
+					CreateTaskParams: db.CreateTaskParams {
+						Title: 	 task.Title,
+						Description: task.Description,
+							DueDate: task.DueDate,
+						UserID: user.ID,
+						Priority: db.TaskPriorityNone,
+					},
+					Tags: []string{"work", "home"},
+					Reminders: reminders,
+				}
+
+				result := db.TaskTxResult {
+					Task: task,
+					Tags: []db.GetTagsForTasksRow{
+						{TaskID: task.ID, ID: uuid.New(), Name: "home", Color: defaultTagColor},
+						{TaskID: task.ID, ID: uuid.New(), Name: "work", Color: defaultTagColor},
+					},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCreated)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res createTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Tags, 2)
+				require.Equal(t, "home", res.Tags[0].Name)
+				require.Equal(t, "work", res.Tags[1].Name)
+			},
+		},
+		{
+			name: "WithRecurrence",
+			body: gin.H {
+				"title": task.Title,
+				"description": task.Description.String,
+				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"recurrence": gin.H {
+					"rule": "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+					"timezone": "Europe/Berlin",
+				},
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskParams {
+					Title: 	 task.Title,
+					Description: task.Description,
+					DueDate: task.DueDate,
+					UserID: user.ID,
+					Priority: db.TaskPriorityNone,
+					RecurrenceRule: sql.NullString{String: "FREQ=WEEKLY;BYDAY=MO,WE,FR", Valid: true},
+					RecurrenceStart: sql.NullTime{Time: task.DueDate, Valid: true},
+					RecurrenceTimezone: sql.NullString{String: "Europe/Berlin", Valid: true},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(db.CreateTaskTxParams{CreateTaskParams: arg, Reminders: reminders})).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCreated)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRecurrence",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"recurrence": gin.H {
+					"rule": "FREQ=FORTNIGHTLY",
+				},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DefaultReminders",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskTxParams {
+					CreateTaskParams: db.CreateTaskParams {
+						Title: 	 task.Title,
+						DueDate: task.DueDate,
+						UserID: user.ID,
+						Priority: db.TaskPriorityNone,
+					},
+					Reminders: []db.ReminderSpec{
+						{OffsetSeconds: sql.NullInt32{Int32: 86400, Valid: true}},
+						{OffsetSeconds: sql.NullInt32{Int32: 900, Valid: true}},
+					},
+				}
+
+				defaults := []db.DefaultReminder{
+					{ID: uuid.New(), UserID: user.ID, OffsetSeconds: 86400},
+					{ID: uuid.New(), UserID: user.ID, OffsetSeconds: 900},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListDefaultReminders(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(defaults, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCreated)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoReminders",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"reminders": []gin.H{},
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.CreateTaskTxParams {
+					CreateTaskParams: db.CreateTaskParams {
+						Title: 	 task.Title,
+						DueDate: task.DueDate,
+						UserID: user.ID,
+						Priority: db.TaskPriorityNone,
+					},
+					Reminders: []db.ReminderSpec{},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListDefaultReminders(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCreated)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ArchivedProject",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"project_id": archivedProject.ID.String(),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetProjectByID(gomock.Any(), gomock.Eq(archivedProject.ID)).Times(1).Return(archivedProject, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InternalError",
 			body: gin.H {
@@ -61,40 +242,39 @@ func TestCreateTaskApi(t *testing.T) {
 				"description": task.Description.String,
 				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
 				"due_date": "2021-07-13T15:28:51.818095+00:00",
-				"user_id": user.ID.String(),
 			},
 			build: func(store *mockdb.MockStore) {
 				arg := db.CreateTaskParams {
 					Title: 	 task.Title,
 					Description: task.Description,
-					ReminderDate: task.ReminderDate,
 					DueDate: task.DueDate,
 					UserID: user.ID,
+					Priority: db.TaskPriorityNone,
 				}
 
-				store.EXPECT().CreateTask(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Task{}, sql.ErrConnDone)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Eq(db.CreateTaskTxParams{CreateTaskParams: arg, Reminders: reminders})).Times(1).Return(db.TaskTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 		{
-			name: "InvalidUserID",
+			name: "NoAuthorization",
 			body: gin.H {
 				"title": task.Title,
 				"description": task.Description.String,
 				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
 				"due_date": "2021-07-13T15:28:51.818095+00:00",
-				"user_id": "invalid",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
 			build: func(store *mockdb.MockStore) {
-
-
-				store.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
 		{
@@ -104,11 +284,10 @@ func TestCreateTaskApi(t *testing.T) {
 				"description": task.Description.String,
 				"reminder_date": "2021-07-13T15:28:51.818095+00:00",
 				"due_date": "invalid",
-				"user_id": user.ID.String(),
 			},
 			build: func(store *mockdb.MockStore) {
 
-				store.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -123,10 +302,9 @@ func TestCreateTaskApi(t *testing.T) {
 				"description": task.Description.String,
 				"reminder_date": "invalid",
 				"due_date": "2021-07-13T15:28:51.818095+00:00",
-				"user_id": user.ID.String(),
 			},
 			build: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTaskTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -161,6 +339,12 @@ func TestCreateTaskApi(t *testing.T) {
 
 			require.NoError(t, err)
 
+			if tc.setupAuth != nil {
+				tc.setupAuth(t, request, server.tokenMaker)
+			} else {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+			}
+
 			server.router.ServeHTTP(recorder, request)
 
 			tc.checkResponse(t, recorder)
@@ -174,6 +358,14 @@ func TestGetTaskByIDApi(t *testing.T) {
 
 	task := randomTask(user)
 
+	otherTask := randomTask(randomUser())
+
+	tag := randomTag(user)
+
+	tags := []db.GetTagsForTasksRow{
+		{TaskID: task.ID, ID: tag.ID, Name: tag.Name, Color: tag.Color},
+	}
+
 	testCases := []struct {
 		name 	string
 		taskID 	string
@@ -184,10 +376,30 @@ func TestGetTaskByIDApi(t *testing.T) {
 			name: "OK",
 			taskID: task.ID.String(),
 			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
 				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Eq([]uuid.UUID{task.ID})).Times(1).Return(tags, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res createTaskResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, task.ID.String(), res.ID)
+				require.Equal(t, []tagResponse{newTagResponse(tag)}, res.Tags)
+			},
+		},
+		{
+			name: "Forbidden",
+			taskID: otherTask.ID.String(),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(otherTask.ID)).Times(1).Return(otherTask, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 		{
@@ -197,13 +409,14 @@ func TestGetTaskByIDApi(t *testing.T) {
 				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name: "ErrNoRows",
 			taskID: task.ID.String(),
 			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
 				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, sql.ErrNoRows)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -233,6 +446,8 @@ func TestGetTaskByIDApi(t *testing.T) {
 
 			require.NoError(t, err)
 
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
 			server.router.ServeHTTP(recorder, request)
 
 			tc.checkResponse(t, recorder)
@@ -247,6 +462,7 @@ func TestGetTasksByUserApi(t *testing.T) {
 	testCases := []struct {
 		name 	string
 		userID 	string
+		query 	string
 		build 	func(store *mockdb.MockStore)
 		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
 	} {
@@ -254,7 +470,17 @@ func TestGetTasksByUserApi(t *testing.T) {
 			name: "OK",
 			userID: user.ID.String(),
 			build: func(store *mockdb.MockStore) {
-				store.EXPECT().GetTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{task}, nil)
+				arg := db.ListTasksParams {
+					UserID: user.ID,
+					SortBy: db.TaskSortDueDate,
+					Limit: defaultPageSize + 1,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Task{task}, nil)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -264,27 +490,63 @@ func TestGetTasksByUserApi(t *testing.T) {
 			name: "InvalidID",
 			userID: "invalid",
 			build: func(store *mockdb.MockStore) {
-				store.EXPECT().GetTasksByUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		}, 
 		{
-			name: "ErrNoRows",
+			name: "StatusFilter",
 			userID: user.ID.String(),
+			query: "?status=done",
+			build: func(store *mockdb.MockStore) {
+				arg := db.ListTasksParams {
+					UserID: user.ID,
+					Status: db.NullTaskStatus{TaskStatus: db.TaskStatusDone, Valid: true},
+					SortBy: db.TaskSortDueDate,
+					Limit: defaultPageSize + 1,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Task{task}, nil)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden",
+			userID: uuid.New().String(),
 			build: func(store *mockdb.MockStore) {
-				store.EXPECT().GetTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{}, sql.ErrNoRows)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidStatus",
+			userID: user.ID.String(),
+			query: "?status=finished",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name: "InternalError",
 			userID: user.ID.String(),
 			build: func(store *mockdb.MockStore) {
-				store.EXPECT().GetTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{}, sql.ErrConnDone)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.Task{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -306,18 +568,898 @@ func TestGetTasksByUserApi(t *testing.T) {
 		server := newTestServer(t, store)
 		recorder := httptest.NewRecorder()
 
-		url := "/tasks/user/" + tc.userID
+		url := "/tasks/user/" + tc.userID + tc.query
 
 		request, err := http.NewRequest(http.MethodGet, url, nil)
 
 		require.NoError(t, err)
 
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
 		server.router.ServeHTTP(recorder, request)
 
 		tc.checkResponse(t, recorder)
 	}
 }
 
+func TestListTasksApi(t *testing.T) {
+	user := randomUser()
+
+	tasks := make([]db.Task, 3)
+	for i := range tasks {
+		tasks[i] = randomTask(user)
+	}
+
+	testCases := []struct {
+		name 	string
+		query 	func() string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	} {
+		{
+			name: "NextPage",
+			query: func() string {
+				return "?limit=2&sort=title&order=desc&q=milk&has_reminder=true&due_from=2021-01-01T00:00:00Z"
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.ListTasksParams {
+					UserID: user.ID,
+					DueFrom: sql.NullTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					HasReminder: sql.NullBool{Bool: true, Valid: true},
+					Search: sql.NullString{String: "milk", Valid: true},
+					SortBy: db.TaskSortTitle,
+					Descending: true,
+					Limit: 3,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+						require.Equal(t, arg.DueFrom.Time.Unix(), got.DueFrom.Time.Unix())
+						got.DueFrom = arg.DueFrom
+						require.Equal(t, arg, got)
+						return tasks, nil
+					})
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listTasksResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Tasks, 2)
+				require.NotNil(t, res.NextCursor)
+
+				cursor, err := decodeTaskCursor(db.TaskSortTitle, *res.NextCursor)
+				require.NoError(t, err)
+				require.Equal(t, tasks[1].ID, cursor.ID)
+				require.Equal(t, tasks[1].Title, cursor.Value)
+			},
+		},
+		{
+			name: "TagFilter",
+			query: func() string {
+				return "?tags=work,home&tag_mode=any"
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+						require.Equal(t, []string{"work", "home"}, got.Tags)
+						require.False(t, got.MatchAllTags)
+						return []db.Task{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listTasksResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Empty(t, res.Tasks)
+				require.Nil(t, res.NextCursor)
+			},
+		},
+		{
+			name: "TagFilterSpaces",
+			query: func() string {
+				return "?tags=" + url.QueryEscape(" work , ,home,")
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+						require.Equal(t, []string{"work", "home"}, got.Tags)
+						require.True(t, got.MatchAllTags)
+						return []db.Task{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyTagFilter",
+			query: func() string {
+				return "?tags=,"
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+						require.Empty(t, got.Tags)
+						return []db.Task{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTagMode",
+			query: func() string {
+				return "?tags=work&tag_mode=some"
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "LastPage",
+			query: func() string {
+				return "?cursor=" + encodeTaskCursor(db.TaskSortDueDate, tasks[0])
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListTasksParams) ([]db.Task, error) {
+						require.NotNil(t, got.After)
+						require.Equal(t, tasks[0].ID, got.After.ID)
+						return tasks[1:], nil
+					})
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listTasksResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Tasks, 2)
+				require.Nil(t, res.NextCursor)
+			},
+		},
+		{
+			name: "CursorOfOtherSort",
+			query: func() string {
+				return "?sort=title&cursor=" + encodeTaskCursor(db.TaskSortDueDate, tasks[0])
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSort",
+			query: func() string {
+				return "?sort=priority"
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/tasks" + tc.query(), nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name 	string
+		taskID 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"title": "new title",
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpdateTaskParams {
+					ID: task.ID,
+					Title: sql.NullString{String: "new title", Valid: true},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Eq(db.UpdateTaskTxParams{UpdateTaskParams: arg})).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ClearDescription",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"description": nil,
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpdateTaskParams {
+					ID: task.ID,
+					SetDescription: true,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Eq(db.UpdateTaskTxParams{UpdateTaskParams: arg})).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "SetTags",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"tags": []string{"work"},
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpdateTaskTxParams {
+					UpdateTaskParams: db.UpdateTaskParams {
+						ID: task.ID,
+					},
+					SetTags: true,
+					Tags: []string{"work"},
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ReminderAfterDueDate",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"reminder_date": task.DueDate.Add(time.Hour).Format(time.RFC3339),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DueDateBeforeReminder",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"due_date": task.DueDate.Add(-time.Hour).Format(time.RFC3339),
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListTaskReminders(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.TaskReminder{{ID: uuid.New(), TaskID: task.ID, RemindAt: task.DueDate}}, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			taskID: "invalid",
+			body: gin.H {
+				"title": "new title",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			taskID: task.ID.String(),
+			body: gin.H {
+				"title": "new title",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, sql.ErrNoRows)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			url := "/tasks/" + tc.taskID
+
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReplaceTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+			},
+			build: func(store *mockdb.MockStore) {
+				//Omitted priority, importance and quiet hours flag are reset
+				arg := db.UpdateTaskParams {
+					ID: task.ID,
+					Title: sql.NullString{String: task.Title, Valid: true},
+					SetDescription: true,
+					DueDate: sql.NullTime{Time: task.DueDate, Valid: true},
+					Priority: db.NullTaskPriority{TaskPriority: db.TaskPriorityNone, Valid: true},
+					Important: sql.NullBool{Bool: false, Valid: true},
+					BreakThroughQuietHours: sql.NullBool{Bool: false, Valid: true},
+					SetProjectID: true,
+					SetRecurrence: true,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Eq(db.UpdateTaskTxParams{UpdateTaskParams: arg, SetTags: true, SetReminders: true, Reminders: []db.ReminderSpec{}})).Times(1).Return(db.TaskTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PriorityAndImportant",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"priority": "high",
+				"important": true,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					UpdateTaskTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTaskTxParams) (db.TaskTxResult, error) {
+						require.Equal(t, db.NullTaskPriority{TaskPriority: db.TaskPriorityHigh, Valid: true}, arg.Priority)
+						require.Equal(t, sql.NullBool{Bool: true, Valid: true}, arg.Important)
+						return db.TaskTxResult{Task: task}, nil
+					})
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BreakThroughQuietHours",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"break_through_quiet_hours": true,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					UpdateTaskTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTaskTxParams) (db.TaskTxResult, error) {
+						require.Equal(t, sql.NullBool{Bool: true, Valid: true}, arg.BreakThroughQuietHours)
+						return db.TaskTxResult{Task: task}, nil
+					})
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPriority",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+				"priority": "whenever",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingTitle",
+			body: gin.H {
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H {
+				"title": task.Title,
+				"due_date": "2021-07-13T15:28:51.818095+00:00",
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, sql.ErrNoRows)
+				store.EXPECT().UpdateTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			url := "/tasks/" + task.ID.String()
+
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTrashTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name 	string
+		taskID 	string
+		setupAuth 	func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			taskID: task.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.TrashTaskParams {
+					ID: task.ID,
+					UserID: user.ID,
+				}
+
+				trashed := task
+				trashed.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				subtask := randomTask(user)
+				subtask.ParentID = uuid.NullUUID{UUID: task.ID, Valid: true}
+				subtask.DeletedAt = trashed.DeletedAt
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					TrashTaskTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TaskTreeTxResult{Task: trashed, Descendants: []db.Task{subtask}}, nil)
+				//The subtask trashed along with the task gets an event of its own
+				expectWebhookEvent(t, store, webhook.EventTaskDeleted)
+				expectWebhookEvent(t, store, webhook.EventTaskDeleted)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "deleted_at")
+			},
+		},
+		{
+			name: "NotFound",
+			taskID: task.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().TrashTaskTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TaskTreeTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			taskID: task.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().TrashTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/tasks/" + tc.taskID
+
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTrashedTasksApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+	task.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().GetTrashedTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{task}, nil)
+	store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Eq([]uuid.UUID{task.ID})).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+	store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+	store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/tasks/trash", nil)
+
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), task.ID.String())
+}
+
+func TestRestoreTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			build: func(store *mockdb.MockStore) {
+				arg := db.RestoreTaskParams {
+					ID: task.ID,
+					UserID: user.ID,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RestoreTaskTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TaskTreeTxResult{Task: task}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotInTrash",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RestoreTaskTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TaskTreeTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/tasks/" + task.ID.String() + "/restore"
+
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCompleteTaskApi(t *testing.T) {
+	user := randomUser()
+
+	task := randomTask(user)
+
+	testCases := []struct {
+		name 	string
+		path 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Complete",
+			path: "/complete",
+			build: func(store *mockdb.MockStore) {
+				done := task
+				done.Status = db.TaskStatusDone
+				done.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				subtask := randomTask(user)
+				subtask.ParentID = uuid.NullUUID{UUID: task.ID, Valid: true}
+				subtask.Status = db.TaskStatusDone
+				subtask.CompletedAt = done.CompletedAt
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					CompleteTaskTx(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(db.TaskTreeTxResult{Task: done, Descendants: []db.Task{subtask}}, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskCompleted)
+				expectWebhookEvent(t, store, webhook.EventTaskCompleted)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "completed_at")
+			},
+		},
+		{
+			name: "Reopen",
+			path: "/reopen",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ReopenTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				expectWebhookEvent(t, store, webhook.EventTaskUpdated)
+				store.EXPECT().GetTagsForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetTagsForTasksRow{}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().GetSubtaskRollups(gomock.Any(), gomock.Any()).Times(1).Return([]db.GetSubtaskRollupsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "completed_at")
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/complete",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, sql.ErrNoRows)
+				store.EXPECT().CompleteTaskTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := "/tasks/" + task.ID.String() + tc.path
+
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func randomTask(user db.User) db.Task {
 	dueDate, err := time.Parse(time.RFC3339, "2021-07-13T15:28:51.818095+00:00")
 
@@ -331,8 +1473,9 @@ func randomTask(user db.User) db.Task {
 		UserID:       user.ID,
 		Title:        util.RandomString(6),
 		Description:  sql.NullString{String: util.RandomString(6), Valid: true},
-		ReminderDate: sql.NullTime{Time: dueDate, Valid: true},
 		DueDate:      dueDate,
+		Status:       db.TaskStatusOpen,
+		Priority:     db.TaskPriorityNone,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errSessionBlocked = errors.New("session is blocked")
+	errSessionExpired = errors.New("session has expired")
+	errSessionUser = errors.New("session belongs to another user")
+	errSessionClient = errors.New("session was issued to another client")
+)
+
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// sessionResponse is what a client needs to stay signed in: an access token
+// for requests and a refresh token to renew it once it expires.
+type sessionResponse struct {
+	SessionID uuid.UUID `json:"session_id"`
+	AccessToken string `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	RefreshToken string `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// issuedTokens is an access token and the refresh token of a new session.
+type issuedTokens struct {
+	accessToken string
+	accessPayload *token.Payload
+	refreshToken string
+	refreshPayload *token.Payload
+}
+
+func (server *Server) issueTokens(user db.User) (tokens issuedTokens, err error) {
+	tokens.accessToken, tokens.accessPayload, err = server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, server.config.AccessTokenDuration)
+
+	if err != nil {
+		return
+	}
+
+	tokens.refreshToken, tokens.refreshPayload, err = server.tokenMaker.CreateToken(user.Email, token.TokenTypeRefresh, server.config.RefreshTokenDuration)
+
+	return
+}
+
+// session is the session of the refresh token, bound to the client of the request.
+func (tokens issuedTokens) session(ctx *gin.Context, userID uuid.UUID, familyID uuid.UUID) db.CreateSessionParams {
+	return db.CreateSessionParams {
+		ID: tokens.refreshPayload.ID,
+		UserID: userID,
+		FamilyID: familyID,
+		UserAgent: ctx.Request.UserAgent(),
+		ClientIp: ctx.ClientIP(),
+		ExpiresAt: tokens.refreshPayload.ExpiredAt,
+		AccessTokenID: uuid.NullUUID{UUID: tokens.accessPayload.ID, Valid: true},
+		AccessTokenExpiresAt: sql.NullTime{Time: tokens.accessPayload.ExpiredAt, Valid: true},
+	}
+}
+
+func (tokens issuedTokens) response(session db.Session) sessionResponse {
+	return sessionResponse {
+		SessionID: session.ID,
+		AccessToken: tokens.accessToken,
+		AccessTokenExpiresAt: tokens.accessPayload.ExpiredAt,
+		RefreshToken: tokens.refreshToken,
+		RefreshTokenExpiresAt: tokens.refreshPayload.ExpiredAt,
+	}
+}
+
+// startSession signs user in on the client of the request, starting a new session family.
+func (server *Server) startSession(ctx *gin.Context, user db.User) (res sessionResponse, ok bool) {
+	tokens, err := server.issueTokens(user)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.CreateSession(ctx, tokens.session(ctx, user.ID, tokens.refreshPayload.ID))
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	return tokens.response(session), true
+}
+
+// renewAccessToken exchanges a refresh token for a new access token and a new
+// refresh token. Every refresh token works once: presenting one that was
+// already exchanged means it leaked, so the whole session family is blocked.
+func (server *Server) renewAccessToken(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if payload.Type != token.TokenTypeRefresh {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(token.ErrWrongTokenType))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, payload.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.IsBlocked {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionBlocked))
+		return
+	}
+
+	if session.RotatedAt.Valid {
+		server.blockSessionFamily(ctx, session)
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, payload.Email)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionUser))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.ID != session.UserID {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionUser))
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionExpired))
+		return
+	}
+
+	if session.UserAgent != ctx.Request.UserAgent() || session.ClientIp != ctx.ClientIP() {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errSessionClient))
+		return
+	}
+
+	if server.mustVerifyToSignIn(user) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+		return
+	}
+
+	tokens, err := server.issueTokens(user)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	next, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams {
+		PreviousID: session.ID,
+		Next: tokens.session(ctx, user.ID, session.FamilyID),
+	})
+
+	if err != nil {
+		//Another request exchanged the same refresh token first
+		if err == db.ErrSessionRotated {
+			server.blockSessionFamily(ctx, session)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens.response(next))
+}
+
+// blockSessionFamily signs out every session rotated from the same login as
+// session, revokes the access tokens issued through them and lets the user
+// know. It writes the response itself.
+func (server *Server) blockSessionFamily(ctx *gin.Context, session db.Session) {
+	//Sessions are blocked first so no new access tokens are issued meanwhile
+
+	if _, err := server.store.BlockSessionFamily(ctx, session.FamilyID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.revocations.RevokeFamily(ctx, session.FamilyID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notifySecurity(ctx, session.UserID, "You were signed out on one of your devices", "A sign-in of yours was used from two places at once, which can mean it was stolen. It has been signed out everywhere. If this wasn't you, change your password.", "session-family:" + session.FamilyID.String())
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(db.ErrSessionRotated))
+}
+
+// logoutUser revokes the access token of the request and signs out the
+// session it was issued with, so its refresh token stops working too.
+func (server *Server) logoutUser(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if err := server.revocations.Revoke(ctx, user.ID, payload); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	blocked, err := server.store.BlockAccessTokenSession(ctx, uuid.NullUUID{UUID: payload.ID, Valid: true})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"signed_out_sessions": blocked})
+}
+
+// logoutUserEverywhere signs the user out of every session and revokes every
+// access token issued to them that hasn't expired yet.
+func (server *Server) logoutUserEverywhere(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//Sessions are blocked first so no new access tokens are issued meanwhile
+
+	blocked, err := server.store.BlockUserSessions(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.revocations.RevokeUser(ctx, user.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//The access token of the request may not belong to a session
+
+	if err := server.revocations.Revoke(ctx, user.ID, payload); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notifySecurity(ctx, user.ID, "You were signed out everywhere", "All of your devices were signed out. If this wasn't you, change your password.", "logout-all:" + payload.ID.String())
+
+	ctx.JSON(http.StatusOK, gin.H{"signed_out_sessions": blocked})
+}
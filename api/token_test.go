@@ -0,0 +1,451 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/token"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUserAgent = "your-time-test/1.0"
+	//testClientIP is the remote address of requests made with httptest.NewRequest
+	testClientIP = "192.0.2.1"
+)
+
+func randomSession(user db.User, payload *token.Payload) db.Session {
+	return db.Session {
+		ID: payload.ID,
+		UserID: user.ID,
+		FamilyID: uuid.New(),
+		UserAgent: testUserAgent,
+		ClientIp: testClientIP,
+		ExpiresAt: payload.ExpiredAt,
+		CreatedAt: payload.IssuedAt,
+	}
+}
+
+func expectSecurityNotification(t *testing.T, store *mockdb.MockStore, user db.User) {
+	store.EXPECT().
+		CreateNotification(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (int64, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			require.Equal(t, db.NotificationTypeSecurity, arg.Type)
+			return 1, nil
+		})
+}
+
+func TestRenewAccessTokenApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		userAgent string
+		mode string
+		build func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session)
+	}{
+		{
+			name: "OK",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RotateSessionTxParams) (db.Session, error) {
+						require.Equal(t, session.ID, arg.PreviousID)
+						require.Equal(t, session.FamilyID, arg.Next.FamilyID)
+						require.Equal(t, testUserAgent, arg.Next.UserAgent)
+						require.Equal(t, testClientIP, arg.Next.ClientIp)
+						return db.Session{ID: arg.Next.ID, UserID: user.ID, FamilyID: arg.Next.FamilyID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res sessionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotEqual(t, session.ID, res.SessionID)
+				require.NotEmpty(t, res.AccessToken)
+				require.NotEmpty(t, res.RefreshToken)
+			},
+		},
+		{
+			name: "Reused",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+				store.EXPECT().
+					RevokeSessionFamilyAccessTokens(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return([]db.RevokeSessionFamilyAccessTokensRow{{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}}, nil)
+				expectSecurityNotification(t, store, user)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RotatedConcurrently",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, db.ErrSessionRotated)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(1), nil)
+				store.EXPECT().RevokeSessionFamilyAccessTokens(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return([]db.RevokeSessionFamilyAccessTokensRow{}, nil)
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Blocked",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				session.IsBlocked = true
+
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUser",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				session.UserID = uuid.New()
+
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "OtherClient",
+			userAgent: "curl/8.0",
+			build: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "EmailNotVerified",
+			userAgent: testUserAgent,
+			mode: emailVerificationLogin,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			userAgent: testUserAgent,
+			build: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Session{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			server := newTestServer(t, store)
+			server.config.EmailVerification = tc.mode
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeRefresh, time.Hour)
+			require.NoError(t, err)
+
+			session := randomSession(user, payload)
+
+			tc.build(store, session)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			request.Header.Set("User-Agent", tc.userAgent)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder, session)
+		})
+	}
+}
+
+func TestRenewAccessTokenInvalidTokenApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader([]byte(`{"refresh_token":"not-a-token"}`)))
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRenewAccessTokenWithAccessTokenApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(util.RandomEmail(), token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(gin.H{"refresh_token": accessToken})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestLogoutUserApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		path string
+		build func(store *mockdb.MockStore, payload *token.Payload)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Logout",
+			path: "/users/logout",
+			build: func(store *mockdb.MockStore, payload *token.Payload) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					RevokeToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RevokeTokenParams) error {
+						require.Equal(t, payload.ID, arg.ID)
+						require.Equal(t, user.ID, arg.UserID)
+						require.WithinDuration(t, payload.ExpiredAt, arg.ExpiresAt, time.Second)
+						return nil
+					})
+				store.EXPECT().
+					BlockAccessTokenSession(gomock.Any(), gomock.Eq(uuid.NullUUID{UUID: payload.ID, Valid: true})).
+					Times(1).
+					Return(int64(2), nil)
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"signed_out_sessions":2}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "LogoutEverywhere",
+			path: "/users/logout_all",
+			build: func(store *mockdb.MockStore, payload *token.Payload) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(3), nil)
+				store.EXPECT().
+					RevokeUserAccessTokens(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]db.RevokeUserAccessTokensRow{{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}}, nil)
+				store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"signed_out_sessions":3}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "RevokeFailed",
+			path: "/users/logout",
+			build: func(store *mockdb.MockStore, payload *token.Payload) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+				store.EXPECT().BlockAccessTokenSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			server := newTestServer(t, store)
+
+			accessToken, payload, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, time.Minute)
+			require.NoError(t, err)
+
+			tc.build(store, payload)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			request.Header.Set(authorizationHeaderKey, authorizationTypeBearer + " " + accessToken)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomUser()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().BlockAccessTokenSession(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+
+	server := newTestServer(t, store)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	for _, code := range []int{http.StatusOK, http.StatusUnauthorized} {
+		recorder := httptest.NewRecorder()
+
+		request := httptest.NewRequest(http.MethodPost, "/users/logout", nil)
+		request.Header.Set(authorizationHeaderKey, authorizationTypeBearer + " " + accessToken)
+
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, code, recorder.Code)
+	}
+}
+
+func TestRefreshTokenReuseRevokesAccessTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomUser()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	server := newTestServer(t, store)
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeRefresh, time.Hour)
+	require.NoError(t, err)
+
+	//The access token was issued with the refresh token that gets reused
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Email, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	session := randomSession(user, refreshPayload)
+	session.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(int64(2), nil)
+	store.EXPECT().
+		RevokeSessionFamilyAccessTokens(gomock.Any(), gomock.Eq(session.FamilyID)).
+		Times(1).
+		Return([]db.RevokeSessionFamilyAccessTokensRow{{ID: accessPayload.ID, ExpiresAt: accessPayload.ExpiredAt}}, nil)
+	expectSecurityNotification(t, store, user)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+
+	data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+	request.Header.Set("User-Agent", testUserAgent)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+
+	request = httptest.NewRequest(http.MethodGet, "/users/2fa", nil)
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer + " " + accessToken)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
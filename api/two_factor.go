@@ -0,0 +1,624 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/totp"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	//totpIssuer is the name authenticator apps list the account under
+	totpIssuer = "Your Time"
+	//loginChallengeDuration is how long a user has to enter their code after the password
+	loginChallengeDuration = 5 * time.Minute
+	//maxLoginChallengeAttempts is how many codes can be tried against one challenge
+	maxLoginChallengeAttempts = 5
+	//maxLoginChallenges is how many challenges a user gets per loginChallengeWindow
+	maxLoginChallenges = 5
+	loginChallengeWindow = 15 * time.Minute
+	//maxSecondFactorAttempts is how many codes in a row can be wrong before
+	//the second factor of a user is locked for secondFactorLockout, whichever
+	//endpoint they are tried on
+	maxSecondFactorAttempts = 10
+	secondFactorLockout = 15 * time.Minute
+)
+
+var (
+	errTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	errTwoFactorDisabled = errors.New("two-factor authentication is not enabled")
+	errInvalidCode = errors.New("invalid authentication code")
+	errLoginChallengeInvalid = errors.New("login challenge is invalid or has expired")
+	errTooManyLoginChallenges = errors.New("too many login attempts, try again later")
+	errTooManyCodeAttempts = errors.New("too many wrong codes, try again later")
+)
+
+type enrollTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type enrollTOTPResponse struct {
+	Secret string `json:"secret"`
+	//OtpauthURI is usually shown as a QR code for authenticator apps to scan
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type confirmTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type recoveryCodesResponse struct {
+	//RecoveryCodes are only ever shown once, just their hashes are stored
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type disableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	//Code is a TOTP code or a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+type twoFactorStatusResponse struct {
+	Enabled bool `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// loginChallengeResponse is what loginUser responds with when the password
+// is right but the user has two-factor authentication enabled.
+type loginChallengeResponse struct {
+	TwoFactorRequired bool `json:"two_factor_required"`
+	ChallengeToken string `json:"challenge_token"`
+	ChallengeExpiresAt time.Time `json:"challenge_expires_at"`
+}
+
+type verifyLoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	//Code is a TOTP code or a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+// getTwoFactorStatus tells whether the user has two-factor authentication
+// enabled and how many of their recovery codes are left.
+func (server *Server) getTwoFactorStatus(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, user.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, twoFactorStatusResponse{})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !credential.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusOK, twoFactorStatusResponse{})
+		return
+	}
+
+	left, err := server.store.CountUnusedRecoveryCodes(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, twoFactorStatusResponse {
+		Enabled: true,
+		RecoveryCodesLeft: left,
+	})
+}
+
+// enrollTOTP generates a TOTP secret for the user. Two-factor authentication
+// is only enabled once they confirm it with a first code, until then enrolling
+// again replaces the secret.
+func (server *Server) enrollTOTP(ctx *gin.Context) {
+	var req enrollTOTPRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := util.ComparePassword(user.Password, req.Password); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	secret, err := totp.NewSecret()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	credential, err := server.store.UpsertTOTPCredential(ctx, db.UpsertTOTPCredentialParams {
+		UserID: user.ID,
+		Secret: secret,
+	})
+
+	if err != nil {
+		//A confirmed secret isn't replaced
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(errTwoFactorEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollTOTPResponse {
+		Secret: credential.Secret,
+		OtpauthURI: totp.URI(totpIssuer, user.Email, credential.Secret),
+	})
+}
+
+// confirmTOTP enables two-factor authentication once the user entered a
+// first code from their authenticator app, and responds with their recovery codes.
+func (server *Server) confirmTOTP(ctx *gin.Context) {
+	var req confirmTOTPRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, user.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(db.ErrTOTPNotPending))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if credential.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errTwoFactorEnabled))
+		return
+	}
+
+	step, ok := totp.Validate(credential.Secret, req.Code, time.Now())
+
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCode))
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.EnableTOTPTx(ctx, db.EnableTOTPTxParams {
+		UserID: user.ID,
+		Step: step,
+		RecoveryCodeHashes: hashes,
+	})
+
+	if err != nil {
+		if err == db.ErrTOTPNotPending {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notifySecurity(ctx, user.ID, "Two-factor authentication was enabled", "Signing in to your account now takes a code from your authenticator app. If this wasn't you, reset your password right away.", "")
+
+	ctx.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of the user with new
+// ones. It takes a TOTP code, a recovery code would be replaced right away.
+func (server *Server) regenerateRecoveryCodes(ctx *gin.Context) {
+	var req confirmTOTPRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	credential, ok := server.confirmedTOTPCredential(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if _, err := server.verifySecondFactor(ctx, credential, req.Code, false); err != nil {
+		ctx.JSON(secondFactorErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.ReplaceRecoveryCodesTx(ctx, db.ReplaceRecoveryCodesTxParams {
+		UserID: user.ID,
+		RecoveryCodeHashes: hashes,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notifySecurity(ctx, user.ID, "New recovery codes were generated", "Your old recovery codes no longer work. If this wasn't you, reset your password right away.", "")
+
+	ctx.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// disableTwoFactor turns two-factor authentication off. It takes both the
+// password and a code, so neither a stolen access token nor a lost device
+// alone is enough.
+func (server *Server) disableTwoFactor(ctx *gin.Context) {
+	var req disableTwoFactorRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := util.ComparePassword(user.Password, req.Password); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	credential, ok := server.confirmedTOTPCredential(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if _, err := server.verifySecondFactor(ctx, credential, req.Code, true); err != nil {
+		ctx.JSON(secondFactorErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	if err := server.store.DisableTOTPTx(ctx, user.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.notifySecurity(ctx, user.ID, "Two-factor authentication was disabled", "Signing in to your account takes just your password again. If this wasn't you, reset your password right away.", "")
+
+	ctx.JSON(http.StatusOK, twoFactorStatusResponse{})
+}
+
+// verifyLoginChallenge finishes signing in a user with two-factor
+// authentication, exchanging the challenge token loginUser responded with and
+// a TOTP or recovery code for a session.
+func (server *Server) verifyLoginChallenge(ctx *gin.Context) {
+	var req verifyLoginChallengeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	challenge, err := server.store.GetLoginChallengeByTokenHash(ctx, util.HashSecretToken(req.ChallengeToken))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errLoginChallengeInvalid))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//Every code tried counts, whether or not it turns out right, so a
+	//challenge can't be used to guess codes
+	claimed, err := server.store.ClaimLoginChallengeAttempt(ctx, db.ClaimLoginChallengeAttemptParams {
+		ID: challenge.ID,
+		MaxAttempts: maxLoginChallengeAttempts,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if claimed == 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errLoginChallengeInvalid))
+		return
+	}
+
+	user, err := server.store.GetUserByID(ctx, challenge.UserID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errLoginChallengeInvalid))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	credential, err := server.store.GetTOTPCredential(ctx, user.ID)
+
+	if err != nil {
+		//Two-factor authentication was disabled after the challenge was issued
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errLoginChallengeInvalid))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	recovery, err := server.verifySecondFactor(ctx, credential, req.Code, true)
+
+	if err != nil {
+		ctx.JSON(secondFactorErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	used, err := server.store.UseLoginChallenge(ctx, challenge.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//Another request redeemed the same challenge first
+	if used == 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errLoginChallengeInvalid))
+		return
+	}
+
+	if recovery {
+		server.notifyRecoveryCodeUsed(ctx, user, challenge)
+	}
+
+	session, ok := server.startSession(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loginUserResponse {
+		sessionResponse: session,
+		User: newUserResponse(user),
+	})
+}
+
+// startLoginChallenge responds to a login with the right password of a user
+// with two-factor authentication, instead of signing them in right away.
+func (server *Server) startLoginChallenge(ctx *gin.Context, user db.User) {
+	recent, err := server.store.CountLoginChallengesSince(ctx, db.CountLoginChallengesSinceParams {
+		UserID: user.ID,
+		CreatedAt: time.Now().Add(-loginChallengeWindow),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//Only whoever knows the password gets here, so telling them is fine
+
+	if recent >= maxLoginChallenges {
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(errTooManyLoginChallenges))
+		return
+	}
+
+	challengeToken, err := util.NewSecretToken()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	challenge, err := server.store.CreateLoginChallenge(ctx, db.CreateLoginChallengeParams {
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(challengeToken),
+		ExpiresAt: time.Now().Add(loginChallengeDuration),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loginChallengeResponse {
+		TwoFactorRequired: true,
+		ChallengeToken: challengeToken,
+		ChallengeExpiresAt: challenge.ExpiresAt,
+	})
+}
+
+// confirmedTOTPCredential is the TOTP credential of user, if they have
+// two-factor authentication enabled. It writes the error response itself.
+func (server *Server) confirmedTOTPCredential(ctx *gin.Context, user db.User) (credential db.TotpCredential, ok bool) {
+	credential, err := server.store.GetTOTPCredential(ctx, user.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(errTwoFactorDisabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !credential.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errTwoFactorDisabled))
+		return
+	}
+
+	return credential, true
+}
+
+// verifySecondFactor checks code against the TOTP credential of a user and,
+// when allowRecovery is true, against their unused recovery codes. A code
+// that passes can't be used again. It reports whether a recovery code was used.
+// Too many wrong codes in a row lock the second factor for a while, so six
+// digits can't be guessed.
+func (server *Server) verifySecondFactor(ctx *gin.Context, credential db.TotpCredential, code string, allowRecovery bool) (recovery bool, err error) {
+	if !credential.ConfirmedAt.Valid {
+		return false, errTwoFactorDisabled
+	}
+
+	//Every code counts as wrong until it passes, so codes tried at the same
+	//time can't get past the limit
+	claimed, err := server.store.ClaimTOTPAttempt(ctx, db.ClaimTOTPAttemptParams {
+		WindowStart: time.Now().Add(-secondFactorLockout),
+		UserID: credential.UserID,
+		MaxAttempts: maxSecondFactorAttempts,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if claimed == 0 {
+		return false, errTooManyCodeAttempts
+	}
+
+	recovery, err = server.checkSecondFactor(ctx, credential, code, allowRecovery)
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := server.store.ResetTOTPAttempts(ctx, credential.UserID); err != nil {
+		log.Println("cannot reset second factor attempts:", err)
+	}
+
+	return recovery, nil
+}
+
+func (server *Server) checkSecondFactor(ctx *gin.Context, credential db.TotpCredential, code string, allowRecovery bool) (recovery bool, err error) {
+	if totp.IsCode(code) {
+		step, ok := totp.Validate(credential.Secret, code, time.Now())
+
+		if !ok {
+			return false, errInvalidCode
+		}
+
+		//A step at or before the last one used is a replayed code
+		used, err := server.store.UseTOTPStep(ctx, db.UseTOTPStepParams {
+			Step: step,
+			UserID: credential.UserID,
+		})
+
+		if err != nil {
+			return false, err
+		}
+
+		if used == 0 {
+			return false, errInvalidCode
+		}
+
+		return false, nil
+	}
+
+	if !allowRecovery {
+		return false, errInvalidCode
+	}
+
+	used, err := server.store.UseRecoveryCode(ctx, db.UseRecoveryCodeParams {
+		UserID: credential.UserID,
+		CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(code)),
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if used == 0 {
+		return false, errInvalidCode
+	}
+
+	return true, nil
+}
+
+func secondFactorErrorStatus(err error) int {
+	switch err {
+	case errInvalidCode:
+		return http.StatusUnauthorized
+	case errTwoFactorDisabled:
+		return http.StatusConflict
+	case errTooManyCodeAttempts:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// notifyRecoveryCodeUsed tells the user a recovery code was used to sign in
+// and how many are left, so they notice if it wasn't them.
+func (server *Server) notifyRecoveryCodeUsed(ctx *gin.Context, user db.User, challenge db.LoginChallenge) {
+	left, err := server.store.CountUnusedRecoveryCodes(ctx, user.ID)
+
+	body := "A recovery code was used to sign in to your account. If this wasn't you, reset your password right away."
+
+	if err == nil {
+		body = fmt.Sprintf("A recovery code was used to sign in to your account, %d are left. If this wasn't you, reset your password right away.", left)
+	}
+
+	server.notifySecurity(ctx, user.ID, "A recovery code was used", body, "recovery-code:" + challenge.ID.String())
+}
+
+// newRecoveryCodes generates recovery codes along with the hashes of them that are stored.
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	codes, err = totp.NewRecoveryCodes()
+
+	if err != nil {
+		return
+	}
+
+	hashes = make([]string, len(codes))
+
+	for i, code := range codes {
+		hashes[i] = util.HashSecretToken(totp.NormalizeRecoveryCode(code))
+	}
+
+	return
+}
@@ -0,0 +1,765 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/totp"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTOTPCredential(t *testing.T, user db.User, confirmed bool) db.TotpCredential {
+	secret, err := totp.NewSecret()
+	require.NoError(t, err)
+
+	credential := db.TotpCredential {
+		UserID: user.ID,
+		Secret: secret,
+		CreatedAt: time.Now(),
+	}
+
+	if confirmed {
+		credential.ConfirmedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	return credential
+}
+
+func currentCode(t *testing.T, credential db.TotpCredential) string {
+	code, err := totp.Code(credential.Secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// expectCodeAttempt expects a code of user to be counted against the limit
+// of wrong codes, and the count to be reset when accepted is true.
+func expectCodeAttempt(t *testing.T, store *mockdb.MockStore, user db.User, accepted bool) {
+	store.EXPECT().
+		ClaimTOTPAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ClaimTOTPAttemptParams) (int64, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			require.Equal(t, int32(maxSecondFactorAttempts), arg.MaxAttempts)
+			require.WithinDuration(t, time.Now().Add(-secondFactorLockout), arg.WindowStart, time.Second)
+			return 1, nil
+		})
+
+	resets := 0
+	if accepted {
+		resets = 1
+	}
+
+	store.EXPECT().ResetTOTPAttempts(gomock.Any(), gomock.Eq(user.ID)).Times(resets).Return(nil)
+}
+
+func TestEnrollTOTPApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UpsertTOTPCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertTOTPCredentialParams) (db.TotpCredential, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return db.TotpCredential{UserID: arg.UserID, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res enrollTOTPResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotEmpty(t, res.Secret)
+
+				uri, err := url.Parse(res.OtpauthURI)
+				require.NoError(t, err)
+				require.Equal(t, "otpauth", uri.Scheme)
+				require.Equal(t, "totp", uri.Host)
+				require.Equal(t, res.Secret, uri.Query().Get("secret"))
+				require.Equal(t, totpIssuer, uri.Query().Get("issuer"))
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": "wrong-password"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().UpsertTOTPCredential(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			body: gin.H{"password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().UpsertTOTPCredential(gomock.Any(), gomock.Any()).Times(1).Return(db.TotpCredential{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/2fa/totp", strings.NewReader(mustMarshal(t, tc.body)))
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestConfirmTOTPApi(t *testing.T) {
+	user := randomUser()
+
+	pending := randomTOTPCredential(t, user, false)
+	confirmed := randomTOTPCredential(t, user, true)
+
+	testCases := []struct {
+		name string
+		body func(t *testing.T) gin.H
+		build func(t *testing.T, store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": currentCode(t, pending)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.EnableTOTPTxParams) error {
+						require.Equal(t, user.ID, arg.UserID)
+						require.InDelta(t, totp.Step(time.Now()), arg.Step, totp.Skew)
+						require.Len(t, arg.RecoveryCodeHashes, totp.RecoveryCodeCount)
+						return nil
+					})
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res recoveryCodesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.RecoveryCodes, totp.RecoveryCodeCount)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": "12345x"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnrolled",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": "123456"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.TotpCredential{}, sql.ErrNoRows)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": currentCode(t, confirmed)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(confirmed, nil)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(t, store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/2fa/totp/confirm", strings.NewReader(mustMarshal(t, tc.body(t))))
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLoginTwoFactorApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+
+	credential := randomTOTPCredential(t, user, true)
+
+	testCases := []struct {
+		name string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Challenge",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				store.EXPECT().CountLoginChallengesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().
+					CreateLoginChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoginChallengeParams) (db.LoginChallenge, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.WithinDuration(t, time.Now().Add(loginChallengeDuration), arg.ExpiresAt, time.Second)
+						return db.LoginChallenge{ID: uuid.New(), UserID: arg.UserID, TokenHash: arg.TokenHash, ExpiresAt: arg.ExpiresAt}, nil
+					})
+				//No session until the second factor is verified
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res map[string]interface{}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, true, res["two_factor_required"])
+				require.NotEmpty(t, res["challenge_token"])
+				require.NotContains(t, res, "access_token")
+			},
+		},
+		{
+			name: "PendingEnrollment",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(randomTOTPCredential(t, user, false), nil)
+				store.EXPECT().CreateLoginChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, UserID: arg.UserID, FamilyID: arg.FamilyID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotEmpty(t, res.AccessToken)
+			},
+		},
+		{
+			name: "TooManyChallenges",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				store.EXPECT().CountLoginChallengesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(maxLoginChallenges), nil)
+				store.EXPECT().CreateLoginChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := mustMarshal(t, gin.H{"email": user.Email, "password": password})
+			request := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(body))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifyLoginChallengeApi(t *testing.T) {
+	user := randomUser()
+
+	credential := randomTOTPCredential(t, user, true)
+
+	challengeToken, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	challenge := db.LoginChallenge {
+		ID: uuid.New(),
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(challengeToken),
+		ExpiresAt: time.Now().Add(loginChallengeDuration),
+		CreatedAt: time.Now(),
+	}
+
+	recoveryCode := "abcd-efgh-ijkm-npqr"
+
+	expectChallenge := func(store *mockdb.MockStore) {
+		store.EXPECT().GetLoginChallengeByTokenHash(gomock.Any(), gomock.Eq(challenge.TokenHash)).Times(1).Return(challenge, nil)
+		store.EXPECT().
+			ClaimLoginChallengeAttempt(gomock.Any(), gomock.Eq(db.ClaimLoginChallengeAttemptParams{ID: challenge.ID, MaxAttempts: maxLoginChallengeAttempts})).
+			Times(1).
+			Return(int64(1), nil)
+		store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+		store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+	}
+
+	expectSession := func(store *mockdb.MockStore) {
+		store.EXPECT().UseLoginChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(int64(1), nil)
+		store.EXPECT().
+			CreateSession(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+				require.Equal(t, user.ID, arg.UserID)
+				return db.Session{ID: arg.ID, UserID: arg.UserID, FamilyID: arg.FamilyID}, nil
+			})
+	}
+
+	requireSession := func(t *testing.T, recorder *httptest.ResponseRecorder) {
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var res loginUserResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+		require.NotEmpty(t, res.AccessToken)
+		require.NotEmpty(t, res.RefreshToken)
+		require.Equal(t, user.Email, res.User.Email)
+	}
+
+	testCases := []struct {
+		name string
+		body func(t *testing.T) gin.H
+		build func(t *testing.T, store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "TOTPCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				expectChallenge(store)
+				expectCodeAttempt(t, store, user, true)
+				store.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UseTOTPStepParams) (int64, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.InDelta(t, totp.Step(time.Now()), arg.Step, totp.Skew)
+						return 1, nil
+					})
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(0)
+				expectSession(store)
+			},
+			checkResponse: requireSession,
+		},
+		{
+			name: "RecoveryCode",
+			body: func(t *testing.T) gin.H {
+				//Codes are accepted however they're typed
+				return gin.H{"challenge_token": challengeToken, "code": "ABCD EFGH IJKM NPQR"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				expectChallenge(store)
+				expectCodeAttempt(t, store, user, true)
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(db.UseRecoveryCodeParams {
+						UserID: user.ID,
+						CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(recoveryCode)),
+					})).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().CountUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(9), nil)
+				expectSecurityNotification(t, store, user)
+				expectSession(store)
+			},
+			checkResponse: requireSession,
+		},
+		{
+			name: "InvalidCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": "wxyz-wxyz-wxyz-wxyz"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				expectChallenge(store)
+				expectCodeAttempt(t, store, user, false)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseLoginChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				expectChallenge(store)
+				expectCodeAttempt(t, store, user, false)
+				//The step was used already
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AttemptsExhausted",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetLoginChallengeByTokenHash(gomock.Any(), gomock.Eq(challenge.TokenHash)).Times(1).Return(challenge, nil)
+				store.EXPECT().ClaimLoginChallengeAttempt(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownChallenge",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": "not-a-challenge", "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetLoginChallengeByTokenHash(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginChallenge{}, sql.ErrNoRows)
+				store.EXPECT().ClaimLoginChallengeAttempt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RedeemedConcurrently",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				expectChallenge(store)
+				expectCodeAttempt(t, store, user, true)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().UseLoginChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(t, store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/login/2fa", strings.NewReader(mustMarshal(t, tc.body(t))))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodesApi(t *testing.T) {
+	user := randomUser()
+
+	credential := randomTOTPCredential(t, user, true)
+
+	testCases := []struct {
+		name string
+		body func(t *testing.T) gin.H
+		build func(t *testing.T, store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				expectCodeAttempt(t, store, user, true)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().
+					ReplaceRecoveryCodesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ReplaceRecoveryCodesTxParams) error {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Len(t, arg.RecoveryCodeHashes, totp.RecoveryCodeCount)
+						return nil
+					})
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res recoveryCodesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.RecoveryCodes, totp.RecoveryCodeCount)
+			},
+		},
+		{
+			name: "RecoveryCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": "abcd-efgh-ijkm-npqr"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				expectCodeAttempt(t, store, user, false)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReplaceRecoveryCodesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "LockedOut",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				//Too many wrong codes were tried already, even the right one is turned away
+				store.EXPECT().ClaimTOTPAttempt(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReplaceRecoveryCodesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnabled",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"code": "123456"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.TotpCredential{}, sql.ErrNoRows)
+				store.EXPECT().ReplaceRecoveryCodesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(t, store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/2fa/recovery_codes", strings.NewReader(mustMarshal(t, tc.body(t))))
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisableTwoFactorApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+
+	credential := randomTOTPCredential(t, user, true)
+
+	testCases := []struct {
+		name string
+		body func(t *testing.T) gin.H
+		build func(t *testing.T, store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"password": password, "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				expectCodeAttempt(t, store, user, true)
+				store.EXPECT().UseTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(nil)
+				expectSecurityNotification(t, store, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res twoFactorStatusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.False(t, res.Enabled)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"password": "wrong-password", "code": currentCode(t, credential)}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: func(t *testing.T) gin.H {
+				return gin.H{"password": password, "code": "wxyz-wxyz-wxyz-wxyz"}
+			},
+			build: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(credential, nil)
+				expectCodeAttempt(t, store, user, false)
+				store.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(t, store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodDelete, "/users/2fa", strings.NewReader(mustMarshal(t, tc.body(t))))
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"log"
 	db "m1thrandir225/your_time/db/sqlc"
 	"m1thrandir225/your_time/util"
 	"net/http"
@@ -23,6 +25,8 @@ type userResponse struct {
 	FirstName string `json:"first_name"`
 	LastName string `json:"last_name"`
 	Email string `json:"email"`
+	Timezone string `json:"timezone"`
+	EmailVerified bool `json:"email_verified"`
 	CreatedAt string `json:"created_at"`
 }
 
@@ -32,6 +36,8 @@ func newUserResponse(user db.User) userResponse {
 		FirstName: user.FirstName,
 		LastName: user.LastName,
 		Email: user.Email,
+		Timezone: user.Timezone,
+		EmailVerified: user.EmailVerifiedAt.Valid,
 		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
@@ -40,13 +46,20 @@ type getUserRequest struct {
 	ID string `uri:"id" binding:"required,min=1"`
 }
 
+type updateUserRequest struct {
+	FirstName *string `json:"first_name" binding:"omitempty,min=1"`
+	LastName *string `json:"last_name" binding:"omitempty,min=1"`
+	//Timezone is an IANA zone, e.g. Europe/Berlin
+	Timezone *string `json:"timezone" binding:"omitempty,timezone"`
+}
+
 type loginUserRequest struct {
 	Email string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
 type loginUserResponse struct {
-	AccessToken string `json:"access_token"`
+	sessionResponse
 	User userResponse `json:"user"`
 }
 
@@ -86,6 +99,12 @@ func (server *Server) createUser(ctx * gin.Context) {
 		return
 	}
 
+	//The account works without a verified email unless the server is configured otherwise
+
+	if err := server.sendVerificationEmail(ctx, user); err != nil {
+		log.Println("cannot send verification email:", err)
+	}
+
 	responseData := newUserResponse(user)
 
 	ctx.JSON(http.StatusOK, responseData)
@@ -100,13 +119,21 @@ func (server *Server) getUser(ctx * gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return	
 	}
-	user, err := server.store.GetUserByID(ctx, uuid.MustParse(req.ID))
+	userID, err := uuid.Parse(req.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	//Users can only look up their own account
+
+	user, ok := server.authorizedUser(ctx)
+	if !ok {
+		return
+	}
+
+	if user.ID != userID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("account doesn't belong to the authenticated user")))
 		return
 	}
 	responseData := newUserResponse(user)
@@ -114,6 +141,63 @@ func (server *Server) getUser(ctx * gin.Context) {
 	ctx.JSON(http.StatusOK, responseData)
 }
 
+func (server *Server) updateUser(ctx *gin.Context) {
+	var uri getUserRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+	if !ok {
+		return
+	}
+
+	if user.ID != userID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	arg := db.UpdateUserParams {
+		ID: user.ID,
+	}
+
+	if req.FirstName != nil {
+		arg.FirstName = sql.NullString{String: *req.FirstName, Valid: true}
+	}
+
+	if req.LastName != nil {
+		arg.LastName = sql.NullString{String: *req.LastName, Valid: true}
+	}
+
+	if req.Timezone != nil {
+		arg.Timezone = sql.NullString{String: *req.Timezone, Valid: true}
+	}
+
+	user, err = server.store.UpdateUser(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 
@@ -140,16 +224,33 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(user.Email, server.config.AccessTokenDuration)
+	if server.mustVerifyToSignIn(user) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+		return
+	}
 
-	if err != nil {
+	credential, err := server.store.GetTOTPCredential(ctx, user.ID)
+
+	if err != nil && err != sql.ErrNoRows {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	//A secret that was never confirmed doesn't count yet
+
+	if err == nil && credential.ConfirmedAt.Valid {
+		server.startLoginChallenge(ctx, user)
+		return
+	}
+
+	session, ok := server.startSession(ctx, user)
+
+	if !ok {
+		return
+	}
 
 	responseData := loginUserResponse {
-		AccessToken: accessToken,
+		sessionResponse: session,
 		User: newUserResponse(user),
 	}
 
@@ -2,16 +2,20 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"io"
 	mockdb "m1thrandir225/your_time/db/mock"
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/token"
 	"m1thrandir225/your_time/util"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -23,15 +27,19 @@ func TestGetUserApi(t *testing.T) {
 	testCases := []struct {
 		name string
 		userID string
+		setupAuth func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		build func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	} {
 		{
 			name: "OK",
 			userID: user.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+			},
 			build: func (store *mockdb.MockStore) {
 				store.EXPECT().
-				GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+				GetUser(gomock.Any(), gomock.Eq(user.Email)).
 				Times(1).
 				Return(user, nil)
 			},
@@ -39,6 +47,36 @@ func TestGetUserApi(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "OtherUser",
+			userID: uuid.New().String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+			},
+			build: func (store *mockdb.MockStore) {
+				store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user.Email)).
+				Times(1).
+				Return(user, nil)
+			},
+			checkResponse: func (t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			userID: user.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			build: func (store *mockdb.MockStore) {
+				store.EXPECT().
+				GetUser(gomock.Any(), gomock.Any()).
+				Times(0)
+			},
+			checkResponse: func (t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i:= range testCases {
@@ -63,6 +101,8 @@ func TestGetUserApi(t *testing.T) {
 
 			require.NoError(t, err)
 
+			tc.setupAuth(t, request, sever.tokenMaker)
+
 			sever.router.ServeHTTP(recorder, request)
 
 			tc.checkResponse(t, recorder)
@@ -70,6 +110,188 @@ func TestGetUserApi(t *testing.T) {
 	}
 }
 
+func TestUpdateUserApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		userID string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	} {
+		{
+			name: "OK",
+			userID: user.ID.String(),
+			body: gin.H{"timezone": "America/New_York"},
+			build: func (store *mockdb.MockStore) {
+				arg := db.UpdateUserParams {
+					ID: user.ID,
+					Timezone: sql.NullString{String: "America/New_York", Valid: true},
+				}
+
+				updated := user
+				updated.Timezone = "America/New_York"
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+			},
+			checkResponse: func (t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, "America/New_York", res.Timezone)
+			},
+		},
+		{
+			name: "InvalidTimezone",
+			userID: user.ID.String(),
+			body: gin.H{"timezone": "Mars/Olympus_Mons"},
+			build: func (store *mockdb.MockStore) {
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func (t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUser",
+			userID: uuid.New().String(),
+			body: gin.H{"first_name": "Frodo"},
+			build: func (store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func (t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPatch, "/users/"+tc.userID, bytes.NewReader(data))
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLoginUserApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+
+	testCases := []struct {
+		name string
+		body gin.H
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"email": user.Email, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.TotpCredential{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						//A login starts a new session family
+						require.Equal(t, arg.ID, arg.FamilyID)
+						//The access token can be revoked with the session
+						require.True(t, arg.AccessTokenID.Valid)
+						return db.Session{ID: arg.ID, UserID: arg.UserID, FamilyID: arg.FamilyID, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotEmpty(t, res.AccessToken)
+				require.NotEmpty(t, res.RefreshToken)
+				require.True(t, res.RefreshTokenExpiresAt.After(res.AccessTokenExpiresAt))
+				require.Equal(t, user.ID, res.User.ID)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"email": user.Email, "password": "wrong-password"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"email": user.Email, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
 
 func randomUser() db.User {
 	return db.User{
@@ -78,6 +300,7 @@ func randomUser() db.User {
 		LastName: util.RandomString(6),
 		Email: util.RandomEmail(),
 		Password: util.RandomString(6),
+		Timezone: "UTC",
 		CreatedAt: util.RandomDate(),
 	}
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validTaskStatus validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if status, ok := fieldLevel.Field().Interface().(string); ok {
+		switch db.TaskStatus(status) {
+		case db.TaskStatusOpen, db.TaskStatusInProgress, db.TaskStatusDone, db.TaskStatusCancelled:
+			return true
+		}
+	}
+
+	return false
+}
+
+var validTaskPriority validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if priority, ok := fieldLevel.Field().Interface().(string); ok {
+		switch db.TaskPriority(priority) {
+		case db.TaskPriorityNone, db.TaskPriorityLow, db.TaskPriorityMedium, db.TaskPriorityHigh, db.TaskPriorityUrgent:
+			return true
+		}
+	}
+
+	return false
+}
+
+var validWebhookEvent validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if eventType, ok := fieldLevel.Field().Interface().(string); ok {
+		return webhook.IsSubscribable(eventType)
+	}
+
+	return false
+}
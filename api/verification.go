@@ -0,0 +1,262 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/token"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	//emailVerificationRestrict keeps users whose email isn't verified away
+	//from features that send their data elsewhere
+	emailVerificationRestrict = "restrict"
+	//emailVerificationLogin doesn't let them sign in at all
+	emailVerificationLogin = "login"
+
+	emailVerificationDuration = 24 * time.Hour
+	//verificationResendInterval is how long a user waits before another link is sent
+	verificationResendInterval = 5 * time.Minute
+)
+
+var (
+	errEmailNotVerified = errors.New("email address is not verified")
+	errEmailChanged = errors.New("email was changed after the verification link was sent")
+	errEmailUnchanged = errors.New("email is the same as the current one")
+)
+
+type verifyEmailRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type changeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	//Password is the current password, a stolen access token alone can't take over the account
+	Password string `json:"password" binding:"required"`
+}
+
+// resendVerificationResponse is the same whether or not a link was sent, so
+// the endpoint can't be used to find out who has an account.
+var resendVerificationResponse = gin.H{"message": "if the email belongs to an unverified account, a verification link was sent to it"}
+
+// verifyEmail marks the email of the user as verified with the token of a verification link.
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	claims, err := server.emailVerifier.VerifyToken(req.Token)
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.VerifyUserEmail(ctx, db.VerifyUserEmailParams {
+		ID: claims.UserID,
+		Email: claims.Email,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errEmailChanged))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// resendVerification sends another verification link. It doesn't need an
+// access token, since users may not be able to sign in until they verify.
+func (server *Server) resendVerification(ctx *gin.Context) {
+	var req resendVerificationRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Email)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusAccepted, resendVerificationResponse)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.sendVerificationEmail(ctx, user); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, resendVerificationResponse)
+}
+
+// changeEmail sets a new email that has to be verified again. Every session
+// is signed out, since tokens name the user by email, and the client gets a
+// new session instead, unless unverified users can't sign in.
+func (server *Server) changeEmail(ctx *gin.Context) {
+	var req changeEmailRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := util.ComparePassword(user.Password, req.Password); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if req.Email == user.Email {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errEmailUnchanged))
+		return
+	}
+
+	user, err := server.store.ChangeEmailTx(ctx, db.ChangeEmailTxParams {
+		UserID: user.ID,
+		Email: req.Email,
+	})
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	//The email is changed already, access tokens that can't be revoked now
+	//still expire soon and no longer match a user
+
+	if err := server.revocations.RevokeUser(ctx, user.ID); err != nil {
+		log.Println("cannot revoke access tokens:", err)
+	}
+
+	if err := server.revocations.Revoke(ctx, user.ID, payload); err != nil {
+		log.Println("cannot revoke access token:", err)
+	}
+
+	server.notifySecurity(ctx, user.ID, "Your email was changed", "Your account now uses " + user.Email + " and all of your devices were signed out. If this wasn't you, reset your password right away.", "email-change:" + payload.ID.String())
+
+	if err := server.sendVerificationEmail(ctx, user); err != nil {
+		log.Println("cannot send verification email:", err)
+	}
+
+	if server.mustVerifyToSignIn(user) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+		return
+	}
+
+	session, ok := server.startSession(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loginUserResponse {
+		sessionResponse: session,
+		User: newUserResponse(user),
+	})
+}
+
+// mustVerifyToSignIn reports whether user gets no new sessions until their
+// email is verified.
+func (server *Server) mustVerifyToSignIn(user db.User) bool {
+	return server.config.EmailVerification == emailVerificationLogin && !user.EmailVerifiedAt.Valid
+}
+
+// sendVerificationEmail sends a verification link to the email of user,
+// unless it is verified already or a link was sent moments ago.
+func (server *Server) sendVerificationEmail(ctx *gin.Context, user db.User) error {
+	claimed, err := server.store.ClaimVerificationEmail(ctx, db.ClaimVerificationEmailParams {
+		ID: user.ID,
+		SentBefore: time.Now().Add(-verificationResendInterval),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if claimed == 0 {
+		return nil
+	}
+
+	verificationToken, err := server.emailVerifier.CreateToken(user.ID, user.Email, emailVerificationDuration)
+
+	if err != nil {
+		return err
+	}
+
+	msg, err := notify.EmailVerificationMessage(user.Email, notify.EmailVerificationData {
+		FirstName: user.FirstName,
+		Link: server.appLink("/verify-email", url.Values{"token": {verificationToken}}),
+		ExpiresInHours: int(emailVerificationDuration.Hours()),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	server.sendMail(msg)
+
+	return nil
+}
+
+// requireVerifiedEmail turns away users whose email isn't verified, when
+// the server is configured to restrict them.
+func (server *Server) requireVerifiedEmail() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch server.config.EmailVerification {
+		case emailVerificationRestrict, emailVerificationLogin:
+		default:
+			ctx.Next()
+			return
+		}
+
+		user, ok := server.authorizedUser(ctx)
+
+		if !ok {
+			ctx.Abort()
+			return
+		}
+
+		if !user.EmailVerifiedAt.Valid {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,553 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserSendsVerificationApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomUser()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().
+		ClaimVerificationEmail(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ClaimVerificationEmailParams) (int64, error) {
+			require.Equal(t, user.ID, arg.ID)
+			return 1, nil
+		})
+
+	server := newTestServer(t, store)
+	server.config.AppURL = "https://app.example.com"
+
+	mailer := newFakeMailer()
+	server.mailer = mailer
+
+	data, err := json.Marshal(gin.H{
+		"first_name": user.FirstName,
+		"last_name": user.LastName,
+		"email": user.Email,
+		"password": util.RandomString(8),
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	request := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res userResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.False(t, res.EmailVerified)
+
+	msg := mailer.expectMail(t)
+	require.Equal(t, user.Email, msg.To)
+	require.Contains(t, msg.Text, "https://app.example.com/verify-email?token=")
+
+	claims, err := server.emailVerifier.VerifyToken(linkToken(t, msg.Text))
+	require.NoError(t, err)
+	require.Equal(t, user.ID, claims.UserID)
+	require.Equal(t, user.Email, claims.Email)
+}
+
+func TestVerifyEmailApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		token func(t *testing.T, server *Server) string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			token: func(t *testing.T, server *Server) string {
+				verificationToken, err := server.emailVerifier.CreateToken(user.ID, user.Email, time.Hour)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			build: func(store *mockdb.MockStore) {
+				verified := user
+				verified.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Eq(db.VerifyUserEmailParams{ID: user.ID, Email: user.Email})).
+					Times(1).
+					Return(verified, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, res.EmailVerified)
+			},
+		},
+		{
+			name: "EmailChanged",
+			token: func(t *testing.T, server *Server) string {
+				verificationToken, err := server.emailVerifier.CreateToken(user.ID, "old@example.com", time.Hour)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			token: func(t *testing.T, server *Server) string {
+				verificationToken, err := server.emailVerifier.CreateToken(user.ID, user.Email, -time.Minute)
+				require.NoError(t, err)
+				return verificationToken
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid",
+			token: func(t *testing.T, server *Server) string {
+				return "not-a-token"
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingToken",
+			token: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyUserEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodGet, "/users/verify?" + url.Values{"token": {tc.token(t, server)}}.Encode(), nil)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResendVerificationApi(t *testing.T) {
+	user := randomUser()
+
+	testCases := []struct {
+		name string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer)
+	}{
+		{
+			name: "OK",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ClaimVerificationEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ClaimVerificationEmailParams) (int64, error) {
+						require.WithinDuration(t, time.Now().Add(-verificationResendInterval), arg.SentBefore, time.Second)
+						return 1, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Equal(t, user.Email, mailer.expectMail(t).To)
+			},
+		},
+		{
+			//A link was sent moments ago or the email is verified already
+			name: "NotClaimed",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ClaimVerificationEmail(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.JSONEq(t, mustMarshal(t, resendVerificationResponse), recorder.Body.String())
+				mailer.expectNoMail(t)
+			},
+		},
+		{
+			name: "UnknownEmail",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().ClaimVerificationEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.JSONEq(t, mustMarshal(t, resendVerificationResponse), recorder.Body.String())
+				mailer.expectNoMail(t)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			mailer := newFakeMailer()
+			server.mailer = mailer
+
+			data, err := json.Marshal(gin.H{"email": user.Email})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/verify/resend", bytes.NewReader(data))
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder, mailer)
+		})
+	}
+}
+
+func TestChangeEmailApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+	user.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	newEmail := util.RandomEmail()
+
+	changed := user
+	changed.Email = newEmail
+	changed.EmailVerifiedAt = sql.NullTime{}
+
+	testCases := []struct {
+		name string
+		body gin.H
+		mode string
+		build func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"email": newEmail, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEmailTx(gomock.Any(), gomock.Eq(db.ChangeEmailTxParams{UserID: user.ID, Email: newEmail})).
+					Times(1).
+					Return(changed, nil)
+				store.EXPECT().RevokeUserAccessTokens(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.RevokeUserAccessTokensRow{}, nil)
+				store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				expectSecurityNotification(t, store, user)
+				store.EXPECT().ClaimVerificationEmail(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						return db.Session{ID: arg.ID, UserID: arg.UserID, FamilyID: arg.FamilyID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, newEmail, res.User.Email)
+				require.False(t, res.User.EmailVerified)
+				require.NotEmpty(t, res.AccessToken)
+
+				require.Equal(t, newEmail, mailer.expectMail(t).To)
+			},
+		},
+		{
+			//The change goes through, but signing in waits for the new email
+			name: "LoginMode",
+			body: gin.H{"email": newEmail, "password": password},
+			mode: emailVerificationLogin,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ChangeEmailTx(gomock.Any(), gomock.Any()).Times(1).Return(changed, nil)
+				store.EXPECT().RevokeUserAccessTokens(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.RevokeUserAccessTokensRow{}, nil)
+				store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				expectSecurityNotification(t, store, user)
+				store.EXPECT().ClaimVerificationEmail(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Equal(t, newEmail, mailer.expectMail(t).To)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"email": newEmail, "password": "wrong-password"},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ChangeEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Unchanged",
+			body: gin.H{"email": user.Email, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ChangeEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Taken",
+			body: gin.H{"email": newEmail, "password": password},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().ChangeEmailTx(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, &pq.Error{Code: "23505"})
+				store.EXPECT().RevokeUserAccessTokens(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *fakeMailer) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				mailer.expectNoMail(t)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store)
+
+			server := newTestServer(t, store)
+			server.config.EmailVerification = tc.mode
+
+			mailer := newFakeMailer()
+			server.mailer = mailer
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPut, "/users/email", bytes.NewReader(data))
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder, mailer)
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	unverified := randomUser()
+
+	verified := randomUser()
+	verified.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name string
+		mode string
+		user db.User
+		build func(store *mockdb.MockStore, user db.User)
+		code int
+	}{
+		{
+			name: "Off",
+			mode: "",
+			user: unverified,
+			build: func(store *mockdb.MockStore, user db.User) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "RestrictVerified",
+			mode: emailVerificationRestrict,
+			user: verified,
+			build: func(store *mockdb.MockStore, user db.User) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "RestrictUnverified",
+			mode: emailVerificationRestrict,
+			user: unverified,
+			build: func(store *mockdb.MockStore, user db.User) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "LoginUnverified",
+			mode: emailVerificationLogin,
+			user: unverified,
+			build: func(store *mockdb.MockStore, user db.User) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			},
+			code: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.build(store, tc.user)
+
+			server := newTestServer(t, store)
+			server.config.EmailVerification = tc.mode
+
+			path := "/verified"
+
+			server.router.GET(
+				path,
+				authMiddleware(server.tokenMaker, server.revocations),
+				server.requireVerifiedEmail(),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodGet, path, nil)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.code, recorder.Code, fmt.Sprintf("mode %q", tc.mode))
+		})
+	}
+}
+
+func TestLoginUnverifiedEmailApi(t *testing.T) {
+	password := util.RandomString(8)
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := randomUser()
+	user.Password = hashedPassword
+
+	for _, mode := range []string{emailVerificationRestrict, emailVerificationLogin} {
+		t.Run(mode, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+
+			//Only login mode turns the user away
+			sessions := 1
+			if mode == emailVerificationLogin {
+				sessions = 0
+			}
+
+			store.EXPECT().
+				GetTOTPCredential(gomock.Any(), gomock.Eq(user.ID)).
+				Times(sessions).
+				Return(db.TotpCredential{}, sql.ErrNoRows)
+
+			store.EXPECT().
+				CreateSession(gomock.Any(), gomock.Any()).
+				Times(sessions).
+				DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+					return db.Session{ID: arg.ID, UserID: arg.UserID, FamilyID: arg.FamilyID}, nil
+				})
+
+			server := newTestServer(t, store)
+			server.config.EmailVerification = mode
+
+			data, err := json.Marshal(gin.H{"email": user.Email, "password": password})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			request := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+
+			server.router.ServeHTTP(recorder, request)
+
+			if mode == emailVerificationLogin {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			} else {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			}
+		})
+	}
+}
+
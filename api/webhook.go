@@ -0,0 +1,484 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type webhookResponse struct {
+	ID string `json:"id"`
+	URL string `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active bool `json:"active"`
+	FailureCount int32 `json:"failure_count"`
+	DisabledAt *string `json:"disabled_at"`
+	CreatedAt string `json:"created_at"`
+	//Secret is only returned when the endpoint is created
+	Secret string `json:"secret,omitempty"`
+}
+
+type createWebhookRequest struct {
+	URL string `json:"url" binding:"required,url,max=2000"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,webhook_event"`
+}
+
+type updateWebhookRequest struct {
+	URL *string `json:"url" binding:"omitempty,url,max=2000"`
+	EventTypes *[]string `json:"event_types" binding:"omitempty,min=1,dive,webhook_event"`
+	Active *bool `json:"active"`
+}
+
+type webhookURIRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type listWebhookDeliveriesRequest struct {
+	Cursor string `form:"cursor"`
+	Limit int32 `form:"limit" binding:"omitempty,min=1"`
+}
+
+type webhookDeliveryResponse struct {
+	ID string `json:"id"`
+	EventID string `json:"event_id"`
+	EventType string `json:"event_type"`
+	Payload json.RawMessage `json:"payload"`
+	Status string `json:"status"`
+	Attempts int32 `json:"attempts"`
+	ResponseStatus *int32 `json:"response_status"`
+	LastError *string `json:"last_error"`
+	NextAttemptAt *string `json:"next_attempt_at"`
+	DeliveredAt *string `json:"delivered_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type listWebhookDeliveriesResponse struct {
+	Deliveries []webhookDeliveryResponse `json:"deliveries"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+var (
+	errWebhookNotOwned = errors.New("webhook doesn't belong to the authenticated user")
+	errInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+	errWebhookDisabled = errors.New("webhook is disabled, enable it before sending a test event")
+)
+
+func newWebhookResponse(endpoint db.WebhookEndpoint) webhookResponse {
+	res := webhookResponse {
+		ID: endpoint.ID.String(),
+		URL: endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		Active: endpoint.Active,
+		FailureCount: endpoint.FailureCount,
+		CreatedAt: endpoint.CreatedAt.Format(time.RFC3339),
+	}
+
+	if endpoint.DisabledAt.Valid {
+		disabledAt := endpoint.DisabledAt.Time.Format(time.RFC3339)
+		res.DisabledAt = &disabledAt
+	}
+
+	return res
+}
+
+func newWebhookDeliveryResponse(delivery db.WebhookDelivery) webhookDeliveryResponse {
+	res := webhookDeliveryResponse {
+		ID: delivery.ID.String(),
+		EventID: delivery.EventID.String(),
+		EventType: delivery.EventType,
+		Payload: delivery.Payload,
+		Status: string(delivery.Status),
+		Attempts: delivery.Attempts,
+		CreatedAt: delivery.CreatedAt.Format(time.RFC3339),
+	}
+
+	if delivery.ResponseStatus.Valid {
+		res.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+
+	if delivery.LastError.Valid {
+		res.LastError = &delivery.LastError.String
+	}
+
+	if delivery.Status == db.DeliveryStatusPending {
+		nextAttemptAt := delivery.NextAttemptAt.Format(time.RFC3339)
+		res.NextAttemptAt = &nextAttemptAt
+	}
+
+	if delivery.DeliveredAt.Valid {
+		deliveredAt := delivery.DeliveredAt.Time.Format(time.RFC3339)
+		res.DeliveredAt = &deliveredAt
+	}
+
+	return res
+}
+
+// isValidWebhookURL turns away URLs that obviously point inside our network.
+// Hostnames are checked by the webhook client once they are resolved.
+func isValidWebhookURL(value string) bool {
+	parsed, err := url.Parse(value)
+
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return false
+	}
+
+	if strings.EqualFold(parsed.Hostname(), "localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && !webhook.IsPublicIP(ip) {
+		return false
+	}
+
+	return true
+}
+
+// publishTaskEvent queues a webhook event about task and sends it to the live
+// streams of its user. The change to the task has already been saved by then,
+// so a failure is only logged.
+func (server *Server) publishTaskEvent(ctx *gin.Context, eventType webhook.EventType, task db.Task) {
+	event := webhook.NewTaskEvent(eventType, task)
+
+	if err := webhook.Publish(ctx, server.store, task.UserID, event); err != nil {
+		log.Println("cannot publish webhook event:", err)
+	}
+
+	if err := server.events.Publish(task.UserID, event); err != nil {
+		log.Println("cannot publish stream event:", err)
+	}
+}
+
+// publishTaskTreeEvents publishes eventType for task and for each of the
+// subtasks that changed along with it.
+func (server *Server) publishTaskTreeEvents(ctx *gin.Context, eventType webhook.EventType, task db.Task, descendants []db.Task) {
+	server.publishTaskEvent(ctx, eventType, task)
+
+	for _, descendant := range descendants {
+		server.publishTaskEvent(ctx, eventType, descendant)
+	}
+}
+
+// getOwnedWebhook loads the endpoint in the URI and makes sure it belongs to user.
+// It writes the error response itself, so callers only need to return when ok is false.
+func (server *Server) getOwnedWebhook(ctx *gin.Context, user db.User) (endpoint db.WebhookEndpoint, ok bool) {
+	var uri webhookURIRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, uuid.MustParse(uri.ID))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if endpoint.UserID != user.ID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errWebhookNotOwned))
+		return
+	}
+
+	return endpoint, true
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !isValidWebhookURL(req.URL) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidWebhookURL))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		UserID: user.ID,
+		Url: req.URL,
+		Secret: secret,
+		EventTypes: uniqueStrings(req.EventTypes),
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := newWebhookResponse(endpoint)
+	res.Secret = endpoint.Secret
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, user.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := []webhookResponse{}
+
+	for _, endpoint := range endpoints {
+		res = append(res, newWebhookResponse(endpoint))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (server *Server) getWebhook(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhook(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(endpoint))
+}
+
+// updateWebhook changes an endpoint. Setting active to true enables a
+// disabled endpoint again and resets its failure count.
+func (server *Server) updateWebhook(ctx *gin.Context) {
+	var req updateWebhookRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.URL != nil && !isValidWebhookURL(*req.URL) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidWebhookURL))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhook(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateWebhookEndpointParams {
+		ID: endpoint.ID,
+	}
+
+	if req.URL != nil {
+		arg.Url = sql.NullString{String: *req.URL, Valid: true}
+	}
+
+	if req.EventTypes != nil {
+		arg.SetEventTypes = true
+		arg.EventTypes = uniqueStrings(*req.EventTypes)
+	}
+
+	if req.Active != nil {
+		arg.Active = sql.NullBool{Bool: *req.Active, Valid: true}
+	}
+
+	endpoint, err := server.store.UpdateWebhookEndpoint(ctx, arg)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(endpoint))
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhook(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteWebhookEndpoint(ctx, endpoint.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// listWebhookDeliveries returns the delivery log of an endpoint, newest first.
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var req listWebhookDeliveriesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhook(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	pageSize := pageLimit(req.Limit)
+
+	//Fetch one extra row to find out whether there is a next page
+
+	arg := db.ListWebhookDeliveriesParams {
+		EndpointID: endpoint.ID,
+		PageSize: pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeTimeCursor(req.Cursor)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.BeforeCreatedAt = sql.NullTime{Time: cursor.At, Valid: true}
+		arg.BeforeID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := listWebhookDeliveriesResponse {
+		Deliveries: []webhookDeliveryResponse{},
+	}
+
+	if len(deliveries) > int(pageSize) {
+		deliveries = deliveries[:pageSize]
+		last := deliveries[len(deliveries)-1]
+		nextCursor := encodeTimeCursor(last.CreatedAt, last.ID)
+		res.NextCursor = &nextCursor
+	}
+
+	for _, delivery := range deliveries {
+		res.Deliveries = append(res.Deliveries, newWebhookDeliveryResponse(delivery))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// sendTestWebhook queues a webhook.test event for the endpoint, whatever it
+// is subscribed to. The outcome shows up in the delivery log.
+func (server *Server) sendTestWebhook(ctx *gin.Context) {
+	user, ok := server.authorizedUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	endpoint, ok := server.getOwnedWebhook(ctx, user)
+
+	if !ok {
+		return
+	}
+
+	if !endpoint.Active {
+		ctx.JSON(http.StatusConflict, errorResponse(errWebhookDisabled))
+		return
+	}
+
+	event := webhook.NewTestEvent(endpoint)
+
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	delivery, err := server.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+		EndpointID: endpoint.ID,
+		EventID: event.ID,
+		EventType: string(event.Type),
+		Payload: payload,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newWebhookDeliveryResponse(delivery))
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := []string{}
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
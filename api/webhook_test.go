@@ -0,0 +1,535 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// expectWebhookEvent expects a handler to publish one webhook event of eventType.
+func expectWebhookEvent(t *testing.T, store *mockdb.MockStore, eventType webhook.EventType) {
+	store.EXPECT().
+		EnqueueWebhookEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.EnqueueWebhookEventParams) (int64, error) {
+			require.Equal(t, string(eventType), arg.EventType)
+
+			var event webhook.Event
+			require.NoError(t, json.Unmarshal(arg.Payload, &event))
+			require.Equal(t, arg.EventID, event.ID)
+			require.Equal(t, eventType, event.Type)
+
+			return 0, nil
+		})
+}
+
+func TestCreateWebhookApi(t *testing.T) {
+	user := randomUser()
+
+	endpoint := randomWebhookEndpoint(user)
+
+	testCases := []struct {
+		name 	string
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H {
+				"url": endpoint.Url,
+				"event_types": []string{"task.created", "task.completed", "task.created"},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, endpoint.Url, arg.Url)
+						require.Equal(t, []string{"task.created", "task.completed"}, arg.EventTypes)
+						require.True(t, strings.HasPrefix(arg.Secret, "whsec_"))
+
+						created := endpoint
+						created.Secret = arg.Secret
+						created.EventTypes = arg.EventTypes
+						return created, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res webhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, endpoint.ID.String(), res.ID)
+				require.True(t, strings.HasPrefix(res.Secret, "whsec_"))
+			},
+		},
+		{
+			name: "UnknownEventType",
+			body: gin.H {
+				"url": endpoint.Url,
+				"event_types": []string{"task.archived"},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TestEventNotSubscribable",
+			body: gin.H {
+				"url": endpoint.Url,
+				"event_types": []string{"webhook.test"},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoEventTypes",
+			body: gin.H {
+				"url": endpoint.Url,
+				"event_types": []string{},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotHTTP",
+			body: gin.H {
+				"url": "ftp://example.com/hook",
+				"event_types": []string{"task.created"},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PrivateAddress",
+			body: gin.H {
+				"url": "http://169.254.169.254/latest/meta-data",
+				"event_types": []string{"task.created"},
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateWebhookApi(t *testing.T) {
+	user := randomUser()
+
+	endpoint := randomWebhookEndpoint(user)
+	endpoint.Active = false
+	endpoint.FailureCount = 5
+	endpoint.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	other := randomWebhookEndpoint(randomUser())
+
+	testCases := []struct {
+		name 	string
+		endpointID 	uuid.UUID
+		body 	gin.H
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Enable",
+			endpointID: endpoint.ID,
+			body: gin.H {
+				"active": true,
+				"event_types": []string{"reminder.due"},
+			},
+			build: func(store *mockdb.MockStore) {
+				arg := db.UpdateWebhookEndpointParams {
+					ID: endpoint.ID,
+					SetEventTypes: true,
+					EventTypes: []string{"reminder.due"},
+					Active: sql.NullBool{Bool: true, Valid: true},
+				}
+
+				enabled := endpoint
+				enabled.Active = true
+				enabled.FailureCount = 0
+				enabled.DisabledAt = sql.NullTime{}
+				enabled.EventTypes = arg.EventTypes
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().UpdateWebhookEndpoint(gomock.Any(), gomock.Eq(arg)).Times(1).Return(enabled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res webhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, res.Active)
+				require.Nil(t, res.DisabledAt)
+				require.Empty(t, res.Secret)
+			},
+		},
+		{
+			name: "NotOwned",
+			endpointID: other.ID,
+			body: gin.H {
+				"active": true,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().UpdateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			endpointID: endpoint.ID,
+			body: gin.H {
+				"active": true,
+			},
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(db.WebhookEndpoint{}, sql.ErrNoRows)
+				store.EXPECT().UpdateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/webhooks/%s", tc.endpointID)
+
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListWebhookDeliveriesApi(t *testing.T) {
+	user := randomUser()
+
+	endpoint := randomWebhookEndpoint(user)
+
+	now := time.Now().Truncate(time.Second)
+
+	deliveries := []db.WebhookDelivery{
+		randomWebhookDelivery(endpoint, now),
+		randomWebhookDelivery(endpoint, now.Add(-time.Minute)),
+		randomWebhookDelivery(endpoint, now.Add(-2*time.Minute)),
+	}
+
+	cursor := encodeTimeCursor(deliveries[1].CreatedAt, deliveries[1].ID)
+
+	testCases := []struct {
+		name 	string
+		query 	string
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstPage",
+			query: "?limit=2",
+			build: func(store *mockdb.MockStore) {
+				arg := db.ListWebhookDeliveriesParams {
+					EndpointID: endpoint.ID,
+					PageSize: 3,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).Times(1).Return(deliveries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listWebhookDeliveriesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Deliveries, 2)
+				require.Equal(t, deliveries[0].ID.String(), res.Deliveries[0].ID)
+				require.NotNil(t, res.NextCursor)
+				require.Equal(t, cursor, *res.NextCursor)
+			},
+		},
+		{
+			name: "NextPage",
+			query: "?limit=2&cursor=" + cursor,
+			build: func(store *mockdb.MockStore) {
+				arg := db.ListWebhookDeliveriesParams {
+					EndpointID: endpoint.ID,
+					BeforeCreatedAt: sql.NullTime{Time: deliveries[1].CreatedAt, Valid: true},
+					BeforeID: uuid.NullUUID{UUID: deliveries[1].ID, Valid: true},
+					PageSize: 3,
+				}
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+						require.True(t, arg.BeforeCreatedAt.Time.Equal(got.BeforeCreatedAt.Time))
+						require.Equal(t, arg.BeforeID, got.BeforeID)
+						require.Equal(t, arg.PageSize, got.PageSize)
+						return deliveries[2:], nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res listWebhookDeliveriesResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Deliveries, 1)
+				require.Nil(t, res.NextCursor)
+			},
+		},
+		{
+			name: "InvalidCursor",
+			query: "?cursor=nope",
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%s/deliveries%s", endpoint.ID, tc.query)
+
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSendTestWebhookApi(t *testing.T) {
+	user := randomUser()
+
+	endpoint := randomWebhookEndpoint(user)
+
+	disabled := endpoint
+	disabled.Active = false
+
+	testCases := []struct {
+		name 	string
+		endpoint 	db.WebhookEndpoint
+		build 	func(store *mockdb.MockStore)
+		checkResponse 	func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			endpoint: endpoint,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+						require.Equal(t, endpoint.ID, arg.EndpointID)
+						require.Equal(t, "webhook.test", arg.EventType)
+
+						delivery := randomWebhookDelivery(endpoint, time.Now())
+						delivery.EventID = arg.EventID
+						delivery.EventType = arg.EventType
+						delivery.Payload = arg.Payload
+						return delivery, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res webhookDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, "webhook.test", res.EventType)
+				require.Equal(t, "pending", res.Status)
+				require.Contains(t, string(res.Payload), endpoint.ID.String())
+			},
+		},
+		{
+			name: "Disabled",
+			endpoint: disabled,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(tc.endpoint.ID)).Times(1).Return(tc.endpoint, nil)
+
+			tc.build(store)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%s/test", tc.endpoint.ID)
+
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Email, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomWebhookEndpoint(user db.User) db.WebhookEndpoint {
+	return db.WebhookEndpoint {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Url: "https://example.com/hooks/" + util.RandomString(8),
+		Secret: "whsec_" + util.RandomString(32),
+		EventTypes: []string{"task.created"},
+		Active: true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func randomWebhookDelivery(endpoint db.WebhookEndpoint, createdAt time.Time) db.WebhookDelivery {
+	return db.WebhookDelivery {
+		ID: uuid.New(),
+		EndpointID: endpoint.ID,
+		EventID: uuid.New(),
+		EventType: "task.created",
+		Payload: json.RawMessage(`{}`),
+		Status: db.DeliveryStatusPending,
+		NextAttemptAt: createdAt,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE "tasks" ADD COLUMN "deleted_at" TIMESTAMPTZ;
+
+CREATE INDEX ON "tasks" ("user_id", "deleted_at");
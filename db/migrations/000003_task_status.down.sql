@@ -0,0 +1,5 @@
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "completed_at";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS "task_status";
@@ -0,0 +1,12 @@
+CREATE TYPE "task_status" AS ENUM (
+  'open',
+  'in_progress',
+  'done',
+  'cancelled'
+);
+
+ALTER TABLE "tasks" ADD COLUMN "status" task_status NOT NULL DEFAULT 'open';
+
+ALTER TABLE "tasks" ADD COLUMN "completed_at" TIMESTAMPTZ;
+
+CREATE INDEX ON "tasks" ("user_id", "status");
@@ -0,0 +1,5 @@
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "important";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "priority";
+
+DROP TYPE IF EXISTS "task_priority";
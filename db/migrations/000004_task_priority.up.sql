@@ -0,0 +1,11 @@
+CREATE TYPE "task_priority" AS ENUM (
+  'none',
+  'low',
+  'medium',
+  'high',
+  'urgent'
+);
+
+ALTER TABLE "tasks" ADD COLUMN "priority" task_priority NOT NULL DEFAULT 'none';
+
+ALTER TABLE "tasks" ADD COLUMN "important" BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,24 @@
+CREATE TABLE "tags" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "name" TEXT NOT NULL,
+  "color" TEXT NOT NULL DEFAULT '#808080',
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "task_tags" (
+  "task_id" UUID NOT NULL,
+  "tag_id" UUID NOT NULL,
+  PRIMARY KEY ("task_id", "tag_id")
+);
+
+CREATE UNIQUE INDEX ON "tags" ("user_id", "name");
+
+CREATE INDEX ON "task_tags" ("tag_id");
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "task_tags" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "task_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags" ("id") ON DELETE CASCADE;
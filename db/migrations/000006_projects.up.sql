@@ -0,0 +1,21 @@
+CREATE TABLE "projects" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "name" TEXT NOT NULL,
+  "color" TEXT NOT NULL DEFAULT '#808080',
+  "icon" TEXT,
+  "archived" BOOLEAN NOT NULL DEFAULT false,
+  "sort_order" INTEGER NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "projects" ("user_id", "sort_order");
+
+ALTER TABLE "projects" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "tasks" ADD COLUMN "project_id" UUID;
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "tasks" ("project_id");
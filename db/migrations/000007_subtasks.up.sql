@@ -0,0 +1,5 @@
+ALTER TABLE "tasks" ADD COLUMN "parent_id" UUID;
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("parent_id") REFERENCES "tasks" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "tasks" ("parent_id");
@@ -0,0 +1,7 @@
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "recurrence_exdates";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "recurrence_timezone";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "recurrence_start";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "recurrence_rule";
@@ -0,0 +1,9 @@
+ALTER TABLE "tasks" ADD COLUMN "recurrence_rule" TEXT;
+
+ALTER TABLE "tasks" ADD COLUMN "recurrence_start" TIMESTAMPTZ;
+
+ALTER TABLE "tasks" ADD COLUMN "recurrence_timezone" TEXT;
+
+ALTER TABLE "tasks" ADD COLUMN "recurrence_exdates" TEXT;
+
+COMMENT ON COLUMN "tasks"."recurrence_exdates" IS 'RFC 5545 EXDATE value list in UTC';
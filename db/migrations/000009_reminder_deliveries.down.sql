@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "reminder_deliveries";
+
+DROP INDEX IF EXISTS "tasks_reminder_date_idx";
+
+DROP TYPE IF EXISTS "delivery_status";
@@ -0,0 +1,27 @@
+CREATE TYPE "delivery_status" AS ENUM (
+  'pending',
+  'sent',
+  'failed',
+  'cancelled'
+);
+
+CREATE TABLE "reminder_deliveries" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "task_id" UUID NOT NULL,
+  "remind_at" TIMESTAMPTZ NOT NULL,
+  "status" delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" INT NOT NULL DEFAULT 0,
+  "next_attempt_at" TIMESTAMPTZ NOT NULL,
+  "last_error" TEXT,
+  "delivered_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON "reminder_deliveries" ("task_id", "remind_at");
+
+CREATE INDEX ON "reminder_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX ON "tasks" ("reminder_date") WHERE "deleted_at" IS NULL;
+
+ALTER TABLE "reminder_deliveries" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
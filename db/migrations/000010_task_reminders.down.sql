@@ -0,0 +1,24 @@
+ALTER TABLE "tasks" ADD COLUMN "reminder_date" TIMESTAMPTZ;
+
+UPDATE "tasks" SET "reminder_date" = "first"."remind_at"
+FROM (
+  SELECT "task_id", MIN("remind_at") AS "remind_at" FROM "task_reminders" GROUP BY "task_id"
+) AS "first"
+WHERE "first"."task_id" = "tasks"."id";
+
+CREATE INDEX ON "tasks" ("reminder_date") WHERE "deleted_at" IS NULL;
+
+DELETE FROM "reminder_deliveries" a USING "reminder_deliveries" b
+WHERE a."task_id" = b."task_id" AND a."remind_at" = b."remind_at" AND a."id" > b."id";
+
+DROP INDEX IF EXISTS "reminder_deliveries_task_id_idx";
+
+DROP INDEX IF EXISTS "reminder_deliveries_reminder_id_remind_at_idx";
+
+ALTER TABLE "reminder_deliveries" DROP COLUMN IF EXISTS "reminder_id";
+
+CREATE UNIQUE INDEX ON "reminder_deliveries" ("task_id", "remind_at");
+
+DROP TABLE IF EXISTS "default_reminders";
+
+DROP TABLE IF EXISTS "task_reminders";
@@ -0,0 +1,54 @@
+CREATE TABLE "task_reminders" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "task_id" UUID NOT NULL,
+  "offset_seconds" INT,
+  "remind_at" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "task_reminders"."offset_seconds" IS 'seconds before the due date for relative reminders, NULL for absolute ones';
+
+CREATE INDEX ON "task_reminders" ("task_id");
+
+CREATE INDEX ON "task_reminders" ("remind_at");
+
+ALTER TABLE "task_reminders" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "default_reminders" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "offset_seconds" INT NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON "default_reminders" ("user_id", "offset_seconds");
+
+ALTER TABLE "default_reminders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Reminders on the due date were created by default, so they follow the due date from now on
+
+INSERT INTO "task_reminders" ("task_id", "offset_seconds", "remind_at")
+SELECT "id", CASE WHEN "reminder_date" = "due_date" THEN 0 END, "reminder_date" FROM "tasks"
+WHERE "reminder_date" IS NOT NULL;
+
+ALTER TABLE "reminder_deliveries" ADD COLUMN "reminder_id" UUID;
+
+UPDATE "reminder_deliveries" SET "reminder_id" = "task_reminders"."id"
+FROM "task_reminders"
+WHERE "task_reminders"."task_id" = "reminder_deliveries"."task_id" AND "task_reminders"."remind_at" = "reminder_deliveries"."remind_at";
+
+DELETE FROM "reminder_deliveries" WHERE "reminder_id" IS NULL;
+
+ALTER TABLE "reminder_deliveries" ALTER COLUMN "reminder_id" SET NOT NULL;
+
+DROP INDEX IF EXISTS "reminder_deliveries_task_id_remind_at_idx";
+
+CREATE UNIQUE INDEX ON "reminder_deliveries" ("reminder_id", "remind_at");
+
+CREATE INDEX ON "reminder_deliveries" ("task_id");
+
+ALTER TABLE "reminder_deliveries" ADD FOREIGN KEY ("reminder_id") REFERENCES "task_reminders" ("id") ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS "tasks_reminder_date_idx";
+
+ALTER TABLE "tasks" DROP COLUMN "reminder_date";
@@ -0,0 +1,40 @@
+CREATE TABLE "webhook_endpoints" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "url" TEXT NOT NULL,
+  "secret" TEXT NOT NULL,
+  "event_types" TEXT[] NOT NULL,
+  "active" BOOLEAN NOT NULL DEFAULT true,
+  "failure_count" INT NOT NULL DEFAULT 0,
+  "disabled_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "webhook_endpoints" ("user_id");
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "webhook_deliveries" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "endpoint_id" UUID NOT NULL,
+  "event_id" UUID NOT NULL,
+  "event_type" TEXT NOT NULL,
+  "payload" JSONB NOT NULL,
+  "status" delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" INT NOT NULL DEFAULT 0,
+  "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "response_status" INT,
+  "last_error" TEXT,
+  "delivered_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("endpoint_id", "event_id");
+
+CREATE INDEX ON "webhook_deliveries" ("endpoint_id", "created_at");
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id") ON DELETE CASCADE;
@@ -0,0 +1,26 @@
+CREATE TYPE "notification_type" AS ENUM (
+  'reminder',
+  'security'
+);
+
+CREATE TABLE "notifications" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "type" notification_type NOT NULL,
+  "title" TEXT NOT NULL,
+  "body" TEXT NOT NULL DEFAULT '',
+  "task_id" UUID,
+  "dedupe_key" TEXT,
+  "read_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "notifications" ("user_id", "created_at");
+
+CREATE INDEX ON "notifications" ("user_id") WHERE "read_at" IS NULL;
+
+CREATE UNIQUE INDEX ON "notifications" ("user_id", "dedupe_key");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE SET NULL;
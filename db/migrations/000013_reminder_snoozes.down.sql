@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "reminder_snoozes";
+
+ALTER TABLE "task_reminders" DROP COLUMN IF EXISTS "dismissed_at";
+
+ALTER TABLE "task_reminders" DROP COLUMN IF EXISTS "snooze_count";
+
+ALTER TABLE "task_reminders" DROP COLUMN IF EXISTS "original_remind_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "timezone";
@@ -0,0 +1,29 @@
+ALTER TABLE "users" ADD COLUMN "timezone" TEXT NOT NULL DEFAULT 'UTC';
+
+COMMENT ON COLUMN "users"."timezone" IS 'IANA zone used for times like "tomorrow morning"';
+
+ALTER TABLE "task_reminders" ADD COLUMN "original_remind_at" TIMESTAMPTZ;
+
+ALTER TABLE "task_reminders" ADD COLUMN "snooze_count" INT NOT NULL DEFAULT 0;
+
+ALTER TABLE "task_reminders" ADD COLUMN "dismissed_at" TIMESTAMPTZ;
+
+COMMENT ON COLUMN "task_reminders"."original_remind_at" IS 'when the reminder was due before it was first snoozed';
+
+CREATE TABLE "reminder_snoozes" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "task_id" UUID NOT NULL,
+  "reminder_id" UUID,
+  "preset" TEXT NOT NULL,
+  "snoozed_from" TIMESTAMPTZ NOT NULL,
+  "snoozed_until" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "reminder_snoozes" ("task_id", "created_at");
+
+ALTER TABLE "reminder_snoozes" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+-- Replacing the reminders of a task keeps their history
+
+ALTER TABLE "reminder_snoozes" ADD FOREIGN KEY ("reminder_id") REFERENCES "task_reminders" ("id") ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "break_through_quiet_hours";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "dnd_until";
+
+DROP TABLE IF EXISTS "quiet_hours";
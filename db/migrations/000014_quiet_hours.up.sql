@@ -0,0 +1,21 @@
+CREATE TABLE "quiet_hours" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "weekday" INT NOT NULL CHECK ("weekday" BETWEEN 0 AND 6),
+  "start_minute" INT NOT NULL CHECK ("start_minute" BETWEEN 0 AND 1439),
+  "end_minute" INT NOT NULL CHECK ("end_minute" BETWEEN 0 AND 1439),
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK ("start_minute" <> "end_minute")
+);
+
+COMMENT ON COLUMN "quiet_hours"."weekday" IS 'day the window starts on, 0 is Sunday';
+
+COMMENT ON COLUMN "quiet_hours"."end_minute" IS 'minutes after local midnight, a window ending before it starts runs into the next day';
+
+CREATE INDEX ON "quiet_hours" ("user_id");
+
+ALTER TABLE "quiet_hours" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "users" ADD COLUMN "dnd_until" TIMESTAMPTZ;
+
+ALTER TABLE "tasks" ADD COLUMN "break_through_quiet_hours" BOOLEAN NOT NULL DEFAULT false;
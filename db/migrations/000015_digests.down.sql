@@ -0,0 +1,18 @@
+DELETE FROM "notifications" WHERE "type" = 'digest';
+
+-- Postgres can't drop a value from an enum, so the type is swapped for one without it
+
+ALTER TYPE "notification_type" RENAME TO "notification_type_old";
+
+CREATE TYPE "notification_type" AS ENUM (
+  'reminder',
+  'security'
+);
+
+ALTER TABLE "notifications" ALTER COLUMN "type" TYPE "notification_type" USING "type"::text::"notification_type";
+
+DROP TYPE "notification_type_old";
+
+DROP TABLE IF EXISTS "digest_settings";
+
+DROP TYPE IF EXISTS "digest_frequency";
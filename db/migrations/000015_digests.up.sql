@@ -0,0 +1,25 @@
+CREATE TYPE "digest_frequency" AS ENUM (
+  'off',
+  'daily',
+  'weekly'
+);
+
+CREATE TABLE "digest_settings" (
+  "user_id" UUID PRIMARY KEY,
+  "frequency" digest_frequency NOT NULL DEFAULT 'off',
+  "hour" INT NOT NULL DEFAULT 8 CHECK ("hour" BETWEEN 0 AND 23),
+  "weekday" INT NOT NULL DEFAULT 1 CHECK ("weekday" BETWEEN 0 AND 6),
+  "last_sent_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "digest_settings"."hour" IS 'local hour in the timezone of the user the digest is sent at';
+
+COMMENT ON COLUMN "digest_settings"."weekday" IS 'day weekly digests are sent on, 0 is Sunday';
+
+CREATE INDEX ON "digest_settings" ("frequency") WHERE "frequency" <> 'off';
+
+ALTER TABLE "digest_settings" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TYPE "notification_type" ADD VALUE IF NOT EXISTS 'digest';
@@ -0,0 +1,23 @@
+CREATE TABLE "sessions" (
+  "id" UUID PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "family_id" UUID NOT NULL,
+  "user_agent" TEXT NOT NULL,
+  "client_ip" TEXT NOT NULL,
+  "is_blocked" BOOLEAN NOT NULL DEFAULT false,
+  "rotated_at" TIMESTAMPTZ,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "sessions"."id" IS 'ID of the payload of the refresh token';
+
+COMMENT ON COLUMN "sessions"."family_id" IS 'shared by every session rotated from the same login';
+
+COMMENT ON COLUMN "sessions"."rotated_at" IS 'when the refresh token was exchanged for a new one, using it again blocks the family';
+
+CREATE INDEX ON "sessions" ("user_id");
+
+CREATE INDEX ON "sessions" ("family_id");
+
+ALTER TABLE "sessions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,24 @@
+ALTER TABLE "sessions" ADD COLUMN "access_token_id" UUID;
+
+ALTER TABLE "sessions" ADD COLUMN "access_token_expires_at" TIMESTAMPTZ;
+
+COMMENT ON COLUMN "sessions"."access_token_id" IS 'ID of the payload of the access token issued with the refresh token';
+
+CREATE UNIQUE INDEX ON "sessions" ("access_token_id");
+
+CREATE TABLE "revoked_tokens" (
+  "id" UUID PRIMARY KEY,
+  "user_id" UUID NOT NULL,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "revoked_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "revoked_tokens"."id" IS 'ID of the payload of the revoked token';
+
+COMMENT ON COLUMN "revoked_tokens"."expires_at" IS 'when the token expires anyway, after that the row can be deleted';
+
+CREATE INDEX ON "revoked_tokens" ("expires_at");
+
+CREATE INDEX ON "revoked_tokens" ("revoked_at");
+
+ALTER TABLE "revoked_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,16 @@
+CREATE TABLE "password_resets" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "token_hash" TEXT UNIQUE NOT NULL,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "used_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "password_resets"."token_hash" IS 'SHA-256 of the token sent to the user, the token itself is never stored';
+
+COMMENT ON COLUMN "password_resets"."used_at" IS 'when the token was redeemed or superseded, it works only once';
+
+CREATE INDEX ON "password_resets" ("user_id", "created_at");
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "verification_sent_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_verified_at";
@@ -0,0 +1,7 @@
+ALTER TABLE "users" ADD COLUMN "email_verified_at" TIMESTAMPTZ;
+
+ALTER TABLE "users" ADD COLUMN "verification_sent_at" TIMESTAMPTZ;
+
+COMMENT ON COLUMN "users"."email_verified_at" IS 'when the user opened the verification link sent to their current email';
+
+COMMENT ON COLUMN "users"."verification_sent_at" IS 'when the last verification link was sent, resending is throttled';
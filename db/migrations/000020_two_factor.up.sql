@@ -0,0 +1,44 @@
+CREATE TABLE "totp_credentials" (
+  "user_id" UUID PRIMARY KEY,
+  "secret" TEXT NOT NULL,
+  "confirmed_at" TIMESTAMPTZ,
+  "last_used_step" BIGINT NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "totp_credentials"."confirmed_at" IS 'when the user entered a first code, two-factor authentication is only on after that';
+
+COMMENT ON COLUMN "totp_credentials"."last_used_step" IS 'period of the last accepted code, codes of it and earlier periods are rejected so they can''t be replayed';
+
+CREATE TABLE "recovery_codes" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "code_hash" TEXT NOT NULL,
+  "used_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE ("user_id", "code_hash")
+);
+
+COMMENT ON COLUMN "recovery_codes"."code_hash" IS 'SHA-256 of the normalized code, the code itself is only shown once';
+
+CREATE TABLE "login_challenges" (
+  "id" UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  "user_id" UUID NOT NULL,
+  "token_hash" TEXT UNIQUE NOT NULL,
+  "attempts" INT NOT NULL DEFAULT 0,
+  "expires_at" TIMESTAMPTZ NOT NULL,
+  "used_at" TIMESTAMPTZ,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN "login_challenges"."token_hash" IS 'SHA-256 of the token of a sign-in that passed the password and waits for a second factor';
+
+COMMENT ON COLUMN "login_challenges"."attempts" IS 'codes tried with the challenge, it stops working after a few';
+
+CREATE INDEX ON "login_challenges" ("user_id", "created_at");
+
+ALTER TABLE "totp_credentials" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "login_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE "totp_credentials" DROP COLUMN IF EXISTS "last_failed_at";
+
+ALTER TABLE "totp_credentials" DROP COLUMN IF EXISTS "failed_attempts";
@@ -0,0 +1,7 @@
+ALTER TABLE "totp_credentials" ADD COLUMN "failed_attempts" INT NOT NULL DEFAULT 0;
+
+ALTER TABLE "totp_credentials" ADD COLUMN "last_failed_at" TIMESTAMPTZ;
+
+COMMENT ON COLUMN "totp_credentials"."failed_attempts" IS 'codes tried since the last one that was accepted, too many in a row lock the second factor for a while';
+
+COMMENT ON COLUMN "totp_credentials"."last_failed_at" IS 'when the last of failed_attempts was made';
@@ -8,6 +8,7 @@ import (
 	context "context"
 	db "m1thrandir225/your_time/db/sqlc"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,92 +37,2172 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddTaskTag mocks base method.
+func (m *MockStore) AddTaskTag(arg0 context.Context, arg1 db.AddTaskTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskTag indicates an expected call of AddTaskTag.
+func (mr *MockStoreMockRecorder) AddTaskTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskTag", reflect.TypeOf((*MockStore)(nil).AddTaskTag), arg0, arg1)
+}
+
+// AdvanceRecurringTask mocks base method.
+func (m *MockStore) AdvanceRecurringTask(arg0 context.Context, arg1 db.AdvanceRecurringTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceRecurringTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceRecurringTask indicates an expected call of AdvanceRecurringTask.
+func (mr *MockStoreMockRecorder) AdvanceRecurringTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceRecurringTask", reflect.TypeOf((*MockStore)(nil).AdvanceRecurringTask), arg0, arg1)
+}
+
+// BlockAccessTokenSession mocks base method.
+func (m *MockStore) BlockAccessTokenSession(arg0 context.Context, arg1 uuid.NullUUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockAccessTokenSession", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockAccessTokenSession indicates an expected call of BlockAccessTokenSession.
+func (mr *MockStoreMockRecorder) BlockAccessTokenSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockAccessTokenSession", reflect.TypeOf((*MockStore)(nil).BlockAccessTokenSession), arg0, arg1)
+}
+
+// BlockSessionFamily mocks base method.
+func (m *MockStore) BlockSessionFamily(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSessionFamily indicates an expected call of BlockSessionFamily.
+func (mr *MockStoreMockRecorder) BlockSessionFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSessionFamily", reflect.TypeOf((*MockStore)(nil).BlockSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CancelPendingReminderDeliveries mocks base method.
+func (m *MockStore) CancelPendingReminderDeliveries(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingReminderDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPendingReminderDeliveries indicates an expected call of CancelPendingReminderDeliveries.
+func (mr *MockStoreMockRecorder) CancelPendingReminderDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingReminderDeliveries", reflect.TypeOf((*MockStore)(nil).CancelPendingReminderDeliveries), arg0, arg1)
+}
+
+// CancelReminder mocks base method.
+func (m *MockStore) CancelReminder(arg0 context.Context, arg1 uuid.UUID) (db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelReminder indicates an expected call of CancelReminder.
+func (mr *MockStoreMockRecorder) CancelReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReminder", reflect.TypeOf((*MockStore)(nil).CancelReminder), arg0, arg1)
+}
+
+// CancelWebhookDeliveries mocks base method.
+func (m *MockStore) CancelWebhookDeliveries(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelWebhookDeliveries indicates an expected call of CancelWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CancelWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CancelWebhookDeliveries), arg0, arg1)
+}
+
+// ChangeEmailTx mocks base method.
+func (m *MockStore) ChangeEmailTx(arg0 context.Context, arg1 db.ChangeEmailTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEmailTx indicates an expected call of ChangeEmailTx.
+func (mr *MockStoreMockRecorder) ChangeEmailTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmailTx", reflect.TypeOf((*MockStore)(nil).ChangeEmailTx), arg0, arg1)
+}
+
+// ClaimDigest mocks base method.
+func (m *MockStore) ClaimDigest(arg0 context.Context, arg1 db.ClaimDigestParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDigest", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDigest indicates an expected call of ClaimDigest.
+func (mr *MockStoreMockRecorder) ClaimDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDigest", reflect.TypeOf((*MockStore)(nil).ClaimDigest), arg0, arg1)
+}
+
+// ClaimDueReminders mocks base method.
+func (m *MockStore) ClaimDueReminders(arg0 context.Context, arg1 db.ClaimDueRemindersParams) ([]db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReminders indicates an expected call of ClaimDueReminders.
+func (mr *MockStoreMockRecorder) ClaimDueReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReminders", reflect.TypeOf((*MockStore)(nil).ClaimDueReminders), arg0, arg1)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(arg0 context.Context, arg1 db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), arg0, arg1)
+}
+
+// ClaimLoginChallengeAttempt mocks base method.
+func (m *MockStore) ClaimLoginChallengeAttempt(arg0 context.Context, arg1 db.ClaimLoginChallengeAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimLoginChallengeAttempt", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimLoginChallengeAttempt indicates an expected call of ClaimLoginChallengeAttempt.
+func (mr *MockStoreMockRecorder) ClaimLoginChallengeAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimLoginChallengeAttempt", reflect.TypeOf((*MockStore)(nil).ClaimLoginChallengeAttempt), arg0, arg1)
+}
+
+// ClaimTOTPAttempt mocks base method.
+func (m *MockStore) ClaimTOTPAttempt(arg0 context.Context, arg1 db.ClaimTOTPAttemptParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTOTPAttempt", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTOTPAttempt indicates an expected call of ClaimTOTPAttempt.
+func (mr *MockStoreMockRecorder) ClaimTOTPAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTOTPAttempt", reflect.TypeOf((*MockStore)(nil).ClaimTOTPAttempt), arg0, arg1)
+}
+
+// ClaimVerificationEmail mocks base method.
+func (m *MockStore) ClaimVerificationEmail(arg0 context.Context, arg1 db.ClaimVerificationEmailParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimVerificationEmail", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimVerificationEmail indicates an expected call of ClaimVerificationEmail.
+func (mr *MockStoreMockRecorder) ClaimVerificationEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimVerificationEmail", reflect.TypeOf((*MockStore)(nil).ClaimVerificationEmail), arg0, arg1)
+}
+
+// CompleteTask mocks base method.
+func (m *MockStore) CompleteTask(arg0 context.Context, arg1 uuid.UUID) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTask indicates an expected call of CompleteTask.
+func (mr *MockStoreMockRecorder) CompleteTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTask", reflect.TypeOf((*MockStore)(nil).CompleteTask), arg0, arg1)
+}
+
+// CompleteTaskDescendants mocks base method.
+func (m *MockStore) CompleteTaskDescendants(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTaskDescendants", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTaskDescendants indicates an expected call of CompleteTaskDescendants.
+func (mr *MockStoreMockRecorder) CompleteTaskDescendants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTaskDescendants", reflect.TypeOf((*MockStore)(nil).CompleteTaskDescendants), arg0, arg1)
+}
+
+// CompleteTaskTx mocks base method.
+func (m *MockStore) CompleteTaskTx(arg0 context.Context, arg1 uuid.UUID) (db.TaskTreeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskTreeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTaskTx indicates an expected call of CompleteTaskTx.
+func (mr *MockStoreMockRecorder) CompleteTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTaskTx", reflect.TypeOf((*MockStore)(nil).CompleteTaskTx), arg0, arg1)
+}
+
+// ConfirmTOTPCredential mocks base method.
+func (m *MockStore) ConfirmTOTPCredential(arg0 context.Context, arg1 db.ConfirmTOTPCredentialParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTPCredential", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTPCredential indicates an expected call of ConfirmTOTPCredential.
+func (mr *MockStoreMockRecorder) ConfirmTOTPCredential(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPCredential", reflect.TypeOf((*MockStore)(nil).ConfirmTOTPCredential), arg0, arg1)
+}
+
+// CountLoginChallengesSince mocks base method.
+func (m *MockStore) CountLoginChallengesSince(arg0 context.Context, arg1 db.CountLoginChallengesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLoginChallengesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLoginChallengesSince indicates an expected call of CountLoginChallengesSince.
+func (mr *MockStoreMockRecorder) CountLoginChallengesSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLoginChallengesSince", reflect.TypeOf((*MockStore)(nil).CountLoginChallengesSince), arg0, arg1)
+}
+
+// CountPasswordResetsSince mocks base method.
+func (m *MockStore) CountPasswordResetsSince(arg0 context.Context, arg1 db.CountPasswordResetsSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordResetsSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPasswordResetsSince indicates an expected call of CountPasswordResetsSince.
+func (mr *MockStoreMockRecorder) CountPasswordResetsSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordResetsSince", reflect.TypeOf((*MockStore)(nil).CountPasswordResetsSince), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockStore) CountUnusedRecoveryCodes(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockStoreMockRecorder) CountUnusedRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockStore)(nil).CountUnusedRecoveryCodes), arg0, arg1)
+}
+
+// CreateDefaultReminder mocks base method.
+func (m *MockStore) CreateDefaultReminder(arg0 context.Context, arg1 db.CreateDefaultReminderParams) (db.DefaultReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDefaultReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.DefaultReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDefaultReminder indicates an expected call of CreateDefaultReminder.
+func (mr *MockStoreMockRecorder) CreateDefaultReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDefaultReminder", reflect.TypeOf((*MockStore)(nil).CreateDefaultReminder), arg0, arg1)
+}
+
+// CreateLoginChallenge mocks base method.
+func (m *MockStore) CreateLoginChallenge(arg0 context.Context, arg1 db.CreateLoginChallengeParams) (db.LoginChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginChallenge indicates an expected call of CreateLoginChallenge.
+func (mr *MockStoreMockRecorder) CreateLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginChallenge", reflect.TypeOf((*MockStore)(nil).CreateLoginChallenge), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStore) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStoreMockRecorder) CreatePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStore)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreateProject mocks base method.
+func (m *MockStore) CreateProject(arg0 context.Context, arg1 db.CreateProjectParams) (db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", arg0, arg1)
+	ret0, _ := ret[0].(db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockStoreMockRecorder) CreateProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockStore)(nil).CreateProject), arg0, arg1)
+}
+
+// CreateQuietHour mocks base method.
+func (m *MockStore) CreateQuietHour(arg0 context.Context, arg1 db.CreateQuietHourParams) (db.QuietHour, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuietHour", arg0, arg1)
+	ret0, _ := ret[0].(db.QuietHour)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateQuietHour indicates an expected call of CreateQuietHour.
+func (mr *MockStoreMockRecorder) CreateQuietHour(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuietHour", reflect.TypeOf((*MockStore)(nil).CreateQuietHour), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateReminderSnooze mocks base method.
+func (m *MockStore) CreateReminderSnooze(arg0 context.Context, arg1 db.CreateReminderSnoozeParams) (db.ReminderSnooze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReminderSnooze", arg0, arg1)
+	ret0, _ := ret[0].(db.ReminderSnooze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReminderSnooze indicates an expected call of CreateReminderSnooze.
+func (mr *MockStoreMockRecorder) CreateReminderSnooze(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReminderSnooze", reflect.TypeOf((*MockStore)(nil).CreateReminderSnooze), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateTag mocks base method.
+func (m *MockStore) CreateTag(arg0 context.Context, arg1 db.CreateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockStoreMockRecorder) CreateTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockStore)(nil).CreateTag), arg0, arg1)
+}
+
 // CreateTask mocks base method.
 func (m *MockStore) CreateTask(arg0 context.Context, arg1 db.CreateTaskParams) (db.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTask", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTask indicates an expected call of CreateTask.
+func (mr *MockStoreMockRecorder) CreateTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockStore)(nil).CreateTask), arg0, arg1)
+}
+
+// CreateTaskReminder mocks base method.
+func (m *MockStore) CreateTaskReminder(arg0 context.Context, arg1 db.CreateTaskReminderParams) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskReminder indicates an expected call of CreateTaskReminder.
+func (mr *MockStoreMockRecorder) CreateTaskReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskReminder", reflect.TypeOf((*MockStore)(nil).CreateTaskReminder), arg0, arg1)
+}
+
+// CreateTaskTx mocks base method.
+func (m *MockStore) CreateTaskTx(arg0 context.Context, arg1 db.CreateTaskTxParams) (db.TaskTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskTx indicates an expected call of CreateTaskTx.
+func (mr *MockStoreMockRecorder) CreateTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskTx", reflect.TypeOf((*MockStore)(nil).CreateTaskTx), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(arg0 context.Context, arg1 db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), arg0, arg1)
+}
+
+// DeferReminderDelivery mocks base method.
+func (m *MockStore) DeferReminderDelivery(arg0 context.Context, arg1 db.DeferReminderDeliveryParams) (db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeferReminderDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeferReminderDelivery indicates an expected call of DeferReminderDelivery.
+func (mr *MockStoreMockRecorder) DeferReminderDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeferReminderDelivery", reflect.TypeOf((*MockStore)(nil).DeferReminderDelivery), arg0, arg1)
+}
+
+// DeleteDefaultReminders mocks base method.
+func (m *MockStore) DeleteDefaultReminders(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDefaultReminders", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDefaultReminders indicates an expected call of DeleteDefaultReminders.
+func (mr *MockStoreMockRecorder) DeleteDefaultReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDefaultReminders", reflect.TypeOf((*MockStore)(nil).DeleteDefaultReminders), arg0, arg1)
+}
+
+// DeleteExpiredRevokedTokens mocks base method.
+func (m *MockStore) DeleteExpiredRevokedTokens(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevokedTokens", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRevokedTokens indicates an expected call of DeleteExpiredRevokedTokens.
+func (mr *MockStoreMockRecorder) DeleteExpiredRevokedTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevokedTokens", reflect.TypeOf((*MockStore)(nil).DeleteExpiredRevokedTokens), arg0, arg1)
+}
+
+// DeleteNotification mocks base method.
+func (m *MockStore) DeleteNotification(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotification indicates an expected call of DeleteNotification.
+func (mr *MockStoreMockRecorder) DeleteNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotification", reflect.TypeOf((*MockStore)(nil).DeleteNotification), arg0, arg1)
+}
+
+// DeleteProject mocks base method.
+func (m *MockStore) DeleteProject(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockStoreMockRecorder) DeleteProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockStore)(nil).DeleteProject), arg0, arg1)
+}
+
+// DeleteQuietHours mocks base method.
+func (m *MockStore) DeleteQuietHours(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQuietHours", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQuietHours indicates an expected call of DeleteQuietHours.
+func (mr *MockStoreMockRecorder) DeleteQuietHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuietHours", reflect.TypeOf((*MockStore)(nil).DeleteQuietHours), arg0, arg1)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteTOTPCredential mocks base method.
+func (m *MockStore) DeleteTOTPCredential(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTPCredential", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTOTPCredential indicates an expected call of DeleteTOTPCredential.
+func (mr *MockStoreMockRecorder) DeleteTOTPCredential(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPCredential", reflect.TypeOf((*MockStore)(nil).DeleteTOTPCredential), arg0, arg1)
+}
+
+// DeleteTag mocks base method.
+func (m *MockStore) DeleteTag(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockStoreMockRecorder) DeleteTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockStore)(nil).DeleteTag), arg0, arg1)
+}
+
+// DeleteTaskReminder mocks base method.
+func (m *MockStore) DeleteTaskReminder(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskReminder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskReminder indicates an expected call of DeleteTaskReminder.
+func (mr *MockStoreMockRecorder) DeleteTaskReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskReminder", reflect.TypeOf((*MockStore)(nil).DeleteTaskReminder), arg0, arg1)
+}
+
+// DeleteTaskReminders mocks base method.
+func (m *MockStore) DeleteTaskReminders(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskReminders", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskReminders indicates an expected call of DeleteTaskReminders.
+func (mr *MockStoreMockRecorder) DeleteTaskReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskReminders", reflect.TypeOf((*MockStore)(nil).DeleteTaskReminders), arg0, arg1)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStore) DeleteWebhookEndpoint(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebhookEndpoint), arg0, arg1)
+}
+
+// DisableTOTPTx mocks base method.
+func (m *MockStore) DisableTOTPTx(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTPTx indicates an expected call of DisableTOTPTx.
+func (mr *MockStoreMockRecorder) DisableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTPTx", reflect.TypeOf((*MockStore)(nil).DisableTOTPTx), arg0, arg1)
+}
+
+// DismissReminderTx mocks base method.
+func (m *MockStore) DismissReminderTx(arg0 context.Context, arg1 uuid.UUID) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissReminderTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DismissReminderTx indicates an expected call of DismissReminderTx.
+func (mr *MockStoreMockRecorder) DismissReminderTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissReminderTx", reflect.TypeOf((*MockStore)(nil).DismissReminderTx), arg0, arg1)
+}
+
+// DismissTaskReminder mocks base method.
+func (m *MockStore) DismissTaskReminder(arg0 context.Context, arg1 uuid.UUID) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissTaskReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DismissTaskReminder indicates an expected call of DismissTaskReminder.
+func (mr *MockStoreMockRecorder) DismissTaskReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissTaskReminder", reflect.TypeOf((*MockStore)(nil).DismissTaskReminder), arg0, arg1)
+}
+
+// EnableTOTPTx mocks base method.
+func (m *MockStore) EnableTOTPTx(arg0 context.Context, arg1 db.EnableTOTPTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTPTx indicates an expected call of EnableTOTPTx.
+func (mr *MockStoreMockRecorder) EnableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTPTx", reflect.TypeOf((*MockStore)(nil).EnableTOTPTx), arg0, arg1)
+}
+
+// EnqueueDueReminders mocks base method.
+func (m *MockStore) EnqueueDueReminders(arg0 context.Context, arg1 db.EnqueueDueRemindersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDueReminders", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDueReminders indicates an expected call of EnqueueDueReminders.
+func (mr *MockStoreMockRecorder) EnqueueDueReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDueReminders", reflect.TypeOf((*MockStore)(nil).EnqueueDueReminders), arg0, arg1)
+}
+
+// EnqueueWebhookEvent mocks base method.
+func (m *MockStore) EnqueueWebhookEvent(arg0 context.Context, arg1 db.EnqueueWebhookEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookEvent", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookEvent indicates an expected call of EnqueueWebhookEvent.
+func (mr *MockStoreMockRecorder) EnqueueWebhookEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookEvent", reflect.TypeOf((*MockStore)(nil).EnqueueWebhookEvent), arg0, arg1)
+}
+
+// GetChildTasks mocks base method.
+func (m *MockStore) GetChildTasks(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildTasks indicates an expected call of GetChildTasks.
+func (mr *MockStoreMockRecorder) GetChildTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildTasks", reflect.TypeOf((*MockStore)(nil).GetChildTasks), arg0, arg1)
+}
+
+// GetDigestSettings mocks base method.
+func (m *MockStore) GetDigestSettings(arg0 context.Context, arg1 uuid.UUID) (db.DigestSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigestSettings", arg0, arg1)
+	ret0, _ := ret[0].(db.DigestSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigestSettings indicates an expected call of GetDigestSettings.
+func (mr *MockStoreMockRecorder) GetDigestSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestSettings", reflect.TypeOf((*MockStore)(nil).GetDigestSettings), arg0, arg1)
+}
+
+// GetLatestFiredReminder mocks base method.
+func (m *MockStore) GetLatestFiredReminder(arg0 context.Context, arg1 db.GetLatestFiredReminderParams) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestFiredReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestFiredReminder indicates an expected call of GetLatestFiredReminder.
+func (mr *MockStoreMockRecorder) GetLatestFiredReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestFiredReminder", reflect.TypeOf((*MockStore)(nil).GetLatestFiredReminder), arg0, arg1)
+}
+
+// GetLoginChallengeByTokenHash mocks base method.
+func (m *MockStore) GetLoginChallengeByTokenHash(arg0 context.Context, arg1 string) (db.LoginChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginChallengeByTokenHash", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginChallengeByTokenHash indicates an expected call of GetLoginChallengeByTokenHash.
+func (mr *MockStoreMockRecorder) GetLoginChallengeByTokenHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginChallengeByTokenHash", reflect.TypeOf((*MockStore)(nil).GetLoginChallengeByTokenHash), arg0, arg1)
+}
+
+// GetNotification mocks base method.
+func (m *MockStore) GetNotification(arg0 context.Context, arg1 uuid.UUID) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockStoreMockRecorder) GetNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockStore)(nil).GetNotification), arg0, arg1)
+}
+
+// GetOpenTasksByUser mocks base method.
+func (m *MockStore) GetOpenTasksByUser(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenTasksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenTasksByUser indicates an expected call of GetOpenTasksByUser.
+func (mr *MockStoreMockRecorder) GetOpenTasksByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenTasksByUser", reflect.TypeOf((*MockStore)(nil).GetOpenTasksByUser), arg0, arg1)
+}
+
+// GetPasswordResetByTokenHash mocks base method.
+func (m *MockStore) GetPasswordResetByTokenHash(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetByTokenHash", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetByTokenHash indicates an expected call of GetPasswordResetByTokenHash.
+func (mr *MockStoreMockRecorder) GetPasswordResetByTokenHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetByTokenHash", reflect.TypeOf((*MockStore)(nil).GetPasswordResetByTokenHash), arg0, arg1)
+}
+
+// GetProjectByID mocks base method.
+func (m *MockStore) GetProjectByID(arg0 context.Context, arg1 uuid.UUID) (db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectByID indicates an expected call of GetProjectByID.
+func (mr *MockStoreMockRecorder) GetProjectByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectByID", reflect.TypeOf((*MockStore)(nil).GetProjectByID), arg0, arg1)
+}
+
+// GetProjectTaskCounts mocks base method.
+func (m *MockStore) GetProjectTaskCounts(arg0 context.Context, arg1 uuid.UUID) ([]db.GetProjectTaskCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectTaskCounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetProjectTaskCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectTaskCounts indicates an expected call of GetProjectTaskCounts.
+func (mr *MockStoreMockRecorder) GetProjectTaskCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectTaskCounts", reflect.TypeOf((*MockStore)(nil).GetProjectTaskCounts), arg0, arg1)
+}
+
+// GetRemindersForTasks mocks base method.
+func (m *MockStore) GetRemindersForTasks(arg0 context.Context, arg1 []uuid.UUID) ([]db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemindersForTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemindersForTasks indicates an expected call of GetRemindersForTasks.
+func (mr *MockStoreMockRecorder) GetRemindersForTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemindersForTasks", reflect.TypeOf((*MockStore)(nil).GetRemindersForTasks), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetSubtaskRollups mocks base method.
+func (m *MockStore) GetSubtaskRollups(arg0 context.Context, arg1 []uuid.UUID) ([]db.GetSubtaskRollupsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtaskRollups", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetSubtaskRollupsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtaskRollups indicates an expected call of GetSubtaskRollups.
+func (mr *MockStoreMockRecorder) GetSubtaskRollups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtaskRollups", reflect.TypeOf((*MockStore)(nil).GetSubtaskRollups), arg0, arg1)
+}
+
+// GetSubtreeHeight mocks base method.
+func (m *MockStore) GetSubtreeHeight(arg0 context.Context, arg1 uuid.UUID) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtreeHeight", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtreeHeight indicates an expected call of GetSubtreeHeight.
+func (mr *MockStoreMockRecorder) GetSubtreeHeight(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtreeHeight", reflect.TypeOf((*MockStore)(nil).GetSubtreeHeight), arg0, arg1)
+}
+
+// GetTOTPCredential mocks base method.
+func (m *MockStore) GetTOTPCredential(arg0 context.Context, arg1 uuid.UUID) (db.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTPCredential", arg0, arg1)
+	ret0, _ := ret[0].(db.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTPCredential indicates an expected call of GetTOTPCredential.
+func (mr *MockStoreMockRecorder) GetTOTPCredential(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTPCredential", reflect.TypeOf((*MockStore)(nil).GetTOTPCredential), arg0, arg1)
+}
+
+// GetTagByID mocks base method.
+func (m *MockStore) GetTagByID(arg0 context.Context, arg1 uuid.UUID) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByID indicates an expected call of GetTagByID.
+func (mr *MockStoreMockRecorder) GetTagByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByID", reflect.TypeOf((*MockStore)(nil).GetTagByID), arg0, arg1)
+}
+
+// GetTagsForTasks mocks base method.
+func (m *MockStore) GetTagsForTasks(arg0 context.Context, arg1 []uuid.UUID) ([]db.GetTagsForTasksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsForTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetTagsForTasksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsForTasks indicates an expected call of GetTagsForTasks.
+func (mr *MockStoreMockRecorder) GetTagsForTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsForTasks", reflect.TypeOf((*MockStore)(nil).GetTagsForTasks), arg0, arg1)
+}
+
+// GetTaskAncestors mocks base method.
+func (m *MockStore) GetTaskAncestors(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskAncestors", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskAncestors indicates an expected call of GetTaskAncestors.
+func (mr *MockStoreMockRecorder) GetTaskAncestors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskAncestors", reflect.TypeOf((*MockStore)(nil).GetTaskAncestors), arg0, arg1)
+}
+
+// GetTaskByID mocks base method.
+func (m *MockStore) GetTaskByID(arg0 context.Context, arg1 uuid.UUID) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskByID indicates an expected call of GetTaskByID.
+func (mr *MockStoreMockRecorder) GetTaskByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockStore)(nil).GetTaskByID), arg0, arg1)
+}
+
+// GetTaskReminderByID mocks base method.
+func (m *MockStore) GetTaskReminderByID(arg0 context.Context, arg1 uuid.UUID) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskReminderByID", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskReminderByID indicates an expected call of GetTaskReminderByID.
+func (mr *MockStoreMockRecorder) GetTaskReminderByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskReminderByID", reflect.TypeOf((*MockStore)(nil).GetTaskReminderByID), arg0, arg1)
+}
+
+// GetTasksByUser mocks base method.
+func (m *MockStore) GetTasksByUser(arg0 context.Context, arg1 db.GetTasksByUserParams) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByUser indicates an expected call of GetTasksByUser.
+func (mr *MockStoreMockRecorder) GetTasksByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByUser", reflect.TypeOf((*MockStore)(nil).GetTasksByUser), arg0, arg1)
+}
+
+// GetTrashedTasksByUser mocks base method.
+func (m *MockStore) GetTrashedTasksByUser(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashedTasksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrashedTasksByUser indicates an expected call of GetTrashedTasksByUser.
+func (mr *MockStoreMockRecorder) GetTrashedTasksByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashedTasksByUser", reflect.TypeOf((*MockStore)(nil).GetTrashedTasksByUser), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByID mocks base method.
+func (m *MockStore) GetUserByID(arg0 context.Context, arg1 uuid.UUID) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockStoreMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0, arg1)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(arg0 context.Context, arg1 uuid.UUID) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), arg0, arg1)
+}
+
+// ListDefaultReminders mocks base method.
+func (m *MockStore) ListDefaultReminders(arg0 context.Context, arg1 uuid.UUID) ([]db.DefaultReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDefaultReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.DefaultReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDefaultReminders indicates an expected call of ListDefaultReminders.
+func (mr *MockStoreMockRecorder) ListDefaultReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDefaultReminders", reflect.TypeOf((*MockStore)(nil).ListDefaultReminders), arg0, arg1)
+}
+
+// ListDigestSubscribers mocks base method.
+func (m *MockStore) ListDigestSubscribers(arg0 context.Context) ([]db.ListDigestSubscribersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDigestSubscribers", arg0)
+	ret0, _ := ret[0].([]db.ListDigestSubscribersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDigestSubscribers indicates an expected call of ListDigestSubscribers.
+func (mr *MockStoreMockRecorder) ListDigestSubscribers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDigestSubscribers", reflect.TypeOf((*MockStore)(nil).ListDigestSubscribers), arg0)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListProjectsByUser mocks base method.
+func (m *MockStore) ListProjectsByUser(arg0 context.Context, arg1 db.ListProjectsByUserParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjectsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjectsByUser indicates an expected call of ListProjectsByUser.
+func (mr *MockStoreMockRecorder) ListProjectsByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectsByUser", reflect.TypeOf((*MockStore)(nil).ListProjectsByUser), arg0, arg1)
+}
+
+// ListQuietHours mocks base method.
+func (m *MockStore) ListQuietHours(arg0 context.Context, arg1 uuid.UUID) ([]db.QuietHour, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuietHours", arg0, arg1)
+	ret0, _ := ret[0].([]db.QuietHour)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuietHours indicates an expected call of ListQuietHours.
+func (mr *MockStoreMockRecorder) ListQuietHours(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuietHours", reflect.TypeOf((*MockStore)(nil).ListQuietHours), arg0, arg1)
+}
+
+// ListReminderDeliveries mocks base method.
+func (m *MockStore) ListReminderDeliveries(arg0 context.Context, arg1 uuid.UUID) ([]db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReminderDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReminderDeliveries indicates an expected call of ListReminderDeliveries.
+func (mr *MockStoreMockRecorder) ListReminderDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReminderDeliveries", reflect.TypeOf((*MockStore)(nil).ListReminderDeliveries), arg0, arg1)
+}
+
+// ListReminderSnoozes mocks base method.
+func (m *MockStore) ListReminderSnoozes(arg0 context.Context, arg1 uuid.UUID) ([]db.ReminderSnooze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReminderSnoozes", arg0, arg1)
+	ret0, _ := ret[0].([]db.ReminderSnooze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReminderSnoozes indicates an expected call of ListReminderSnoozes.
+func (mr *MockStoreMockRecorder) ListReminderSnoozes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReminderSnoozes", reflect.TypeOf((*MockStore)(nil).ListReminderSnoozes), arg0, arg1)
+}
+
+// ListRevokedTokens mocks base method.
+func (m *MockStore) ListRevokedTokens(arg0 context.Context, arg1 time.Time) ([]db.ListRevokedTokensRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevokedTokens", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRevokedTokensRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevokedTokens indicates an expected call of ListRevokedTokens.
+func (mr *MockStoreMockRecorder) ListRevokedTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevokedTokens", reflect.TypeOf((*MockStore)(nil).ListRevokedTokens), arg0, arg1)
+}
+
+// ListTagsByUser mocks base method.
+func (m *MockStore) ListTagsByUser(arg0 context.Context, arg1 uuid.UUID) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsByUser indicates an expected call of ListTagsByUser.
+func (mr *MockStoreMockRecorder) ListTagsByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsByUser", reflect.TypeOf((*MockStore)(nil).ListTagsByUser), arg0, arg1)
+}
+
+// ListTaskReminders mocks base method.
+func (m *MockStore) ListTaskReminders(arg0 context.Context, arg1 uuid.UUID) ([]db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskReminders indicates an expected call of ListTaskReminders.
+func (mr *MockStoreMockRecorder) ListTaskReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskReminders", reflect.TypeOf((*MockStore)(nil).ListTaskReminders), arg0, arg1)
+}
+
+// ListTasks mocks base method.
+func (m *MockStore) ListTasks(arg0 context.Context, arg1 db.ListTasksParams) ([]db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockStoreMockRecorder) ListTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockStore)(nil).ListTasks), arg0, arg1)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
+}
+
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(arg0 context.Context, arg1 uuid.UUID) ([]db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), arg0, arg1)
+}
+
+// LockTaskTree mocks base method.
+func (m *MockStore) LockTaskTree(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTaskTree", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTaskTree indicates an expected call of LockTaskTree.
+func (mr *MockStoreMockRecorder) LockTaskTree(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTaskTree", reflect.TypeOf((*MockStore)(nil).LockTaskTree), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 uuid.UUID) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// MarkReminderFailed mocks base method.
+func (m *MockStore) MarkReminderFailed(arg0 context.Context, arg1 db.MarkReminderFailedParams) (db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminderFailed", arg0, arg1)
+	ret0, _ := ret[0].(db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReminderFailed indicates an expected call of MarkReminderFailed.
+func (mr *MockStoreMockRecorder) MarkReminderFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderFailed", reflect.TypeOf((*MockStore)(nil).MarkReminderFailed), arg0, arg1)
+}
+
+// MarkReminderSent mocks base method.
+func (m *MockStore) MarkReminderSent(arg0 context.Context, arg1 uuid.UUID) (db.ReminderDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminderSent", arg0, arg1)
+	ret0, _ := ret[0].(db.ReminderDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReminderSent indicates an expected call of MarkReminderSent.
+func (mr *MockStoreMockRecorder) MarkReminderSent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockStore)(nil).MarkReminderSent), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// MarkWebhookDeliverySent mocks base method.
+func (m *MockStore) MarkWebhookDeliverySent(arg0 context.Context, arg1 db.MarkWebhookDeliverySentParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliverySent", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkWebhookDeliverySent indicates an expected call of MarkWebhookDeliverySent.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliverySent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliverySent", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliverySent), arg0, arg1)
+}
+
+// MoveTask mocks base method.
+func (m *MockStore) MoveTask(arg0 context.Context, arg1 db.MoveTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTask indicates an expected call of MoveTask.
+func (mr *MockStoreMockRecorder) MoveTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTask", reflect.TypeOf((*MockStore)(nil).MoveTask), arg0, arg1)
+}
+
+// MoveTaskTx mocks base method.
+func (m *MockStore) MoveTaskTx(arg0 context.Context, arg1 db.MoveTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTaskTx indicates an expected call of MoveTaskTx.
+func (mr *MockStoreMockRecorder) MoveTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTaskTx", reflect.TypeOf((*MockStore)(nil).MoveTaskTx), arg0, arg1)
+}
+
+// PurgeTrashedTasks mocks base method.
+func (m *MockStore) PurgeTrashedTasks(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrashedTasks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrashedTasks indicates an expected call of PurgeTrashedTasks.
+func (mr *MockStoreMockRecorder) PurgeTrashedTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrashedTasks", reflect.TypeOf((*MockStore)(nil).PurgeTrashedTasks), arg0, arg1)
+}
+
+// RecordWebhookFailure mocks base method.
+func (m *MockStore) RecordWebhookFailure(arg0 context.Context, arg1 db.RecordWebhookFailureParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookFailure", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookFailure indicates an expected call of RecordWebhookFailure.
+func (mr *MockStoreMockRecorder) RecordWebhookFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookFailure", reflect.TypeOf((*MockStore)(nil).RecordWebhookFailure), arg0, arg1)
+}
+
+// RecordWebhookSuccess mocks base method.
+func (m *MockStore) RecordWebhookSuccess(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookSuccess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookSuccess indicates an expected call of RecordWebhookSuccess.
+func (mr *MockStoreMockRecorder) RecordWebhookSuccess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookSuccess", reflect.TypeOf((*MockStore)(nil).RecordWebhookSuccess), arg0, arg1)
+}
+
+// RemoveTaskTags mocks base method.
+func (m *MockStore) RemoveTaskTags(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTaskTags", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTaskTags indicates an expected call of RemoveTaskTags.
+func (mr *MockStoreMockRecorder) RemoveTaskTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTaskTags", reflect.TypeOf((*MockStore)(nil).RemoveTaskTags), arg0, arg1)
+}
+
+// ReopenTask mocks base method.
+func (m *MockStore) ReopenTask(arg0 context.Context, arg1 uuid.UUID) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReopenTask indicates an expected call of ReopenTask.
+func (mr *MockStoreMockRecorder) ReopenTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenTask", reflect.TypeOf((*MockStore)(nil).ReopenTask), arg0, arg1)
+}
+
+// ReplaceDefaultRemindersTx mocks base method.
+func (m *MockStore) ReplaceDefaultRemindersTx(arg0 context.Context, arg1 db.ReplaceDefaultRemindersTxParams) ([]db.DefaultReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceDefaultRemindersTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.DefaultReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceDefaultRemindersTx indicates an expected call of ReplaceDefaultRemindersTx.
+func (mr *MockStoreMockRecorder) ReplaceDefaultRemindersTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceDefaultRemindersTx", reflect.TypeOf((*MockStore)(nil).ReplaceDefaultRemindersTx), arg0, arg1)
+}
+
+// ReplaceQuietHoursTx mocks base method.
+func (m *MockStore) ReplaceQuietHoursTx(arg0 context.Context, arg1 db.ReplaceQuietHoursTxParams) ([]db.QuietHour, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceQuietHoursTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.QuietHour)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceQuietHoursTx indicates an expected call of ReplaceQuietHoursTx.
+func (mr *MockStoreMockRecorder) ReplaceQuietHoursTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceQuietHoursTx", reflect.TypeOf((*MockStore)(nil).ReplaceQuietHoursTx), arg0, arg1)
+}
+
+// ReplaceRecoveryCodesTx mocks base method.
+func (m *MockStore) ReplaceRecoveryCodesTx(arg0 context.Context, arg1 db.ReplaceRecoveryCodesTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodesTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodesTx indicates an expected call of ReplaceRecoveryCodesTx.
+func (mr *MockStoreMockRecorder) ReplaceRecoveryCodesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodesTx", reflect.TypeOf((*MockStore)(nil).ReplaceRecoveryCodesTx), arg0, arg1)
+}
+
+// RescheduleTaskReminders mocks base method.
+func (m *MockStore) RescheduleTaskReminders(arg0 context.Context, arg1 db.RescheduleTaskRemindersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleTaskReminders", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleTaskReminders indicates an expected call of RescheduleTaskReminders.
+func (mr *MockStoreMockRecorder) RescheduleTaskReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleTaskReminders", reflect.TypeOf((*MockStore)(nil).RescheduleTaskReminders), arg0, arg1)
+}
+
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ResetPasswordTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// ResetTOTPAttempts mocks base method.
+func (m *MockStore) ResetTOTPAttempts(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetTOTPAttempts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetTOTPAttempts indicates an expected call of ResetTOTPAttempts.
+func (mr *MockStoreMockRecorder) ResetTOTPAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetTOTPAttempts", reflect.TypeOf((*MockStore)(nil).ResetTOTPAttempts), arg0, arg1)
+}
+
+// RestoreTask mocks base method.
+func (m *MockStore) RestoreTask(arg0 context.Context, arg1 db.RestoreTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", arg0, arg1)
 	ret0, _ := ret[0].(db.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTask indicates an expected call of CreateTask.
-func (mr *MockStoreMockRecorder) CreateTask(arg0, arg1 interface{}) *gomock.Call {
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockStoreMockRecorder) RestoreTask(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockStore)(nil).CreateTask), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockStore)(nil).RestoreTask), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// RestoreTaskDescendants mocks base method.
+func (m *MockStore) RestoreTaskDescendants(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreTaskDescendants", arg0, arg1)
+	ret0, _ := ret[0].([]db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTaskDescendants indicates an expected call of RestoreTaskDescendants.
+func (mr *MockStoreMockRecorder) RestoreTaskDescendants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTaskDescendants", reflect.TypeOf((*MockStore)(nil).RestoreTaskDescendants), arg0, arg1)
+}
+
+// RestoreTaskTx mocks base method.
+func (m *MockStore) RestoreTaskTx(arg0 context.Context, arg1 db.RestoreTaskParams) (db.TaskTreeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskTreeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTaskTx indicates an expected call of RestoreTaskTx.
+func (mr *MockStoreMockRecorder) RestoreTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTaskTx", reflect.TypeOf((*MockStore)(nil).RestoreTaskTx), arg0, arg1)
+}
+
+// RevokeSessionFamilyAccessTokens mocks base method.
+func (m *MockStore) RevokeSessionFamilyAccessTokens(arg0 context.Context, arg1 uuid.UUID) ([]db.RevokeSessionFamilyAccessTokensRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionFamilyAccessTokens", arg0, arg1)
+	ret0, _ := ret[0].([]db.RevokeSessionFamilyAccessTokensRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessionFamilyAccessTokens indicates an expected call of RevokeSessionFamilyAccessTokens.
+func (mr *MockStoreMockRecorder) RevokeSessionFamilyAccessTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionFamilyAccessTokens", reflect.TypeOf((*MockStore)(nil).RevokeSessionFamilyAccessTokens), arg0, arg1)
+}
+
+// RevokeToken mocks base method.
+func (m *MockStore) RevokeToken(arg0 context.Context, arg1 db.RevokeTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockStoreMockRecorder) RevokeToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockStore)(nil).RevokeToken), arg0, arg1)
+}
+
+// RevokeUserAccessTokens mocks base method.
+func (m *MockStore) RevokeUserAccessTokens(arg0 context.Context, arg1 uuid.UUID) ([]db.RevokeUserAccessTokensRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserAccessTokens", arg0, arg1)
+	ret0, _ := ret[0].([]db.RevokeUserAccessTokensRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserAccessTokens indicates an expected call of RevokeUserAccessTokens.
+func (mr *MockStoreMockRecorder) RevokeUserAccessTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccessTokens", reflect.TypeOf((*MockStore)(nil).RevokeUserAccessTokens), arg0, arg1)
+}
+
+// RotateSession mocks base method.
+func (m *MockStore) RotateSession(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSession", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockStoreMockRecorder) RotateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockStore)(nil).RotateSession), arg0, arg1)
+}
+
+// RotateSessionTx mocks base method.
+func (m *MockStore) RotateSessionTx(arg0 context.Context, arg1 db.RotateSessionTxParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSessionTx indicates an expected call of RotateSessionTx.
+func (mr *MockStoreMockRecorder) RotateSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSessionTx", reflect.TypeOf((*MockStore)(nil).RotateSessionTx), arg0, arg1)
+}
+
+// SetDoNotDisturb mocks base method.
+func (m *MockStore) SetDoNotDisturb(arg0 context.Context, arg1 db.SetDoNotDisturbParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDoNotDisturb", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// SetDoNotDisturb indicates an expected call of SetDoNotDisturb.
+func (mr *MockStoreMockRecorder) SetDoNotDisturb(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDoNotDisturb", reflect.TypeOf((*MockStore)(nil).SetDoNotDisturb), arg0, arg1)
 }
 
-// GetTaskByID mocks base method.
-func (m *MockStore) GetTaskByID(arg0 context.Context, arg1 uuid.UUID) (db.Task, error) {
+// SetProjectArchived mocks base method.
+func (m *MockStore) SetProjectArchived(arg0 context.Context, arg1 db.SetProjectArchivedParams) (db.Project, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetProjectArchived", arg0, arg1)
+	ret0, _ := ret[0].(db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProjectArchived indicates an expected call of SetProjectArchived.
+func (mr *MockStoreMockRecorder) SetProjectArchived(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectArchived", reflect.TypeOf((*MockStore)(nil).SetProjectArchived), arg0, arg1)
+}
+
+// SnoozeReminderTx mocks base method.
+func (m *MockStore) SnoozeReminderTx(arg0 context.Context, arg1 db.SnoozeReminderTxParams) (db.SnoozeReminderTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnoozeReminderTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SnoozeReminderTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnoozeReminderTx indicates an expected call of SnoozeReminderTx.
+func (mr *MockStoreMockRecorder) SnoozeReminderTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnoozeReminderTx", reflect.TypeOf((*MockStore)(nil).SnoozeReminderTx), arg0, arg1)
+}
+
+// SnoozeTaskReminder mocks base method.
+func (m *MockStore) SnoozeTaskReminder(arg0 context.Context, arg1 db.SnoozeTaskReminderParams) (db.TaskReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnoozeTaskReminder", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnoozeTaskReminder indicates an expected call of SnoozeTaskReminder.
+func (mr *MockStoreMockRecorder) SnoozeTaskReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnoozeTaskReminder", reflect.TypeOf((*MockStore)(nil).SnoozeTaskReminder), arg0, arg1)
+}
+
+// TrashTask mocks base method.
+func (m *MockStore) TrashTask(arg0 context.Context, arg1 db.TrashTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashTask", arg0, arg1)
 	ret0, _ := ret[0].(db.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTaskByID indicates an expected call of GetTaskByID.
-func (mr *MockStoreMockRecorder) GetTaskByID(arg0, arg1 interface{}) *gomock.Call {
+// TrashTask indicates an expected call of TrashTask.
+func (mr *MockStoreMockRecorder) TrashTask(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockStore)(nil).GetTaskByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashTask", reflect.TypeOf((*MockStore)(nil).TrashTask), arg0, arg1)
 }
 
-// GetTasksByUser mocks base method.
-func (m *MockStore) GetTasksByUser(arg0 context.Context, arg1 uuid.UUID) ([]db.Task, error) {
+// TrashTaskDescendants mocks base method.
+func (m *MockStore) TrashTaskDescendants(arg0 context.Context, arg1 db.TrashTaskDescendantsParams) ([]db.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasksByUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "TrashTaskDescendants", arg0, arg1)
 	ret0, _ := ret[0].([]db.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTasksByUser indicates an expected call of GetTasksByUser.
-func (mr *MockStoreMockRecorder) GetTasksByUser(arg0, arg1 interface{}) *gomock.Call {
+// TrashTaskDescendants indicates an expected call of TrashTaskDescendants.
+func (mr *MockStoreMockRecorder) TrashTaskDescendants(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByUser", reflect.TypeOf((*MockStore)(nil).GetTasksByUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashTaskDescendants", reflect.TypeOf((*MockStore)(nil).TrashTaskDescendants), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// TrashTaskTx mocks base method.
+func (m *MockStore) TrashTaskTx(arg0 context.Context, arg1 db.TrashTaskParams) (db.TaskTreeTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "TrashTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskTreeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrashTaskTx indicates an expected call of TrashTaskTx.
+func (mr *MockStoreMockRecorder) TrashTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashTaskTx", reflect.TypeOf((*MockStore)(nil).TrashTaskTx), arg0, arg1)
+}
+
+// UpdateProject mocks base method.
+func (m *MockStore) UpdateProject(arg0 context.Context, arg1 db.UpdateProjectParams) (db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", arg0, arg1)
+	ret0, _ := ret[0].(db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockStoreMockRecorder) UpdateProject(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockStore)(nil).UpdateProject), arg0, arg1)
+}
+
+// UpdateTag mocks base method.
+func (m *MockStore) UpdateTag(arg0 context.Context, arg1 db.UpdateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockStoreMockRecorder) UpdateTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockStore)(nil).UpdateTag), arg0, arg1)
+}
+
+// UpdateTask mocks base method.
+func (m *MockStore) UpdateTask(arg0 context.Context, arg1 db.UpdateTaskParams) (db.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockStoreMockRecorder) UpdateTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockStore)(nil).UpdateTask), arg0, arg1)
+}
+
+// UpdateTaskTx mocks base method.
+func (m *MockStore) UpdateTaskTx(arg0 context.Context, arg1 db.UpdateTaskTxParams) (db.TaskTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TaskTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskTx indicates an expected call of UpdateTaskTx.
+func (mr *MockStoreMockRecorder) UpdateTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskTx", reflect.TypeOf((*MockStore)(nil).UpdateTaskTx), arg0, arg1)
+}
+
+// UpdateUser mocks base method.
+func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
 
-// GetUserByID mocks base method.
-func (m *MockStore) GetUserByID(arg0 context.Context, arg1 uuid.UUID) (db.User, error) {
+// UpdateUserEmail mocks base method.
+func (m *MockStore) UpdateUserEmail(arg0 context.Context, arg1 db.UpdateUserEmailParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateUserEmail", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockStoreMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUserEmail indicates an expected call of UpdateUserEmail.
+func (mr *MockStoreMockRecorder) UpdateUserEmail(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEmail", reflect.TypeOf((*MockStore)(nil).UpdateUserEmail), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateWebhookEndpoint mocks base method.
+func (m *MockStore) UpdateWebhookEndpoint(arg0 context.Context, arg1 db.UpdateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookEndpoint indicates an expected call of UpdateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) UpdateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).UpdateWebhookEndpoint), arg0, arg1)
+}
+
+// UpsertDigestSettings mocks base method.
+func (m *MockStore) UpsertDigestSettings(arg0 context.Context, arg1 db.UpsertDigestSettingsParams) (db.DigestSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDigestSettings", arg0, arg1)
+	ret0, _ := ret[0].(db.DigestSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDigestSettings indicates an expected call of UpsertDigestSettings.
+func (mr *MockStoreMockRecorder) UpsertDigestSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDigestSettings", reflect.TypeOf((*MockStore)(nil).UpsertDigestSettings), arg0, arg1)
+}
+
+// UpsertTOTPCredential mocks base method.
+func (m *MockStore) UpsertTOTPCredential(arg0 context.Context, arg1 db.UpsertTOTPCredentialParams) (db.TotpCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTOTPCredential", arg0, arg1)
+	ret0, _ := ret[0].(db.TotpCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTOTPCredential indicates an expected call of UpsertTOTPCredential.
+func (mr *MockStoreMockRecorder) UpsertTOTPCredential(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTOTPCredential", reflect.TypeOf((*MockStore)(nil).UpsertTOTPCredential), arg0, arg1)
+}
+
+// UpsertTagByName mocks base method.
+func (m *MockStore) UpsertTagByName(arg0 context.Context, arg1 db.UpsertTagByNameParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTagByName", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTagByName indicates an expected call of UpsertTagByName.
+func (mr *MockStoreMockRecorder) UpsertTagByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTagByName", reflect.TypeOf((*MockStore)(nil).UpsertTagByName), arg0, arg1)
+}
+
+// UseLoginChallenge mocks base method.
+func (m *MockStore) UseLoginChallenge(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseLoginChallenge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseLoginChallenge indicates an expected call of UseLoginChallenge.
+func (mr *MockStoreMockRecorder) UseLoginChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseLoginChallenge", reflect.TypeOf((*MockStore)(nil).UseLoginChallenge), arg0, arg1)
+}
+
+// UsePasswordReset mocks base method.
+func (m *MockStore) UsePasswordReset(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePasswordReset indicates an expected call of UsePasswordReset.
+func (mr *MockStoreMockRecorder) UsePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordReset", reflect.TypeOf((*MockStore)(nil).UsePasswordReset), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockStore) UseTOTPStep(arg0 context.Context, arg1 db.UseTOTPStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockStoreMockRecorder) UseTOTPStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockStore)(nil).UseTOTPStep), arg0, arg1)
+}
+
+// UseUserPasswordResets mocks base method.
+func (m *MockStore) UseUserPasswordResets(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserPasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserPasswordResets indicates an expected call of UseUserPasswordResets.
+func (mr *MockStoreMockRecorder) UseUserPasswordResets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserPasswordResets", reflect.TypeOf((*MockStore)(nil).UseUserPasswordResets), arg0, arg1)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(arg0 context.Context, arg1 db.VerifyUserEmailParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), arg0, arg1)
 }
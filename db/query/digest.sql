@@ -0,0 +1,34 @@
+-- name: GetDigestSettings :one
+SELECT * FROM digest_settings
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertDigestSettings :one
+INSERT INTO digest_settings (
+    user_id,
+    frequency,
+    hour,
+    weekday
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (user_id) DO UPDATE
+SET
+    frequency = EXCLUDED.frequency,
+    hour = EXCLUDED.hour,
+    weekday = EXCLUDED.weekday,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListDigestSubscribers :many
+SELECT digest_settings.user_id, digest_settings.frequency, digest_settings.hour, digest_settings.weekday, digest_settings.last_sent_at, users.timezone
+FROM digest_settings
+JOIN users ON users.id = digest_settings.user_id
+WHERE digest_settings.frequency <> 'off'
+ORDER BY digest_settings.user_id;
+
+-- name: ClaimDigest :execrows
+UPDATE digest_settings
+SET
+    last_sent_at = sqlc.arg(sent_at),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+AND (last_sent_at IS NULL OR last_sent_at < sqlc.arg(scheduled_at));
@@ -0,0 +1,50 @@
+-- name: CreateNotification :execrows
+INSERT INTO notifications (
+    user_id,
+    type,
+    title,
+    body,
+    task_id,
+    dedupe_key
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(type),
+    sqlc.arg(title),
+    sqlc.arg(body),
+    sqlc.narg(task_id),
+    sqlc.narg(dedupe_key)
+) ON CONFLICT (user_id, dedupe_key) DO NOTHING;
+
+-- name: GetNotification :one
+SELECT * FROM notifications
+WHERE id = $1 LIMIT 1;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+    AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL)
+    AND (
+        sqlc.narg(before_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(before_created_at)::timestamptz, sqlc.narg(before_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: DeleteNotification :exec
+DELETE FROM notifications
+WHERE id = $1;
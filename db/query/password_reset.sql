@@ -0,0 +1,30 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetPasswordResetByTokenHash :one
+SELECT * FROM password_resets
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: CountPasswordResetsSince :one
+SELECT COUNT(*) FROM password_resets
+WHERE user_id = $1
+AND created_at > $2;
+
+-- name: UsePasswordReset :execrows
+UPDATE password_resets
+SET used_at = NOW()
+WHERE id = $1
+AND used_at IS NULL
+AND expires_at > NOW();
+
+-- name: UseUserPasswordResets :execrows
+UPDATE password_resets
+SET used_at = NOW()
+WHERE user_id = $1
+AND used_at IS NULL;
@@ -0,0 +1,56 @@
+-- name: CreateProject :one
+INSERT INTO projects (
+    user_id,
+    name,
+    color,
+    icon,
+    sort_order
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(name),
+    sqlc.arg(color),
+    sqlc.narg(icon),
+    COALESCE(sqlc.narg(sort_order)::integer, (SELECT COALESCE(MAX(sort_order) + 1, 0) FROM projects WHERE user_id = sqlc.arg(user_id)))
+) RETURNING *;
+
+-- name: GetProjectByID :one
+SELECT * FROM projects
+WHERE id = $1 LIMIT 1;
+
+-- name: ListProjectsByUser :many
+SELECT * FROM projects
+WHERE user_id = sqlc.arg(user_id)
+AND (sqlc.arg(include_archived)::boolean OR NOT archived)
+ORDER BY sort_order, name;
+
+-- name: UpdateProject :one
+UPDATE projects
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    color = COALESCE(sqlc.narg(color), color),
+    icon = CASE WHEN sqlc.arg(set_icon)::boolean THEN sqlc.narg(icon)::text ELSE icon END,
+    sort_order = COALESCE(sqlc.narg(sort_order)::integer, sort_order),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetProjectArchived :one
+UPDATE projects
+SET
+    archived = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1;
+
+-- name: GetProjectTaskCounts :many
+SELECT
+    project_id::uuid AS project_id,
+    COUNT(*) FILTER (WHERE status IN ('open', 'in_progress'))::bigint AS open_count,
+    COUNT(*) FILTER (WHERE status = 'done')::bigint AS done_count
+FROM tasks
+WHERE user_id = $1 AND project_id IS NOT NULL AND deleted_at IS NULL
+GROUP BY project_id;
@@ -0,0 +1,18 @@
+-- name: ListQuietHours :many
+SELECT * FROM quiet_hours
+WHERE user_id = $1
+ORDER BY weekday, start_minute;
+
+-- name: CreateQuietHour :one
+INSERT INTO quiet_hours (
+    user_id,
+    weekday,
+    start_minute,
+    end_minute
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: DeleteQuietHours :exec
+DELETE FROM quiet_hours
+WHERE user_id = $1;
@@ -0,0 +1,189 @@
+-- name: CreateTaskReminder :one
+INSERT INTO task_reminders (
+    task_id,
+    offset_seconds,
+    remind_at
+) VALUES (
+    sqlc.arg(task_id),
+    sqlc.narg(offset_seconds),
+    sqlc.arg(remind_at)
+) RETURNING *;
+
+-- name: GetTaskReminderByID :one
+SELECT * FROM task_reminders
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTaskReminders :many
+SELECT * FROM task_reminders
+WHERE task_id = $1
+ORDER BY remind_at, id;
+
+-- name: GetRemindersForTasks :many
+SELECT * FROM task_reminders
+WHERE task_id = ANY(sqlc.arg(task_ids)::uuid[])
+ORDER BY remind_at, id;
+
+-- name: DeleteTaskReminder :exec
+DELETE FROM task_reminders
+WHERE id = $1;
+
+-- name: DeleteTaskReminders :exec
+DELETE FROM task_reminders
+WHERE task_id = $1;
+
+-- name: RescheduleTaskReminders :exec
+UPDATE task_reminders
+SET
+    remind_at = CASE
+        WHEN offset_seconds IS NOT NULL THEN sqlc.arg(due_date)::timestamptz - offset_seconds * INTERVAL '1 second'
+        ELSE remind_at + sqlc.arg(shift_seconds)::bigint * INTERVAL '1 second'
+    END,
+    original_remind_at = CASE WHEN offset_seconds IS NOT NULL OR sqlc.arg(shift_seconds)::bigint <> 0 THEN NULL ELSE original_remind_at END,
+    snooze_count = CASE WHEN offset_seconds IS NOT NULL OR sqlc.arg(shift_seconds)::bigint <> 0 THEN 0 ELSE snooze_count END,
+    dismissed_at = CASE WHEN offset_seconds IS NOT NULL OR sqlc.arg(shift_seconds)::bigint <> 0 THEN NULL ELSE dismissed_at END
+WHERE task_id = sqlc.arg(task_id);
+
+-- name: GetLatestFiredReminder :one
+SELECT * FROM task_reminders
+WHERE task_id = sqlc.arg(task_id)
+    AND remind_at <= sqlc.arg(now)
+    AND dismissed_at IS NULL
+ORDER BY remind_at DESC
+LIMIT 1;
+
+-- name: SnoozeTaskReminder :one
+UPDATE task_reminders
+SET
+    original_remind_at = COALESCE(original_remind_at, remind_at),
+    remind_at = sqlc.arg(snoozed_until),
+    snooze_count = snooze_count + 1,
+    dismissed_at = NULL
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DismissTaskReminder :one
+UPDATE task_reminders
+SET dismissed_at = COALESCE(dismissed_at, NOW())
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateReminderSnooze :one
+INSERT INTO reminder_snoozes (
+    task_id,
+    reminder_id,
+    preset,
+    snoozed_from,
+    snoozed_until
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- name: ListReminderSnoozes :many
+SELECT * FROM reminder_snoozes
+WHERE task_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListDefaultReminders :many
+SELECT * FROM default_reminders
+WHERE user_id = $1
+ORDER BY offset_seconds DESC;
+
+-- name: CreateDefaultReminder :one
+INSERT INTO default_reminders (
+    user_id,
+    offset_seconds
+) VALUES (
+    $1,
+    $2
+) RETURNING *;
+
+-- name: DeleteDefaultReminders :exec
+DELETE FROM default_reminders
+WHERE user_id = $1;
+
+-- name: EnqueueDueReminders :execrows
+INSERT INTO reminder_deliveries (
+    task_id,
+    reminder_id,
+    remind_at,
+    next_attempt_at
+)
+SELECT task_reminders.task_id, task_reminders.id, task_reminders.remind_at, task_reminders.remind_at
+FROM task_reminders
+JOIN tasks ON tasks.id = task_reminders.task_id
+WHERE task_reminders.remind_at > sqlc.arg(since)
+    AND task_reminders.remind_at <= sqlc.arg(until)
+    AND task_reminders.dismissed_at IS NULL
+    AND tasks.deleted_at IS NULL
+    AND tasks.status NOT IN ('done', 'cancelled')
+ON CONFLICT (reminder_id, remind_at) DO NOTHING;
+
+-- name: ClaimDueReminders :many
+WITH due AS (
+    SELECT id FROM reminder_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= sqlc.arg(now)
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE reminder_deliveries
+SET
+    attempts = reminder_deliveries.attempts + 1,
+    next_attempt_at = sqlc.arg(lease_until),
+    updated_at = NOW()
+FROM due
+WHERE reminder_deliveries.id = due.id
+RETURNING reminder_deliveries.*;
+
+-- name: MarkReminderSent :one
+UPDATE reminder_deliveries
+SET
+    status = 'sent',
+    last_error = NULL,
+    delivered_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MarkReminderFailed :one
+UPDATE reminder_deliveries
+SET
+    status = sqlc.arg(status),
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeferReminderDelivery :one
+UPDATE reminder_deliveries
+SET
+    attempts = GREATEST(attempts - 1, 0),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelReminder :one
+UPDATE reminder_deliveries
+SET
+    status = 'cancelled',
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelPendingReminderDeliveries :execrows
+UPDATE reminder_deliveries
+SET
+    status = 'cancelled',
+    updated_at = NOW()
+WHERE reminder_id = $1 AND status = 'pending';
+
+-- name: ListReminderDeliveries :many
+SELECT * FROM reminder_deliveries
+WHERE task_id = sqlc.arg(task_id)
+ORDER BY remind_at DESC;
@@ -0,0 +1,37 @@
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (
+    id,
+    user_id,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: RevokeUserAccessTokens :many
+INSERT INTO revoked_tokens (id, user_id, expires_at)
+SELECT access_token_id, user_id, access_token_expires_at
+FROM sessions
+WHERE sessions.user_id = $1
+AND access_token_id IS NOT NULL
+AND access_token_expires_at > NOW()
+ON CONFLICT (id) DO NOTHING
+RETURNING id, expires_at;
+
+-- name: RevokeSessionFamilyAccessTokens :many
+INSERT INTO revoked_tokens (id, user_id, expires_at)
+SELECT access_token_id, user_id, access_token_expires_at
+FROM sessions
+WHERE sessions.family_id = $1
+AND access_token_id IS NOT NULL
+AND access_token_expires_at > NOW()
+ON CONFLICT (id) DO NOTHING
+RETURNING id, expires_at;
+
+-- name: ListRevokedTokens :many
+SELECT id, expires_at FROM revoked_tokens
+WHERE revoked_at > sqlc.arg(revoked_after)
+AND expires_at > NOW();
+
+-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < $1;
@@ -0,0 +1,45 @@
+-- name: CreateSession :one
+INSERT INTO sessions (
+    id,
+    user_id,
+    family_id,
+    user_agent,
+    client_ip,
+    expires_at,
+    access_token_id,
+    access_token_expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetSession :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: RotateSession :execrows
+UPDATE sessions
+SET rotated_at = NOW()
+WHERE id = $1
+AND rotated_at IS NULL
+AND NOT is_blocked;
+
+-- name: BlockSessionFamily :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1
+AND NOT is_blocked;
+
+-- name: BlockAccessTokenSession :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = (
+    SELECT family_id FROM sessions
+    WHERE access_token_id = $1
+)
+AND NOT is_blocked;
+
+-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_blocked = true
+WHERE user_id = $1
+AND NOT is_blocked;
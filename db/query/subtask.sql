@@ -0,0 +1,89 @@
+-- name: GetChildTasks :many
+SELECT * FROM tasks
+WHERE parent_id = sqlc.arg(parent_id)::uuid AND deleted_at IS NULL
+ORDER BY due_date, id;
+
+-- name: MoveTask :one
+UPDATE tasks
+SET
+    parent_id = sqlc.narg(parent_id),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: LockTaskTree :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(user_id)::text));
+
+-- name: GetTaskAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT tasks.id, tasks.parent_id, 1 AS depth FROM tasks WHERE tasks.id = $1
+    UNION ALL
+    SELECT tasks.id, tasks.parent_id, ancestors.depth + 1 FROM tasks
+    JOIN ancestors ON tasks.id = ancestors.parent_id
+    WHERE ancestors.depth < 100
+)
+SELECT id FROM ancestors
+ORDER BY depth;
+
+-- name: GetSubtreeHeight :one
+WITH RECURSIVE subtree AS (
+    SELECT tasks.id, 1 AS depth FROM tasks WHERE tasks.id = $1
+    UNION ALL
+    SELECT tasks.id, subtree.depth + 1 FROM tasks
+    JOIN subtree ON tasks.parent_id = subtree.id
+    WHERE subtree.depth < 100
+)
+SELECT MAX(depth)::integer AS height FROM subtree;
+
+-- name: CompleteTaskDescendants :many
+WITH RECURSIVE subtree AS (
+    SELECT tasks.id FROM tasks WHERE tasks.parent_id = sqlc.arg(id)::uuid
+    UNION ALL
+    SELECT tasks.id FROM tasks
+    JOIN subtree ON tasks.parent_id = subtree.id
+)
+UPDATE tasks
+SET
+    status = 'done',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL AND status IN ('open', 'in_progress')
+RETURNING *;
+
+-- name: TrashTaskDescendants :many
+WITH RECURSIVE subtree AS (
+    SELECT tasks.id FROM tasks WHERE tasks.parent_id = sqlc.arg(id)::uuid
+    UNION ALL
+    SELECT tasks.id FROM tasks
+    JOIN subtree ON tasks.parent_id = subtree.id
+)
+UPDATE tasks
+SET
+    deleted_at = sqlc.arg(deleted_at)::timestamptz,
+    updated_at = NOW()
+WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreTaskDescendants :many
+WITH RECURSIVE subtree AS (
+    SELECT tasks.id FROM tasks WHERE tasks.parent_id = sqlc.arg(id)::uuid
+    UNION ALL
+    SELECT tasks.id FROM tasks
+    JOIN subtree ON tasks.parent_id = subtree.id
+)
+UPDATE tasks
+SET
+    deleted_at = NULL,
+    updated_at = NOW()
+WHERE id IN (SELECT id FROM subtree) AND deleted_at = (SELECT deleted_at FROM tasks WHERE id = sqlc.arg(id)::uuid)
+RETURNING *;
+
+-- name: GetSubtaskRollups :many
+SELECT
+    parent_id::uuid AS parent_id,
+    COUNT(*)::bigint AS child_count,
+    COUNT(*) FILTER (WHERE status = 'done')::bigint AS done_count,
+    MIN(due_date)::timestamptz AS earliest_due_date
+FROM tasks
+WHERE parent_id = ANY(sqlc.arg(parent_ids)::uuid[]) AND deleted_at IS NULL
+GROUP BY parent_id;
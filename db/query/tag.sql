@@ -0,0 +1,61 @@
+-- name: CreateTag :one
+INSERT INTO tags (
+    user_id,
+    name,
+    color
+) VALUES (
+    $1,
+    $2,
+    $3
+) RETURNING *;
+
+-- name: GetTagByID :one
+SELECT * FROM tags
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTagsByUser :many
+SELECT * FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdateTag :one
+UPDATE tags
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    color = COALESCE(sqlc.narg(color), color),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1;
+
+-- name: UpsertTagByName :one
+INSERT INTO tags (
+    user_id,
+    name
+) VALUES (
+    $1,
+    $2
+) ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: AddTaskTag :exec
+INSERT INTO task_tags (
+    task_id,
+    tag_id
+) VALUES (
+    $1,
+    $2
+) ON CONFLICT DO NOTHING;
+
+-- name: RemoveTaskTags :exec
+DELETE FROM task_tags
+WHERE task_id = $1;
+
+-- name: GetTagsForTasks :many
+SELECT task_tags.task_id, tags.id, tags.name, tags.color FROM task_tags
+JOIN tags ON tags.id = task_tags.tag_id
+WHERE task_tags.task_id = ANY(sqlc.arg(task_ids)::uuid[])
+ORDER BY tags.name;
@@ -3,20 +3,120 @@ INSERT INTO tasks (
     title,
     description,
     due_date,
-    reminder_date,
-    user_id
+    user_id,
+    priority,
+    important,
+    project_id,
+    parent_id,
+    recurrence_rule,
+    recurrence_start,
+    recurrence_timezone,
+    recurrence_exdates,
+    break_through_quiet_hours
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 ) RETURNING *;
 
 -- name: GetTaskByID :one
 SELECT * FROM tasks 
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetTasksByUser :many
 SELECT * FROM tasks 
-WHERE user_id = $1;
+WHERE user_id = sqlc.arg(user_id) AND deleted_at IS NULL
+AND (sqlc.narg(status)::task_status IS NULL OR status = sqlc.narg(status)::task_status);
+
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET
+    title = COALESCE(sqlc.narg(title), title),
+    description = CASE WHEN sqlc.arg(set_description)::boolean THEN sqlc.narg(description)::text ELSE description END,
+    due_date = COALESCE(sqlc.narg(due_date), due_date),
+    status = COALESCE(sqlc.narg(status)::task_status, status),
+    completed_at = CASE
+        WHEN sqlc.narg(status)::task_status IS NULL THEN completed_at
+        WHEN sqlc.narg(status)::task_status = 'done' THEN COALESCE(completed_at, NOW())
+        ELSE NULL
+    END,
+    priority = COALESCE(sqlc.narg(priority)::task_priority, priority),
+    important = COALESCE(sqlc.narg(important)::boolean, important),
+    break_through_quiet_hours = COALESCE(sqlc.narg(break_through_quiet_hours)::boolean, break_through_quiet_hours),
+    project_id = CASE WHEN sqlc.arg(set_project_id)::boolean THEN sqlc.narg(project_id)::uuid ELSE project_id END,
+    recurrence_rule = CASE WHEN sqlc.arg(set_recurrence)::boolean THEN sqlc.narg(recurrence_rule)::text ELSE recurrence_rule END,
+    recurrence_start = CASE WHEN sqlc.arg(set_recurrence)::boolean THEN sqlc.narg(recurrence_start)::timestamptz ELSE recurrence_start END,
+    recurrence_timezone = CASE WHEN sqlc.arg(set_recurrence)::boolean THEN sqlc.narg(recurrence_timezone)::text ELSE recurrence_timezone END,
+    recurrence_exdates = CASE WHEN sqlc.arg(set_recurrence)::boolean THEN sqlc.narg(recurrence_exdates)::text ELSE recurrence_exdates END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetOpenTasksByUser :many
+SELECT * FROM tasks
+WHERE user_id = $1 AND deleted_at IS NULL AND status IN ('open', 'in_progress')
+ORDER BY due_date, id;
+
+-- name: CompleteTask :one
+UPDATE tasks
+SET
+    status = 'done',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: AdvanceRecurringTask :one
+UPDATE tasks
+SET
+    due_date = sqlc.arg(due_date),
+    status = 'open',
+    completed_at = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ReopenTask :one
+UPDATE tasks
+SET
+    status = 'open',
+    completed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TrashTask :one
+UPDATE tasks
+SET
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetTrashedTasksByUser :many
+SELECT * FROM tasks
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: RestoreTask :one
+UPDATE tasks
+SET
+    deleted_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeTrashedTasks :execrows
+DELETE FROM tasks
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(before)::timestamptz;
@@ -0,0 +1,114 @@
+-- name: UpsertTOTPCredential :one
+INSERT INTO totp_credentials (
+    user_id,
+    secret
+) VALUES (
+    $1, $2
+) ON CONFLICT (user_id) DO UPDATE
+SET
+    secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = NOW()
+WHERE totp_credentials.confirmed_at IS NULL
+RETURNING *;
+
+-- name: GetTOTPCredential :one
+SELECT * FROM totp_credentials
+WHERE user_id = $1 LIMIT 1;
+
+-- name: ConfirmTOTPCredential :execrows
+UPDATE totp_credentials
+SET
+    confirmed_at = NOW(),
+    last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id)
+AND confirmed_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+UPDATE totp_credentials
+SET last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id)
+AND confirmed_at IS NOT NULL
+AND last_used_step < sqlc.arg(step);
+
+-- name: ClaimTOTPAttempt :execrows
+UPDATE totp_credentials
+SET
+    failed_attempts = CASE
+        WHEN last_failed_at > sqlc.arg(window_start) THEN failed_attempts + 1
+        ELSE 1
+    END,
+    last_failed_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+AND (
+    failed_attempts < sqlc.arg(max_attempts)
+    OR last_failed_at IS NULL
+    OR last_failed_at <= sqlc.arg(window_start)
+);
+
+-- name: ResetTOTPAttempts :exec
+UPDATE totp_credentials
+SET
+    failed_attempts = 0,
+    last_failed_at = NULL
+WHERE user_id = $1;
+
+-- name: DeleteTOTPCredential :execrows
+DELETE FROM totp_credentials
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (
+    user_id,
+    code_hash
+) VALUES (
+    $1, $2
+);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+AND code_hash = $2
+AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes
+WHERE user_id = $1
+AND used_at IS NULL;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (
+    user_id,
+    token_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetLoginChallengeByTokenHash :one
+SELECT * FROM login_challenges
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: CountLoginChallengesSince :one
+SELECT COUNT(*) FROM login_challenges
+WHERE user_id = $1
+AND created_at > $2;
+
+-- name: ClaimLoginChallengeAttempt :execrows
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE id = sqlc.arg(id)
+AND attempts < sqlc.arg(max_attempts)
+AND used_at IS NULL
+AND expires_at > NOW();
+
+-- name: UseLoginChallenge :execrows
+UPDATE login_challenges
+SET used_at = NOW()
+WHERE id = $1
+AND used_at IS NULL;
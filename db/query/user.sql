@@ -19,3 +19,53 @@ WHERE email = $1 LIMIT 1;
 -- name: GetUserByID :one
 SELECT * FROM users 
 WHERE id = $1 LIMIT 1;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+    first_name = COALESCE(sqlc.narg(first_name), first_name),
+    last_name = COALESCE(sqlc.narg(last_name), last_name),
+    timezone = COALESCE(sqlc.narg(timezone), timezone),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetDoNotDisturb :one
+UPDATE users
+SET
+    dnd_until = sqlc.narg(dnd_until),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET
+    password = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET
+    email = $1,
+    email_verified_at = NULL,
+    verification_sent_at = NULL,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE id = $1
+AND email = $2
+RETURNING *;
+
+-- name: ClaimVerificationEmail :execrows
+UPDATE users
+SET verification_sent_at = NOW()
+WHERE id = sqlc.arg(id)
+AND email_verified_at IS NULL
+AND (verification_sent_at IS NULL OR verification_sent_at < sqlc.arg(sent_before));
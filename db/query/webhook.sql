@@ -0,0 +1,142 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    user_id,
+    url,
+    secret,
+    event_types
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(url),
+    sqlc.arg(secret),
+    sqlc.arg(event_types)::text[]
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET
+    url = COALESCE(sqlc.narg(url), url),
+    event_types = CASE WHEN sqlc.arg(set_event_types)::boolean THEN sqlc.arg(event_types)::text[] ELSE event_types END,
+    active = COALESCE(sqlc.narg(active), active),
+    failure_count = CASE WHEN sqlc.narg(active)::boolean THEN 0 ELSE failure_count END,
+    disabled_at = CASE
+        WHEN sqlc.narg(active)::boolean THEN NULL
+        WHEN NOT sqlc.narg(active)::boolean AND active THEN NOW()
+        ELSE disabled_at
+    END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: RecordWebhookSuccess :exec
+UPDATE webhook_endpoints
+SET failure_count = 0
+WHERE id = $1 AND failure_count > 0;
+
+-- name: RecordWebhookFailure :one
+UPDATE webhook_endpoints
+SET
+    failure_count = failure_count + 1,
+    active = active AND failure_count + 1 < sqlc.arg(max_failures)::integer,
+    disabled_at = CASE WHEN active AND failure_count + 1 >= sqlc.arg(max_failures)::integer THEN NOW() ELSE disabled_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: EnqueueWebhookEvent :execrows
+INSERT INTO webhook_deliveries (
+    endpoint_id,
+    event_id,
+    event_type,
+    payload
+)
+SELECT id, sqlc.arg(event_id), sqlc.arg(event_type), sqlc.arg(payload)
+FROM webhook_endpoints
+WHERE user_id = sqlc.arg(user_id)
+    AND active
+    AND sqlc.arg(event_type) = ANY(event_types)
+ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+    endpoint_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    sqlc.arg(endpoint_id),
+    sqlc.arg(event_id),
+    sqlc.arg(event_type),
+    sqlc.arg(payload)
+) RETURNING *;
+
+-- name: ClaimDueWebhookDeliveries :many
+WITH due AS (
+    SELECT webhook_deliveries.id FROM webhook_deliveries
+    JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.endpoint_id
+    WHERE webhook_deliveries.status = 'pending'
+        AND webhook_deliveries.next_attempt_at <= sqlc.arg(now)
+        AND webhook_endpoints.active
+    ORDER BY webhook_deliveries.next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE OF webhook_deliveries SKIP LOCKED
+)
+UPDATE webhook_deliveries
+SET
+    attempts = webhook_deliveries.attempts + 1,
+    next_attempt_at = sqlc.arg(lease_until),
+    updated_at = NOW()
+FROM due
+WHERE webhook_deliveries.id = due.id
+RETURNING webhook_deliveries.*;
+
+-- name: MarkWebhookDeliverySent :one
+UPDATE webhook_deliveries
+SET
+    status = 'sent',
+    response_status = sqlc.arg(response_status),
+    last_error = NULL,
+    delivered_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MarkWebhookDeliveryFailed :one
+UPDATE webhook_deliveries
+SET
+    status = sqlc.arg(status),
+    response_status = sqlc.narg(response_status),
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelWebhookDeliveries :execrows
+UPDATE webhook_deliveries
+SET
+    status = 'cancelled',
+    updated_at = NOW()
+WHERE endpoint_id = $1 AND status = 'pending';
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = sqlc.arg(endpoint_id)
+    AND (
+        sqlc.narg(before_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(before_created_at)::timestamptz, sqlc.narg(before_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
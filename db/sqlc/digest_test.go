@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestSettings(t *testing.T) {
+	user := createRandomUser(t)
+
+	settings, err := testQueries.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID: user.ID,
+		Frequency: DigestFrequencyDaily,
+		Hour: 7,
+		Weekday: 1,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, DigestFrequencyDaily, settings.Frequency)
+	require.False(t, settings.LastSentAt.Valid)
+
+	settings, err = testQueries.UpsertDigestSettings(context.Background(), UpsertDigestSettingsParams{
+		UserID: user.ID,
+		Frequency: DigestFrequencyWeekly,
+		Hour: 9,
+		Weekday: 5,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, DigestFrequencyWeekly, settings.Frequency)
+	require.Equal(t, int32(9), settings.Hour)
+
+	subscribers, err := testQueries.ListDigestSubscribers(context.Background())
+	require.NoError(t, err)
+
+	found := false
+	for _, subscriber := range subscribers {
+		if subscriber.UserID == user.ID {
+			found = true
+			require.Equal(t, user.Timezone, subscriber.Timezone)
+		}
+	}
+	require.True(t, found)
+
+	scheduledAt := time.Now().Truncate(time.Hour)
+
+	arg := ClaimDigestParams {
+		UserID: user.ID,
+		SentAt: time.Now(),
+		ScheduledAt: scheduledAt,
+	}
+
+	claimed, err := testQueries.ClaimDigest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), claimed)
+
+	//The same digest can only be claimed once
+	claimed, err = testQueries.ClaimDigest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, claimed)
+}
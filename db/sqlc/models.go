@@ -6,28 +6,451 @@ package db
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Task struct {
-	ID           uuid.UUID      `json:"id"`
-	Title        string         `json:"title"`
-	DueDate      time.Time      `json:"due_date"`
-	ReminderDate sql.NullTime   `json:"reminder_date"`
-	Description  sql.NullString `json:"description"`
-	UserID       uuid.UUID      `json:"user_id"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSent      DeliveryStatus = "sent"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+	DeliveryStatusCancelled DeliveryStatus = "cancelled"
+)
+
+func (e *DeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DeliveryStatus(s)
+	case string:
+		*e = DeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DeliveryStatus: %T", src)
+	}
+	return nil
 }
 
-type User struct {
+type NullDeliveryStatus struct {
+	DeliveryStatus DeliveryStatus `json:"delivery_status"`
+	Valid          bool           `json:"valid"` // Valid is true if DeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DeliveryStatus), nil
+}
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyOff    DigestFrequency = "off"
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+)
+
+func (e *DigestFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DigestFrequency(s)
+	case string:
+		*e = DigestFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DigestFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullDigestFrequency struct {
+	DigestFrequency DigestFrequency `json:"digest_frequency"`
+	Valid           bool            `json:"valid"` // Valid is true if DigestFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDigestFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.DigestFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DigestFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDigestFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DigestFrequency), nil
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeReminder NotificationType = "reminder"
+	NotificationTypeSecurity NotificationType = "security"
+	NotificationTypeDigest   NotificationType = "digest"
+)
+
+func (e *NotificationType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationType(s)
+	case string:
+		*e = NotificationType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationType: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationType struct {
+	NotificationType NotificationType `json:"notification_type"`
+	Valid            bool             `json:"valid"` // Valid is true if NotificationType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationType) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationType), nil
+}
+
+type TaskPriority string
+
+const (
+	TaskPriorityNone   TaskPriority = "none"
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+func (e *TaskPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TaskPriority(s)
+	case string:
+		*e = TaskPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TaskPriority: %T", src)
+	}
+	return nil
+}
+
+type NullTaskPriority struct {
+	TaskPriority TaskPriority `json:"task_priority"`
+	Valid        bool         `json:"valid"` // Valid is true if TaskPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTaskPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.TaskPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TaskPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTaskPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TaskPriority), nil
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusOpen       TaskStatus = "open"
+	TaskStatusInProgress TaskStatus = "in_progress"
+	TaskStatusDone       TaskStatus = "done"
+	TaskStatusCancelled  TaskStatus = "cancelled"
+)
+
+func (e *TaskStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TaskStatus(s)
+	case string:
+		*e = TaskStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TaskStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTaskStatus struct {
+	TaskStatus TaskStatus `json:"task_status"`
+	Valid      bool       `json:"valid"` // Valid is true if TaskStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTaskStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TaskStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TaskStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTaskStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TaskStatus), nil
+}
+
+type DefaultReminder struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	OffsetSeconds int32     `json:"offset_seconds"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type DigestSetting struct {
+	UserID     uuid.UUID       `json:"user_id"`
+	Frequency  DigestFrequency `json:"frequency"`
+	Hour       int32           `json:"hour"`
+	Weekday    int32           `json:"weekday"`
+	LastSentAt sql.NullTime    `json:"last_sent_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+type LoginChallenge struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	Attempts  int32        `json:"attempts"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Type      NotificationType `json:"type"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	TaskID    uuid.NullUUID    `json:"task_id"`
+	DedupeKey sql.NullString   `json:"dedupe_key"`
+	ReadAt    sql.NullTime     `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type Project struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Name      string         `json:"name"`
+	Color     string         `json:"color"`
+	Icon      sql.NullString `json:"icon"`
+	Archived  bool           `json:"archived"`
+	SortOrder int32          `json:"sort_order"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type QuietHour struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Weekday     int32     `json:"weekday"`
+	StartMinute int32     `json:"start_minute"`
+	EndMinute   int32     `json:"end_minute"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ReminderDelivery struct {
+	ID            uuid.UUID      `json:"id"`
+	TaskID        uuid.UUID      `json:"task_id"`
+	RemindAt      time.Time      `json:"remind_at"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int32          `json:"attempts"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	LastError     sql.NullString `json:"last_error"`
+	DeliveredAt   sql.NullTime   `json:"delivered_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	ReminderID    uuid.UUID      `json:"reminder_id"`
+}
+
+type ReminderSnooze struct {
+	ID           uuid.UUID     `json:"id"`
+	TaskID       uuid.UUID     `json:"task_id"`
+	ReminderID   uuid.NullUUID `json:"reminder_id"`
+	Preset       string        `json:"preset"`
+	SnoozedFrom  time.Time     `json:"snoozed_from"`
+	SnoozedUntil time.Time     `json:"snoozed_until"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type RevokedToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type Session struct {
+	ID                   uuid.UUID     `json:"id"`
+	UserID               uuid.UUID     `json:"user_id"`
+	FamilyID             uuid.UUID     `json:"family_id"`
+	UserAgent            string        `json:"user_agent"`
+	ClientIp             string        `json:"client_ip"`
+	IsBlocked            bool          `json:"is_blocked"`
+	RotatedAt            sql.NullTime  `json:"rotated_at"`
+	ExpiresAt            time.Time     `json:"expires_at"`
+	CreatedAt            time.Time     `json:"created_at"`
+	AccessTokenID        uuid.NullUUID `json:"access_token_id"`
+	AccessTokenExpiresAt sql.NullTime  `json:"access_token_expires_at"`
+}
+
+type Tag struct {
 	ID        uuid.UUID `json:"id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type Task struct {
+	ID                     uuid.UUID      `json:"id"`
+	Title                  string         `json:"title"`
+	DueDate                time.Time      `json:"due_date"`
+	Description            sql.NullString `json:"description"`
+	UserID                 uuid.UUID      `json:"user_id"`
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+	DeletedAt              sql.NullTime   `json:"deleted_at"`
+	Status                 TaskStatus     `json:"status"`
+	CompletedAt            sql.NullTime   `json:"completed_at"`
+	Priority               TaskPriority   `json:"priority"`
+	Important              bool           `json:"important"`
+	ProjectID              uuid.NullUUID  `json:"project_id"`
+	ParentID               uuid.NullUUID  `json:"parent_id"`
+	RecurrenceRule         sql.NullString `json:"recurrence_rule"`
+	RecurrenceStart        sql.NullTime   `json:"recurrence_start"`
+	RecurrenceTimezone     sql.NullString `json:"recurrence_timezone"`
+	RecurrenceExdates      sql.NullString `json:"recurrence_exdates"`
+	BreakThroughQuietHours bool           `json:"break_through_quiet_hours"`
+}
+
+type TaskReminder struct {
+	ID               uuid.UUID     `json:"id"`
+	TaskID           uuid.UUID     `json:"task_id"`
+	OffsetSeconds    sql.NullInt32 `json:"offset_seconds"`
+	RemindAt         time.Time     `json:"remind_at"`
+	CreatedAt        time.Time     `json:"created_at"`
+	OriginalRemindAt sql.NullTime  `json:"original_remind_at"`
+	SnoozeCount      int32         `json:"snooze_count"`
+	DismissedAt      sql.NullTime  `json:"dismissed_at"`
+}
+
+type TaskTag struct {
+	TaskID uuid.UUID `json:"task_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+type TotpCredential struct {
+	UserID         uuid.UUID    `json:"user_id"`
+	Secret         string       `json:"secret"`
+	ConfirmedAt    sql.NullTime `json:"confirmed_at"`
+	LastUsedStep   int64        `json:"last_used_step"`
+	CreatedAt      time.Time    `json:"created_at"`
+	FailedAttempts int32        `json:"failed_attempts"`
+	LastFailedAt   sql.NullTime `json:"last_failed_at"`
+}
+
+type User struct {
+	ID                 uuid.UUID    `json:"id"`
+	FirstName          string       `json:"first_name"`
+	LastName           string       `json:"last_name"`
+	Email              string       `json:"email"`
+	Password           string       `json:"password"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+	Timezone           string       `json:"timezone"`
+	DndUntil           sql.NullTime `json:"dnd_until"`
+	EmailVerifiedAt    sql.NullTime `json:"email_verified_at"`
+	VerificationSentAt sql.NullTime `json:"verification_sent_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus sql.NullInt32   `json:"response_status"`
+	LastError      sql.NullString  `json:"last_error"`
+	DeliveredAt    sql.NullTime    `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type WebhookEndpoint struct {
+	ID           uuid.UUID    `json:"id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	Url          string       `json:"url"`
+	Secret       string       `json:"secret"`
+	EventTypes   []string     `json:"event_types"`
+	Active       bool         `json:"active"`
+	FailureCount int32        `json:"failure_count"`
+	DisabledAt   sql.NullTime `json:"disabled_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationInbox(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateNotificationParams {
+		UserID: user.ID,
+		Type: NotificationTypeReminder,
+		Title: util.RandomString(8),
+		DedupeKey: sql.NullString{String: util.RandomString(12), Valid: true},
+	}
+
+	created, err := testQueries.CreateNotification(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created)
+
+	//A notification with the same dedupe key is only written once
+	created, err = testQueries.CreateNotification(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, created)
+
+	arg.DedupeKey = sql.NullString{}
+	arg.Type = NotificationTypeSecurity
+
+	_, err = testQueries.CreateNotification(context.Background(), arg)
+	require.NoError(t, err)
+
+	unread, err := testQueries.CountUnreadNotifications(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), unread)
+
+	page, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{UserID: user.ID, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+
+	next, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+		UserID: user.ID,
+		BeforeCreatedAt: sql.NullTime{Time: page[0].CreatedAt, Valid: true},
+		BeforeID: uuid.NullUUID{UUID: page[0].ID, Valid: true},
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	require.NotEqual(t, page[0].ID, next[0].ID)
+
+	read, err := testQueries.MarkNotificationRead(context.Background(), page[0].ID)
+	require.NoError(t, err)
+	require.True(t, read.ReadAt.Valid)
+
+	unreadOnly, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{UserID: user.ID, UnreadOnly: true, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, unreadOnly, 1)
+	require.Equal(t, next[0].ID, unreadOnly[0].ID)
+
+	marked, err := testQueries.MarkAllNotificationsRead(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), marked)
+
+	unread, err = testQueries.CountUnreadNotifications(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Zero(t, unread)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordReset(t *testing.T, user User, expiresAt time.Time) PasswordReset {
+	arg := CreatePasswordResetParams {
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(util.RandomString(32)),
+		ExpiresAt: expiresAt,
+	}
+
+	reset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.TokenHash, reset.TokenHash)
+	require.False(t, reset.UsedAt.Valid)
+
+	return reset
+}
+
+func TestCountPasswordResetsSince(t *testing.T) {
+	user := createRandomUser(t)
+
+	createRandomPasswordReset(t, user, time.Now().Add(time.Hour))
+	createRandomPasswordReset(t, user, time.Now().Add(time.Hour))
+
+	count, err := testQueries.CountPasswordResetsSince(context.Background(), CountPasswordResetsSinceParams {
+		UserID: user.ID,
+		CreatedAt: time.Now().Add(-time.Hour),
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	reset := createRandomPasswordReset(t, user, time.Now().Add(time.Hour))
+	other := createRandomPasswordReset(t, user, time.Now().Add(time.Hour))
+	createRandomSession(t, user, reset.ID)
+
+	arg := ResetPasswordTxParams {
+		ResetID: reset.ID,
+		UserID: user.ID,
+		Password: util.RandomString(60),
+	}
+
+	result, err := store.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Password, result.User.Password)
+	require.Equal(t, int64(1), result.SignedOutSessions)
+
+	//A token works once, and the other tokens of the user stop working too
+
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrPasswordResetInvalid)
+
+	other, err = testQueries.GetPasswordResetByTokenHash(context.Background(), other.TokenHash)
+	require.NoError(t, err)
+	require.True(t, other.UsedAt.Valid)
+}
+
+func TestResetPasswordTxExpired(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	reset := createRandomPasswordReset(t, user, time.Now().Add(-time.Minute))
+
+	_, err := store.ResetPasswordTx(context.Background(), ResetPasswordTxParams {
+		ResetID: reset.ID,
+		UserID: user.ID,
+		Password: util.RandomString(60),
+	})
+	require.ErrorIs(t, err, ErrPasswordResetInvalid)
+
+	unchanged, err := testQueries.GetUserByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Password, unchanged.Password)
+}
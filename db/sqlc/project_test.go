@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomProject(t *testing.T, user User) Project {
+	arg := CreateProjectParams {
+		UserID: user.ID,
+		Name: util.RandomString(6),
+		Color: "#3366ff",
+	}
+
+	project, err := testQueries.CreateProject(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.Equal(t, arg.UserID, project.UserID)
+	require.Equal(t, arg.Name, project.Name)
+	require.Equal(t, arg.Color, project.Color)
+	require.False(t, project.Archived)
+	require.NotZero(t, project.ID)
+
+	return project
+}
+
+func TestCreateProjectSortOrder(t *testing.T) {
+	user := createRandomUser(t)
+
+	first := createRandomProject(t, user)
+	second := createRandomProject(t, user)
+
+	require.Equal(t, int32(0), first.SortOrder)
+	require.Equal(t, int32(1), second.SortOrder)
+}
+
+func TestListProjectsByUserArchived(t *testing.T) {
+	user := createRandomUser(t)
+
+	active := createRandomProject(t, user)
+	archived := createRandomProject(t, user)
+
+	_, err := testQueries.SetProjectArchived(context.Background(), SetProjectArchivedParams{ID: archived.ID, Archived: true})
+
+	require.NoError(t, err)
+
+	projects, err := testQueries.ListProjectsByUser(context.Background(), ListProjectsByUserParams{UserID: user.ID})
+
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, active.ID, projects[0].ID)
+
+	projects, err = testQueries.ListProjectsByUser(context.Background(), ListProjectsByUserParams{UserID: user.ID, IncludeArchived: true})
+
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+}
+
+func TestGetProjectTaskCounts(t *testing.T) {
+	user := createRandomUser(t)
+
+	project := createRandomProject(t, user)
+
+	for i := 0; i < 3; i++ {
+		task := createRandomTask(t, user)
+
+		task, err := testQueries.UpdateTask(context.Background(), UpdateTaskParams{
+			ID: task.ID,
+			SetProjectID: true,
+			ProjectID: uuid.NullUUID{UUID: project.ID, Valid: true},
+		})
+
+		require.NoError(t, err)
+
+		if i == 0 {
+			_, err = testQueries.CompleteTask(context.Background(), task.ID)
+			require.NoError(t, err)
+		}
+	}
+
+	counts, err := testQueries.GetProjectTaskCounts(context.Background(), user.ID)
+
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	require.Equal(t, project.ID, counts[0].ProjectID)
+	require.Equal(t, int64(2), counts[0].OpenCount)
+	require.Equal(t, int64(1), counts[0].DoneCount)
+}
+
+func TestDeleteProjectKeepsTasks(t *testing.T) {
+	user := createRandomUser(t)
+
+	project := createRandomProject(t, user)
+
+	task, err := testQueries.CreateTask(context.Background(), CreateTaskParams{
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: util.RandomDate(),
+		Priority: TaskPriorityNone,
+		ProjectID: uuid.NullUUID{UUID: project.ID, Valid: true},
+	})
+
+	require.NoError(t, err)
+
+	require.NoError(t, testQueries.DeleteProject(context.Background(), project.ID))
+
+	task, err = testQueries.GetTaskByID(context.Background(), task.ID)
+
+	require.NoError(t, err)
+	require.False(t, task.ProjectID.Valid)
+
+	_, err = testQueries.GetProjectByID(context.Background(), project.ID)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -6,17 +6,141 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddTaskTag(ctx context.Context, arg AddTaskTagParams) error
+	AdvanceRecurringTask(ctx context.Context, arg AdvanceRecurringTaskParams) (Task, error)
+	BlockAccessTokenSession(ctx context.Context, accessTokenID uuid.NullUUID) (int64, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	BlockUserSessions(ctx context.Context, userID uuid.UUID) (int64, error)
+	CancelPendingReminderDeliveries(ctx context.Context, reminderID uuid.UUID) (int64, error)
+	CancelReminder(ctx context.Context, id uuid.UUID) (ReminderDelivery, error)
+	CancelWebhookDeliveries(ctx context.Context, endpointID uuid.UUID) (int64, error)
+	ClaimDigest(ctx context.Context, arg ClaimDigestParams) (int64, error)
+	ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ReminderDelivery, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimLoginChallengeAttempt(ctx context.Context, arg ClaimLoginChallengeAttemptParams) (int64, error)
+	ClaimTOTPAttempt(ctx context.Context, arg ClaimTOTPAttemptParams) (int64, error)
+	ClaimVerificationEmail(ctx context.Context, arg ClaimVerificationEmailParams) (int64, error)
+	CompleteTask(ctx context.Context, id uuid.UUID) (Task, error)
+	CompleteTaskDescendants(ctx context.Context, id uuid.UUID) ([]Task, error)
+	ConfirmTOTPCredential(ctx context.Context, arg ConfirmTOTPCredentialParams) (int64, error)
+	CountLoginChallengesSince(ctx context.Context, arg CountLoginChallengesSinceParams) (int64, error)
+	CountPasswordResetsSince(ctx context.Context, arg CountPasswordResetsSinceParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateDefaultReminder(ctx context.Context, arg CreateDefaultReminderParams) (DefaultReminder, error)
+	CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) (LoginChallenge, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (int64, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateQuietHour(ctx context.Context, arg CreateQuietHourParams) (QuietHour, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateReminderSnooze(ctx context.Context, arg CreateReminderSnoozeParams) (ReminderSnooze, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	CreateTaskReminder(ctx context.Context, arg CreateTaskReminderParams) (TaskReminder, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeferReminderDelivery(ctx context.Context, arg DeferReminderDeliveryParams) (ReminderDelivery, error)
+	DeleteDefaultReminders(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredRevokedTokens(ctx context.Context, expiresAt time.Time) (int64, error)
+	DeleteNotification(ctx context.Context, id uuid.UUID) error
+	DeleteProject(ctx context.Context, id uuid.UUID) error
+	DeleteQuietHours(ctx context.Context, userID uuid.UUID) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DeleteTOTPCredential(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	DeleteTaskReminder(ctx context.Context, id uuid.UUID) error
+	DeleteTaskReminders(ctx context.Context, taskID uuid.UUID) error
+	DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) error
+	DismissTaskReminder(ctx context.Context, id uuid.UUID) (TaskReminder, error)
+	EnqueueDueReminders(ctx context.Context, arg EnqueueDueRemindersParams) (int64, error)
+	EnqueueWebhookEvent(ctx context.Context, arg EnqueueWebhookEventParams) (int64, error)
+	GetChildTasks(ctx context.Context, parentID uuid.UUID) ([]Task, error)
+	GetDigestSettings(ctx context.Context, userID uuid.UUID) (DigestSetting, error)
+	GetLatestFiredReminder(ctx context.Context, arg GetLatestFiredReminderParams) (TaskReminder, error)
+	GetLoginChallengeByTokenHash(ctx context.Context, tokenHash string) (LoginChallenge, error)
+	GetNotification(ctx context.Context, id uuid.UUID) (Notification, error)
+	GetOpenTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error)
+	GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (PasswordReset, error)
+	GetProjectByID(ctx context.Context, id uuid.UUID) (Project, error)
+	GetProjectTaskCounts(ctx context.Context, userID uuid.UUID) ([]GetProjectTaskCountsRow, error)
+	GetRemindersForTasks(ctx context.Context, taskIds []uuid.UUID) ([]TaskReminder, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSubtaskRollups(ctx context.Context, parentIds []uuid.UUID) ([]GetSubtaskRollupsRow, error)
+	GetSubtreeHeight(ctx context.Context, id uuid.UUID) (int32, error)
+	GetTOTPCredential(ctx context.Context, userID uuid.UUID) (TotpCredential, error)
+	GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error)
+	GetTagsForTasks(ctx context.Context, taskIds []uuid.UUID) ([]GetTagsForTasksRow, error)
+	GetTaskAncestors(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
 	GetTaskByID(ctx context.Context, id uuid.UUID) (Task, error)
-	GetTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error)
+	GetTaskReminderByID(ctx context.Context, id uuid.UUID) (TaskReminder, error)
+	GetTasksByUser(ctx context.Context, arg GetTasksByUserParams) ([]Task, error)
+	GetTrashedTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error)
 	GetUser(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetWebhookEndpoint(ctx context.Context, id uuid.UUID) (WebhookEndpoint, error)
+	ListDefaultReminders(ctx context.Context, userID uuid.UUID) ([]DefaultReminder, error)
+	ListDigestSubscribers(ctx context.Context) ([]ListDigestSubscribersRow, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListProjectsByUser(ctx context.Context, arg ListProjectsByUserParams) ([]Project, error)
+	ListQuietHours(ctx context.Context, userID uuid.UUID) ([]QuietHour, error)
+	ListReminderDeliveries(ctx context.Context, taskID uuid.UUID) ([]ReminderDelivery, error)
+	ListReminderSnoozes(ctx context.Context, taskID uuid.UUID) ([]ReminderSnooze, error)
+	ListRevokedTokens(ctx context.Context, revokedAfter time.Time) ([]ListRevokedTokensRow, error)
+	ListTagsByUser(ctx context.Context, userID uuid.UUID) ([]Tag, error)
+	ListTaskReminders(ctx context.Context, taskID uuid.UUID) ([]TaskReminder, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]WebhookEndpoint, error)
+	LockTaskTree(ctx context.Context, userID uuid.UUID) error
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkNotificationRead(ctx context.Context, id uuid.UUID) (Notification, error)
+	MarkReminderFailed(ctx context.Context, arg MarkReminderFailedParams) (ReminderDelivery, error)
+	MarkReminderSent(ctx context.Context, id uuid.UUID) (ReminderDelivery, error)
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) (WebhookDelivery, error)
+	MarkWebhookDeliverySent(ctx context.Context, arg MarkWebhookDeliverySentParams) (WebhookDelivery, error)
+	MoveTask(ctx context.Context, arg MoveTaskParams) (Task, error)
+	PurgeTrashedTasks(ctx context.Context, before time.Time) (int64, error)
+	RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (WebhookEndpoint, error)
+	RecordWebhookSuccess(ctx context.Context, id uuid.UUID) error
+	RemoveTaskTags(ctx context.Context, taskID uuid.UUID) error
+	ReopenTask(ctx context.Context, id uuid.UUID) (Task, error)
+	RescheduleTaskReminders(ctx context.Context, arg RescheduleTaskRemindersParams) error
+	ResetTOTPAttempts(ctx context.Context, userID uuid.UUID) error
+	RestoreTask(ctx context.Context, arg RestoreTaskParams) (Task, error)
+	RestoreTaskDescendants(ctx context.Context, id uuid.UUID) ([]Task, error)
+	RevokeSessionFamilyAccessTokens(ctx context.Context, familyID uuid.UUID) ([]RevokeSessionFamilyAccessTokensRow, error)
+	RevokeToken(ctx context.Context, arg RevokeTokenParams) error
+	RevokeUserAccessTokens(ctx context.Context, userID uuid.UUID) ([]RevokeUserAccessTokensRow, error)
+	RotateSession(ctx context.Context, id uuid.UUID) (int64, error)
+	SetDoNotDisturb(ctx context.Context, arg SetDoNotDisturbParams) (User, error)
+	SetProjectArchived(ctx context.Context, arg SetProjectArchivedParams) (Project, error)
+	SnoozeTaskReminder(ctx context.Context, arg SnoozeTaskReminderParams) (TaskReminder, error)
+	TrashTask(ctx context.Context, arg TrashTaskParams) (Task, error)
+	TrashTaskDescendants(ctx context.Context, arg TrashTaskDescendantsParams) ([]Task, error)
+	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
+	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (WebhookEndpoint, error)
+	UpsertDigestSettings(ctx context.Context, arg UpsertDigestSettingsParams) (DigestSetting, error)
+	UpsertTOTPCredential(ctx context.Context, arg UpsertTOTPCredentialParams) (TotpCredential, error)
+	UpsertTagByName(ctx context.Context, arg UpsertTagByNameParams) (Tag, error)
+	UseLoginChallenge(ctx context.Context, id uuid.UUID) (int64, error)
+	UsePasswordReset(ctx context.Context, id uuid.UUID) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
+	UseUserPasswordResets(ctx context.Context, userID uuid.UUID) (int64, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceQuietHoursTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	windows, err := store.ReplaceQuietHoursTx(context.Background(), ReplaceQuietHoursTxParams{
+		UserID: user.ID,
+		Windows: []QuietHour{
+			{Weekday: 5, StartMinute: 23 * 60, EndMinute: 9 * 60},
+			{Weekday: 1, StartMinute: 22 * 60, EndMinute: 7 * 60},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	require.Equal(t, int32(1), windows[0].Weekday)
+	require.Equal(t, int32(5), windows[1].Weekday)
+
+	windows, err = store.ReplaceQuietHoursTx(context.Background(), ReplaceQuietHoursTxParams{UserID: user.ID})
+
+	require.NoError(t, err)
+	require.Empty(t, windows)
+}
+
+func TestSetDoNotDisturb(t *testing.T) {
+	user := createRandomUser(t)
+	require.False(t, user.DndUntil.Valid)
+
+	until := time.Now().Add(time.Hour)
+
+	user2, err := testQueries.SetDoNotDisturb(context.Background(), SetDoNotDisturbParams{
+		ID: user.ID,
+		DndUntil: sql.NullTime{Time: until, Valid: true},
+	})
+
+	require.NoError(t, err)
+	require.WithinDuration(t, until, user2.DndUntil.Time, time.Second)
+
+	user2, err = testQueries.SetDoNotDisturb(context.Background(), SetDoNotDisturbParams{ID: user.ID})
+
+	require.NoError(t, err)
+	require.False(t, user2.DndUntil.Valid)
+}
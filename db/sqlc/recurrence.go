@@ -0,0 +1,27 @@
+package db
+
+import (
+	"m1thrandir225/your_time/util"
+)
+
+// Recurrence returns how the task repeats. It is only meaningful when
+// RecurrenceRule is valid.
+func (task Task) Recurrence() (util.Recurrence, error) {
+	exdates, err := util.ParseExDates(task.RecurrenceExdates.String)
+	if err != nil {
+		return util.Recurrence{}, err
+	}
+
+	recurrence := util.Recurrence {
+		Rule: task.RecurrenceRule.String,
+		Start: task.DueDate,
+		Timezone: task.RecurrenceTimezone.String,
+		ExDates: exdates,
+	}
+
+	if task.RecurrenceStart.Valid {
+		recurrence.Start = task.RecurrenceStart.Time
+	}
+
+	return recurrence, nil
+}
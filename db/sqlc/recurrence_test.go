@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteTaskTxAdvancesRecurringTask(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	dueDate := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+
+	result, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: user.ID,
+			Title: util.RandomString(6),
+			DueDate: dueDate,
+			Priority: TaskPriorityNone,
+			RecurrenceRule: sql.NullString{String: "FREQ=WEEKLY;COUNT=2", Valid: true},
+			RecurrenceStart: sql.NullTime{Time: dueDate, Valid: true},
+			RecurrenceTimezone: sql.NullString{String: "UTC", Valid: true},
+		},
+		Reminders: []ReminderSpec{
+			{OffsetSeconds: sql.NullInt32{Int32: 3600, Valid: true}},
+			{RemindAt: dueDate.Add(-24 * time.Hour)},
+		},
+	})
+
+	require.NoError(t, err)
+
+	completed, err := store.CompleteTaskTx(context.Background(), result.Task.ID)
+
+	require.NoError(t, err)
+	require.Empty(t, completed.Descendants)
+
+	advanced := completed.Task
+
+	require.Equal(t, TaskStatusOpen, advanced.Status)
+	require.False(t, advanced.CompletedAt.Valid)
+	require.WithinDuration(t, dueDate.AddDate(0, 0, 7), advanced.DueDate, time.Second)
+
+	//Both the relative and the absolute reminder move along with the due date
+	reminders, err := testQueries.ListTaskReminders(context.Background(), advanced.ID)
+
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+	require.WithinDuration(t, dueDate.AddDate(0, 0, 6), reminders[0].RemindAt, time.Second)
+	require.WithinDuration(t, dueDate.AddDate(0, 0, 7).Add(-time.Hour), reminders[1].RemindAt, time.Second)
+
+	//The rule only has two occurrences so the second completion is final
+	completed, err = store.CompleteTaskTx(context.Background(), result.Task.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusDone, completed.Task.Status)
+	require.True(t, completed.Task.CompletedAt.Valid)
+}
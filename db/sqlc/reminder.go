@@ -0,0 +1,34 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ReminderSpec describes a reminder before it is attached to a task. A valid
+// OffsetSeconds makes the reminder relative to the due date of the task,
+// otherwise it fires at RemindAt.
+type ReminderSpec struct {
+	OffsetSeconds sql.NullInt32
+	RemindAt time.Time
+}
+
+// Params resolves the reminder against task.
+func (spec ReminderSpec) Params(task Task) CreateTaskReminderParams {
+	arg := CreateTaskReminderParams {
+		TaskID: task.ID,
+		OffsetSeconds: spec.OffsetSeconds,
+		RemindAt: spec.RemindAt,
+	}
+
+	if spec.OffsetSeconds.Valid {
+		arg.RemindAt = task.DueDate.Add(-time.Duration(spec.OffsetSeconds.Int32) * time.Second)
+	}
+
+	return arg
+}
+
+// IsRelative reports whether the reminder follows the due date of its task.
+func (reminder TaskReminder) IsRelative() bool {
+	return reminder.OffsetSeconds.Valid
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueAndClaimReminders(t *testing.T) {
+	user := createRandomUser(t)
+
+	remindAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	task, err := testQueries.CreateTask(context.Background(), CreateTaskParams{
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: remindAt.Add(time.Hour),
+		Priority: TaskPriorityNone,
+	})
+
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateTaskReminder(context.Background(), ReminderSpec{RemindAt: remindAt}.Params(task))
+
+	require.NoError(t, err)
+
+	arg := EnqueueDueRemindersParams {
+		Since: remindAt.Add(-time.Second),
+		Until: remindAt.Add(time.Second),
+	}
+
+	_, err = testQueries.EnqueueDueReminders(context.Background(), arg)
+	require.NoError(t, err)
+
+	//Enqueueing the same window again must not duplicate the delivery
+	_, err = testQueries.EnqueueDueReminders(context.Background(), arg)
+	require.NoError(t, err)
+
+	deliveries, err := testQueries.ListReminderDeliveries(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, DeliveryStatusPending, deliveries[0].Status)
+
+	now := time.Now()
+
+	claimed, err := testQueries.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		Now: now,
+		BatchSize: 1000,
+		LeaseUntil: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	var delivery ReminderDelivery
+
+	for _, c := range claimed {
+		if c.TaskID == task.ID {
+			delivery = c
+		}
+	}
+
+	require.Equal(t, deliveries[0].ID, delivery.ID)
+	require.Equal(t, int32(1), delivery.Attempts)
+
+	//A leased delivery is invisible to other dispatchers
+	claimed, err = testQueries.ClaimDueReminders(context.Background(), ClaimDueRemindersParams{
+		Now: now,
+		BatchSize: 1000,
+		LeaseUntil: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	for _, c := range claimed {
+		require.NotEqual(t, delivery.ID, c.ID)
+	}
+
+	sent, err := testQueries.MarkReminderSent(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, DeliveryStatusSent, sent.Status)
+	require.True(t, sent.DeliveredAt.Valid)
+}
+
+func TestRemindersFollowDueDate(t *testing.T) {
+	store := NewStore(testDB)
+
+	dueDate := util.RandomDate()
+	remindAt := util.RandomReminderDate(dueDate)
+
+	result, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: createRandomUser(t).ID,
+			Title: util.RandomString(6),
+			DueDate: dueDate,
+			Priority: TaskPriorityNone,
+		},
+		Reminders: []ReminderSpec{
+			{OffsetSeconds: sql.NullInt32{Int32: 15 * 60, Valid: true}},
+			{RemindAt: remindAt},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Reminders, 2)
+
+	newDueDate := dueDate.Add(48 * time.Hour)
+
+	updated, err := store.UpdateTaskTx(context.Background(), UpdateTaskTxParams{
+		UpdateTaskParams: UpdateTaskParams {
+			ID: result.Task.ID,
+			DueDate: sql.NullTime{Time: newDueDate, Valid: true},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, updated.Reminders, 2)
+
+	for _, reminder := range updated.Reminders {
+		if reminder.IsRelative() {
+			require.WithinDuration(t, newDueDate.Add(-15*time.Minute), reminder.RemindAt, time.Second)
+		} else {
+			require.WithinDuration(t, remindAt, reminder.RemindAt, time.Second)
+		}
+	}
+}
+
+func TestReplaceDefaultRemindersTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	defaults, err := store.ReplaceDefaultRemindersTx(context.Background(), ReplaceDefaultRemindersTxParams{
+		UserID: user.ID,
+		OffsetSeconds: []int32{900, 86400, 900},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, defaults, 2)
+	require.Equal(t, int32(86400), defaults[0].OffsetSeconds)
+	require.Equal(t, int32(900), defaults[1].OffsetSeconds)
+
+	defaults, err = store.ReplaceDefaultRemindersTx(context.Background(), ReplaceDefaultRemindersTxParams{UserID: user.ID})
+
+	require.NoError(t, err)
+	require.Empty(t, defaults)
+}
+
+func TestSnoozeReminderTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	remindAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	task, err := store.CreateTask(context.Background(), CreateTaskParams{
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: remindAt.Add(time.Hour),
+		Priority: TaskPriorityNone,
+	})
+	require.NoError(t, err)
+
+	reminder, err := store.CreateTaskReminder(context.Background(), ReminderSpec{RemindAt: remindAt}.Params(task))
+	require.NoError(t, err)
+
+	fired, err := store.GetLatestFiredReminder(context.Background(), GetLatestFiredReminderParams{TaskID: task.ID, Now: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, reminder.ID, fired.ID)
+
+	until := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	result, err := store.SnoozeReminderTx(context.Background(), SnoozeReminderTxParams{
+		Reminder: reminder,
+		Preset: "10m",
+		Until: until,
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, until, result.Reminder.RemindAt, time.Second)
+	require.WithinDuration(t, remindAt, result.Reminder.OriginalRemindAt.Time, time.Second)
+	require.Equal(t, int32(1), result.Reminder.SnoozeCount)
+	require.Equal(t, reminder.ID, result.Snooze.ReminderID.UUID)
+
+	//Snoozing again keeps the time the reminder was originally due
+	result, err = store.SnoozeReminderTx(context.Background(), SnoozeReminderTxParams{
+		Reminder: result.Reminder,
+		Preset: "1h",
+		Until: until.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, remindAt, result.Reminder.OriginalRemindAt.Time, time.Second)
+	require.Equal(t, int32(2), result.Reminder.SnoozeCount)
+
+	snoozes, err := store.ListReminderSnoozes(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, snoozes, 2)
+
+	dismissed, err := store.DismissReminderTx(context.Background(), reminder.ID)
+	require.NoError(t, err)
+	require.True(t, dismissed.DismissedAt.Valid)
+}
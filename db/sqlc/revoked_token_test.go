@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeToken(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := RevokeTokenParams {
+		ID: uuid.New(),
+		UserID: user.ID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	require.NoError(t, testQueries.RevokeToken(context.Background(), arg))
+
+	//Revoking a token twice is fine
+	require.NoError(t, testQueries.RevokeToken(context.Background(), arg))
+
+	revoked, err := testQueries.ListRevokedTokens(context.Background(), time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	ids := make([]uuid.UUID, 0, len(revoked))
+	for _, row := range revoked {
+		ids = append(ids, row.ID)
+	}
+	require.Contains(t, ids, arg.ID)
+}
+
+func TestRevokeUserAccessTokens(t *testing.T) {
+	user := createRandomUser(t)
+
+	session := createRandomSession(t, user, uuid.New())
+
+	//Sessions from before access tokens were recorded have nothing to revoke
+	createRandomSessionWithAccessToken(t, user, sql.NullTime{})
+
+	revoked, err := testQueries.RevokeUserAccessTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, revoked, 1)
+	require.Equal(t, session.AccessTokenID.UUID, revoked[0].ID)
+
+	//Tokens that are already revoked are skipped
+	revoked, err = testQueries.RevokeUserAccessTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Empty(t, revoked)
+}
+
+func TestRevokeSessionFamilyAccessTokens(t *testing.T) {
+	user := createRandomUser(t)
+
+	familyID := uuid.New()
+	first := createRandomSession(t, user, familyID)
+	second := createRandomSession(t, user, familyID)
+	createRandomSession(t, user, uuid.New())
+
+	revoked, err := testQueries.RevokeSessionFamilyAccessTokens(context.Background(), familyID)
+	require.NoError(t, err)
+
+	ids := make([]uuid.UUID, 0, len(revoked))
+	for _, row := range revoked {
+		ids = append(ids, row.ID)
+	}
+	require.ElementsMatch(t, []uuid.UUID{first.AccessTokenID.UUID, second.AccessTokenID.UUID}, ids)
+
+	revoked, err = testQueries.RevokeSessionFamilyAccessTokens(context.Background(), familyID)
+	require.NoError(t, err)
+	require.Empty(t, revoked)
+}
+
+func TestDeleteExpiredRevokedTokens(t *testing.T) {
+	user := createRandomUser(t)
+
+	expired := RevokeTokenParams {
+		ID: uuid.New(),
+		UserID: user.ID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, testQueries.RevokeToken(context.Background(), expired))
+
+	deleted, err := testQueries.DeleteExpiredRevokedTokens(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	revoked, err := testQueries.ListRevokedTokens(context.Background(), time.Time{})
+	require.NoError(t, err)
+
+	for _, row := range revoked {
+		require.NotEqual(t, expired.ID, row.ID)
+	}
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User, familyID uuid.UUID) Session {
+	arg := CreateSessionParams {
+		ID: uuid.New(),
+		UserID: user.ID,
+		FamilyID: familyID,
+		UserAgent: "your-time-test/1.0",
+		ClientIp: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		AccessTokenID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		AccessTokenExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.IsBlocked)
+	require.False(t, session.RotatedAt.Valid)
+	require.Equal(t, arg.AccessTokenID, session.AccessTokenID)
+
+	return session
+}
+
+func createRandomSessionWithAccessToken(t *testing.T, user User, accessTokenExpiresAt sql.NullTime) Session {
+	arg := CreateSessionParams {
+		ID: uuid.New(),
+		UserID: user.ID,
+		FamilyID: uuid.New(),
+		UserAgent: "your-time-test/1.0",
+		ClientIp: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+		AccessTokenID: uuid.NullUUID{UUID: uuid.New(), Valid: accessTokenExpiresAt.Valid},
+		AccessTokenExpiresAt: accessTokenExpiresAt,
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+
+	return session
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	familyID := uuid.New()
+	first := createRandomSession(t, user, familyID)
+
+	arg := RotateSessionTxParams {
+		PreviousID: first.ID,
+		Next: CreateSessionParams {
+			ID: uuid.New(),
+			UserID: user.ID,
+			FamilyID: familyID,
+			UserAgent: first.UserAgent,
+			ClientIp: first.ClientIp,
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+	}
+
+	next, err := store.RotateSessionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Next.ID, next.ID)
+
+	rotated, err := testQueries.GetSession(context.Background(), first.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.RotatedAt.Valid)
+
+	//A refresh token can only be exchanged once
+	arg.Next.ID = uuid.New()
+	_, err = store.RotateSessionTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSessionRotated)
+
+	_, err = testQueries.GetSession(context.Background(), arg.Next.ID)
+	require.Error(t, err)
+}
+
+func TestBlockSessionFamily(t *testing.T) {
+	user := createRandomUser(t)
+
+	familyID := uuid.New()
+	createRandomSession(t, user, familyID)
+	createRandomSession(t, user, familyID)
+	other := createRandomSession(t, user, uuid.New())
+
+	blocked, err := testQueries.BlockSessionFamily(context.Background(), familyID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+
+	other, err = testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, other.IsBlocked)
+}
+
+func TestBlockAccessTokenSession(t *testing.T) {
+	user := createRandomUser(t)
+
+	familyID := uuid.New()
+	first := createRandomSession(t, user, familyID)
+	createRandomSession(t, user, familyID)
+	other := createRandomSession(t, user, uuid.New())
+
+	blocked, err := testQueries.BlockAccessTokenSession(context.Background(), first.AccessTokenID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+
+	other, err = testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, other.IsBlocked)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+
+	createRandomSession(t, user, uuid.New())
+	createRandomSession(t, user, uuid.New())
+
+	blocked, err := testQueries.BlockUserSessions(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+}
@@ -3,14 +3,47 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 
 type Store interface {
 	Querier
+	ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error)
+	CreateTaskTx(ctx context.Context, arg CreateTaskTxParams) (TaskTxResult, error)
+	UpdateTaskTx(ctx context.Context, arg UpdateTaskTxParams) (TaskTxResult, error)
+	CompleteTaskTx(ctx context.Context, id uuid.UUID) (TaskTreeTxResult, error)
+	TrashTaskTx(ctx context.Context, arg TrashTaskParams) (TaskTreeTxResult, error)
+	RestoreTaskTx(ctx context.Context, arg RestoreTaskParams) (TaskTreeTxResult, error)
+	MoveTaskTx(ctx context.Context, arg MoveTaskParams) (Task, error)
+	ReplaceDefaultRemindersTx(ctx context.Context, arg ReplaceDefaultRemindersTxParams) ([]DefaultReminder, error)
+	SnoozeReminderTx(ctx context.Context, arg SnoozeReminderTxParams) (SnoozeReminderTxResult, error)
+	DismissReminderTx(ctx context.Context, id uuid.UUID) (TaskReminder, error)
+	ReplaceQuietHoursTx(ctx context.Context, arg ReplaceQuietHoursTxParams) ([]QuietHour, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	ChangeEmailTx(ctx context.Context, arg ChangeEmailTxParams) (User, error)
+	EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) error
+	ReplaceRecoveryCodesTx(ctx context.Context, arg ReplaceRecoveryCodesTxParams) error
+	DisableTOTPTx(ctx context.Context, userID uuid.UUID) error
 }
 
+// MaxTaskDepth is how many levels deep tasks can be nested, counting the top level task.
+const MaxTaskDepth = 5
+
+var (
+	ErrTaskCycle = errors.New("a task can't be moved under itself or one of its subtasks")
+	ErrTaskTooDeep = fmt.Errorf("subtasks can't be nested more than %d levels deep", MaxTaskDepth)
+	ErrSessionRotated = errors.New("refresh token has already been used")
+	ErrPasswordResetInvalid = errors.New("password reset token is invalid or has expired")
+	ErrTOTPNotPending = errors.New("two-factor authentication isn't being set up")
+)
+
 type SQLStore struct {
 	*Queries
 	db *sql.DB
@@ -42,4 +75,625 @@ func (store *SQLStore) execTx (ctx context.Context, fn func(*Queries) error) err
 	}
 
 	return tx.Commit()
-}
\ No newline at end of file
+}
+
+type CreateTaskTxParams struct {
+	CreateTaskParams
+	Tags []string
+	Reminders []ReminderSpec
+}
+
+type UpdateTaskTxParams struct {
+	UpdateTaskParams
+	//SetTags replaces the tags of the task with Tags, leaving them untouched otherwise
+	SetTags bool
+	Tags []string
+	//SetReminders replaces the reminders of the task with Reminders, leaving them untouched otherwise
+	SetReminders bool
+	Reminders []ReminderSpec
+}
+
+type TaskTxResult struct {
+	Task Task
+	Tags []GetTagsForTasksRow
+	Reminders []TaskReminder
+	//Descendants are the subtasks completed along with the task
+	Descendants []Task
+}
+
+// TaskTreeTxResult is a task together with the subtasks that changed along with it.
+type TaskTreeTxResult struct {
+	Task Task
+	Descendants []Task
+}
+
+// CreateTaskTx creates a task and attaches its tags and reminders, creating the tags the user doesn't have yet.
+func (store *SQLStore) CreateTaskTx(ctx context.Context, arg CreateTaskTxParams) (TaskTxResult, error) {
+	var result TaskTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.ParentID.Valid {
+			if err = q.LockTaskTree(ctx, arg.UserID); err != nil {
+				return err
+			}
+
+			if err = checkTaskPlacement(ctx, q, arg.ParentID.UUID, uuid.Nil, 1); err != nil {
+				return err
+			}
+		}
+
+		result.Task, err = q.CreateTask(ctx, arg.CreateTaskParams)
+		if err != nil {
+			return err
+		}
+
+		result.Tags, err = setTaskTags(ctx, q, result.Task, arg.Tags)
+		if err != nil {
+			return err
+		}
+
+		result.Reminders, err = setTaskReminders(ctx, q, result.Task, arg.Reminders)
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateTaskTx updates a task and, when SetTags or SetReminders is true, replaces its
+// tags or reminders in the same transaction. Relative reminders follow a new due date.
+// Marking a task as done is handled like CompleteTaskTx.
+func (store *SQLStore) UpdateTaskTx(ctx context.Context, arg UpdateTaskTxParams) (TaskTxResult, error) {
+	var result TaskTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Task, err = q.UpdateTask(ctx, arg.UpdateTaskParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.SetReminders {
+			if _, err = setTaskReminders(ctx, q, result.Task, arg.Reminders); err != nil {
+				return err
+			}
+		} else if arg.DueDate.Valid {
+			err = q.RescheduleTaskReminders(ctx, RescheduleTaskRemindersParams{TaskID: result.Task.ID, DueDate: result.Task.DueDate})
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.Status.Valid && arg.Status.TaskStatus == TaskStatusDone {
+			if result.Task, result.Descendants, err = finishTask(ctx, q, result.Task); err != nil {
+				return err
+			}
+		}
+
+		if arg.SetTags {
+			result.Tags, err = setTaskTags(ctx, q, result.Task, arg.Tags)
+		} else {
+			result.Tags, err = q.GetTagsForTasks(ctx, []uuid.UUID{result.Task.ID})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result.Reminders, err = q.ListTaskReminders(ctx, result.Task.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// CompleteTaskTx completes a task together with all of its open subtasks.
+// Recurring tasks advance to their next occurrence instead.
+func (store *SQLStore) CompleteTaskTx(ctx context.Context, id uuid.UUID) (TaskTreeTxResult, error) {
+	var result TaskTreeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Task, err = q.CompleteTask(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		result.Task, result.Descendants, err = finishTask(ctx, q, result.Task)
+		return err
+	})
+
+	return result, err
+}
+
+// finishTask runs after a task has been marked as done. A recurring task
+// with occurrences left moves on to the next one, with its reminders moving
+// along with the due date. Any other task completes its subtasks, which are
+// returned.
+func finishTask(ctx context.Context, q *Queries, task Task) (Task, []Task, error) {
+	if task.RecurrenceRule.Valid {
+		recurrence, err := task.Recurrence()
+		if err != nil {
+			return task, nil, err
+		}
+
+		next, ok, err := recurrence.Next(task.DueDate)
+		if err != nil {
+			return task, nil, err
+		}
+
+		if ok {
+			advanced, err := q.AdvanceRecurringTask(ctx, AdvanceRecurringTaskParams{ID: task.ID, DueDate: next})
+			if err != nil {
+				return task, nil, err
+			}
+
+			err = q.RescheduleTaskReminders(ctx, RescheduleTaskRemindersParams{
+				TaskID: task.ID,
+				DueDate: next,
+				ShiftSeconds: int64(next.Sub(task.DueDate) / time.Second),
+			})
+
+			return advanced, nil, err
+		}
+	}
+
+	descendants, err := q.CompleteTaskDescendants(ctx, task.ID)
+
+	return task, descendants, err
+}
+
+// TrashTaskTx moves a task and its subtasks to the trash. The subtasks share
+// the deleted_at of the task so RestoreTaskTx can bring back the same set.
+func (store *SQLStore) TrashTaskTx(ctx context.Context, arg TrashTaskParams) (TaskTreeTxResult, error) {
+	var result TaskTreeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Task, err = q.TrashTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Descendants, err = q.TrashTaskDescendants(ctx, TrashTaskDescendantsParams{ID: result.Task.ID, DeletedAt: result.Task.DeletedAt.Time})
+		return err
+	})
+
+	return result, err
+}
+
+// RestoreTaskTx restores a task and the subtasks that were trashed along with it.
+// Subtasks trashed on their own before the task stay in the trash.
+func (store *SQLStore) RestoreTaskTx(ctx context.Context, arg RestoreTaskParams) (TaskTreeTxResult, error) {
+	var result TaskTreeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		//Descendants are matched on the deleted_at of the task, so they have to be restored first
+
+		if result.Descendants, err = q.RestoreTaskDescendants(ctx, arg.ID); err != nil {
+			return err
+		}
+
+		result.Task, err = q.RestoreTask(ctx, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// MoveTaskTx moves a task, together with its subtasks, under a new parent or
+// to the top level when ParentID isn't valid.
+func (store *SQLStore) MoveTaskTx(ctx context.Context, arg MoveTaskParams) (Task, error) {
+	var task Task
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		task, err = q.GetTaskByID(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if arg.ParentID.Valid {
+			//Concurrent moves could otherwise build a cycle the checks below can't see
+
+			if err = q.LockTaskTree(ctx, task.UserID); err != nil {
+				return err
+			}
+
+			height, err := q.GetSubtreeHeight(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+
+			if err = checkTaskPlacement(ctx, q, arg.ParentID.UUID, task.ID, height); err != nil {
+				return err
+			}
+		}
+
+		task, err = q.MoveTask(ctx, arg)
+		return err
+	})
+
+	return task, err
+}
+
+// checkTaskPlacement makes sure a subtree of the given height can be placed
+// under parentID without nesting too deep or putting taskID under itself.
+func checkTaskPlacement(ctx context.Context, q *Queries, parentID uuid.UUID, taskID uuid.UUID, height int32) error {
+	ancestors, err := q.GetTaskAncestors(ctx, parentID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ancestors {
+		if id == taskID {
+			return ErrTaskCycle
+		}
+	}
+
+	if len(ancestors)+int(height) > MaxTaskDepth {
+		return ErrTaskTooDeep
+	}
+
+	return nil
+}
+
+func setTaskTags(ctx context.Context, q *Queries, task Task, names []string) ([]GetTagsForTasksRow, error) {
+	if err := q.RemoveTaskTags(ctx, task.ID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		tag, err := q.UpsertTagByName(ctx, UpsertTagByNameParams{UserID: task.UserID, Name: name})
+		if err != nil {
+			return nil, err
+		}
+
+		err = q.AddTaskTag(ctx, AddTaskTagParams{TaskID: task.ID, TagID: tag.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return q.GetTagsForTasks(ctx, []uuid.UUID{task.ID})
+}
+
+func setTaskReminders(ctx context.Context, q *Queries, task Task, reminders []ReminderSpec) ([]TaskReminder, error) {
+	if err := q.DeleteTaskReminders(ctx, task.ID); err != nil {
+		return nil, err
+	}
+
+	for _, spec := range reminders {
+		if _, err := q.CreateTaskReminder(ctx, spec.Params(task)); err != nil {
+			return nil, err
+		}
+	}
+
+	return q.ListTaskReminders(ctx, task.ID)
+}
+
+type ReplaceDefaultRemindersTxParams struct {
+	UserID uuid.UUID
+	OffsetSeconds []int32
+}
+
+// ReplaceDefaultRemindersTx replaces the reminders createTask gives new tasks of a user.
+func (store *SQLStore) ReplaceDefaultRemindersTx(ctx context.Context, arg ReplaceDefaultRemindersTxParams) ([]DefaultReminder, error) {
+	var reminders []DefaultReminder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteDefaultReminders(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		seen := make(map[int32]bool)
+
+		for _, offset := range arg.OffsetSeconds {
+			if seen[offset] {
+				continue
+			}
+			seen[offset] = true
+
+			_, err := q.CreateDefaultReminder(ctx, CreateDefaultReminderParams{UserID: arg.UserID, OffsetSeconds: offset})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		reminders, err = q.ListDefaultReminders(ctx, arg.UserID)
+		return err
+	})
+
+	return reminders, err
+}
+
+type ReplaceQuietHoursTxParams struct {
+	UserID uuid.UUID
+	//Windows only need Weekday, StartMinute and EndMinute
+	Windows []QuietHour
+}
+
+// ReplaceQuietHoursTx replaces the quiet hour windows of a user.
+func (store *SQLStore) ReplaceQuietHoursTx(ctx context.Context, arg ReplaceQuietHoursTxParams) ([]QuietHour, error) {
+	var windows []QuietHour
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteQuietHours(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		for _, window := range arg.Windows {
+			_, err := q.CreateQuietHour(ctx, CreateQuietHourParams{
+				UserID: arg.UserID,
+				Weekday: window.Weekday,
+				StartMinute: window.StartMinute,
+				EndMinute: window.EndMinute,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		windows, err = q.ListQuietHours(ctx, arg.UserID)
+		return err
+	})
+
+	return windows, err
+}
+
+type SnoozeReminderTxParams struct {
+	Reminder TaskReminder
+	//Preset is how the client picked Until and is only kept for the history
+	Preset string
+	Until time.Time
+}
+
+type SnoozeReminderTxResult struct {
+	Reminder TaskReminder
+	Snooze ReminderSnooze
+}
+
+// SnoozeReminderTx moves a reminder to a later time and records the snooze.
+// Retries of a delivery for the old time are cancelled, the reminder is
+// delivered again once the new time is reached.
+func (store *SQLStore) SnoozeReminderTx(ctx context.Context, arg SnoozeReminderTxParams) (SnoozeReminderTxResult, error) {
+	var result SnoozeReminderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Reminder, err = q.SnoozeTaskReminder(ctx, SnoozeTaskReminderParams{ID: arg.Reminder.ID, SnoozedUntil: arg.Until})
+		if err != nil {
+			return err
+		}
+
+		result.Snooze, err = q.CreateReminderSnooze(ctx, CreateReminderSnoozeParams{
+			TaskID: arg.Reminder.TaskID,
+			ReminderID: uuid.NullUUID{UUID: arg.Reminder.ID, Valid: true},
+			Preset: arg.Preset,
+			SnoozedFrom: arg.Reminder.RemindAt,
+			SnoozedUntil: arg.Until,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CancelPendingReminderDeliveries(ctx, arg.Reminder.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// DismissReminderTx marks a reminder as handled, so it isn't delivered or retried anymore.
+func (store *SQLStore) DismissReminderTx(ctx context.Context, id uuid.UUID) (TaskReminder, error) {
+	var reminder TaskReminder
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		reminder, err = q.DismissTaskReminder(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CancelPendingReminderDeliveries(ctx, id)
+		return err
+	})
+
+	return reminder, err
+}
+
+type RotateSessionTxParams struct {
+	//PreviousID is the session whose refresh token is exchanged
+	PreviousID uuid.UUID
+	Next CreateSessionParams
+}
+
+// RotateSessionTx replaces a session with the next one of its family. A
+// refresh token can only be exchanged once, ErrSessionRotated is returned
+// when the previous session was rotated or blocked in the meantime.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (Session, error) {
+	var session Session
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rotated, err := q.RotateSession(ctx, arg.PreviousID)
+		if err != nil {
+			return err
+		}
+
+		if rotated == 0 {
+			return ErrSessionRotated
+		}
+
+		session, err = q.CreateSession(ctx, arg.Next)
+		return err
+	})
+
+	return session, err
+}
+
+type ResetPasswordTxParams struct {
+	ResetID uuid.UUID
+	UserID uuid.UUID
+	//Password is the new password, already hashed
+	Password string
+}
+
+type ResetPasswordTxResult struct {
+	User User
+	SignedOutSessions int64
+}
+
+// ResetPasswordTx redeems a password reset token and sets the new password.
+// Every other reset token of the user stops working and every session is
+// blocked. ErrPasswordResetInvalid is returned when the token was used or
+// expired in the meantime.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		used, err := q.UsePasswordReset(ctx, arg.ResetID)
+		if err != nil {
+			return err
+		}
+
+		if used == 0 {
+			return ErrPasswordResetInvalid
+		}
+
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams {
+			Password: arg.Password,
+			ID: arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.UseUserPasswordResets(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		result.SignedOutSessions, err = q.BlockUserSessions(ctx, arg.UserID)
+		return err
+	})
+
+	return result, err
+}
+
+type ChangeEmailTxParams struct {
+	UserID uuid.UUID
+	Email string
+}
+
+// ChangeEmailTx sets a new, unverified email and blocks every session of the
+// user. Tokens name the user by email, so the ones issued before the change
+// must not outlive it.
+func (store *SQLStore) ChangeEmailTx(ctx context.Context, arg ChangeEmailTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.UpdateUserEmail(ctx, UpdateUserEmailParams {
+			Email: arg.Email,
+			ID: arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.BlockUserSessions(ctx, arg.UserID)
+		return err
+	})
+
+	return user, err
+}
+
+type EnableTOTPTxParams struct {
+	UserID uuid.UUID
+	//Step is the period of the code the user confirmed with
+	Step int64
+	RecoveryCodeHashes []string
+}
+
+// EnableTOTPTx turns two-factor authentication on once the user entered a
+// first code, and stores their recovery codes. ErrTOTPNotPending is returned
+// when there is no unconfirmed secret, e.g. it was confirmed meanwhile.
+func (store *SQLStore) EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		confirmed, err := q.ConfirmTOTPCredential(ctx, ConfirmTOTPCredentialParams {
+			Step: arg.Step,
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if confirmed == 0 {
+			return ErrTOTPNotPending
+		}
+
+		return replaceRecoveryCodes(ctx, q, arg.UserID, arg.RecoveryCodeHashes)
+	})
+}
+
+type ReplaceRecoveryCodesTxParams struct {
+	UserID uuid.UUID
+	RecoveryCodeHashes []string
+}
+
+// ReplaceRecoveryCodesTx swaps the recovery codes of the user for new ones,
+// used or not.
+func (store *SQLStore) ReplaceRecoveryCodesTx(ctx context.Context, arg ReplaceRecoveryCodesTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		return replaceRecoveryCodes(ctx, q, arg.UserID, arg.RecoveryCodeHashes)
+	})
+}
+
+func replaceRecoveryCodes(ctx context.Context, q *Queries, userID uuid.UUID, hashes []string) error {
+	if err := q.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		err := q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams {
+			UserID: userID,
+			CodeHash: hash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DisableTOTPTx turns two-factor authentication off, dropping the secret and
+// the recovery codes.
+func (store *SQLStore) DisableTOTPTx(ctx context.Context, userID uuid.UUID) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if _, err := q.DeleteTOTPCredential(ctx, userID); err != nil {
+			return err
+		}
+
+		return q.DeleteRecoveryCodes(ctx, userID)
+	})
+}
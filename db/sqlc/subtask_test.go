@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createSubtask(t *testing.T, store Store, parent Task) Task {
+	result, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: parent.UserID,
+			Title: util.RandomString(6),
+			DueDate: util.RandomDate(),
+			Priority: TaskPriorityNone,
+			ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, parent.ID, result.Task.ParentID.UUID)
+
+	return result.Task
+}
+
+func TestSubtaskDepthLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	task := createRandomTask(t, createRandomUser(t))
+
+	for depth := 2; depth <= MaxTaskDepth; depth++ {
+		task = createSubtask(t, store, task)
+	}
+
+	_, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: task.UserID,
+			Title: util.RandomString(6),
+			DueDate: util.RandomDate(),
+			Priority: TaskPriorityNone,
+			ParentID: uuid.NullUUID{UUID: task.ID, Valid: true},
+		},
+	})
+
+	require.ErrorIs(t, err, ErrTaskTooDeep)
+}
+
+func TestMoveTaskTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	root := createRandomTask(t, user)
+	child := createSubtask(t, store, root)
+	grandchild := createSubtask(t, store, child)
+
+	//A task can't end up below one of its own subtasks
+
+	_, err := store.MoveTaskTx(context.Background(), MoveTaskParams{
+		ID: root.ID,
+		ParentID: uuid.NullUUID{UUID: grandchild.ID, Valid: true},
+	})
+
+	require.ErrorIs(t, err, ErrTaskCycle)
+
+	other := createRandomTask(t, user)
+
+	moved, err := store.MoveTaskTx(context.Background(), MoveTaskParams{
+		ID: child.ID,
+		ParentID: uuid.NullUUID{UUID: other.ID, Valid: true},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, other.ID, moved.ParentID.UUID)
+
+	children, err := testQueries.GetChildTasks(context.Background(), other.ID)
+
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	require.Equal(t, child.ID, children[0].ID)
+
+	moved, err = store.MoveTaskTx(context.Background(), MoveTaskParams{ID: child.ID})
+
+	require.NoError(t, err)
+	require.False(t, moved.ParentID.Valid)
+}
+
+func taskIDs(tasks []Task) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tasks))
+
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	return ids
+}
+
+func TestCompleteTaskTxCascades(t *testing.T) {
+	store := NewStore(testDB)
+
+	root := createRandomTask(t, createRandomUser(t))
+	child := createSubtask(t, store, root)
+	grandchild := createSubtask(t, store, child)
+
+	result, err := store.CompleteTaskTx(context.Background(), root.ID)
+
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{child.ID, grandchild.ID}, taskIDs(result.Descendants))
+
+	for _, id := range []uuid.UUID{child.ID, grandchild.ID} {
+		task, err := testQueries.GetTaskByID(context.Background(), id)
+
+		require.NoError(t, err)
+		require.Equal(t, TaskStatusDone, task.Status)
+		require.True(t, task.CompletedAt.Valid)
+	}
+
+	rollups, err := testQueries.GetSubtaskRollups(context.Background(), []uuid.UUID{root.ID, child.ID})
+
+	require.NoError(t, err)
+	require.Len(t, rollups, 2)
+
+	for _, rollup := range rollups {
+		require.Equal(t, int64(1), rollup.ChildCount)
+		require.Equal(t, int64(1), rollup.DoneCount)
+	}
+}
+
+func TestTrashAndRestoreTaskTxCascades(t *testing.T) {
+	store := NewStore(testDB)
+
+	root := createRandomTask(t, createRandomUser(t))
+	child := createSubtask(t, store, root)
+	trashedEarlier := createSubtask(t, store, root)
+
+	_, err := store.TrashTaskTx(context.Background(), TrashTaskParams{ID: trashedEarlier.ID, UserID: root.UserID})
+
+	require.NoError(t, err)
+
+	result, err := store.TrashTaskTx(context.Background(), TrashTaskParams{ID: root.ID, UserID: root.UserID})
+
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{child.ID}, taskIDs(result.Descendants))
+
+	_, err = testQueries.GetTaskByID(context.Background(), child.ID)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err = store.RestoreTaskTx(context.Background(), RestoreTaskParams{ID: root.ID, UserID: root.UserID})
+
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{child.ID}, taskIDs(result.Descendants))
+
+	_, err = testQueries.GetTaskByID(context.Background(), child.ID)
+
+	require.NoError(t, err)
+
+	//The subtask trashed on its own stays in the trash
+
+	_, err = testQueries.GetTaskByID(context.Background(), trashedEarlier.ID)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTag(t *testing.T, user User) Tag {
+	arg := CreateTagParams {
+		UserID: user.ID,
+		Name: util.RandomString(6),
+		Color: "#ff8800",
+	}
+
+	tag, err := testQueries.CreateTag(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.Equal(t, arg.UserID, tag.UserID)
+	require.Equal(t, arg.Name, tag.Name)
+	require.Equal(t, arg.Color, tag.Color)
+	require.NotZero(t, tag.ID)
+
+	return tag
+}
+
+func TestCreateTag(t *testing.T) {
+	createRandomTag(t, createRandomUser(t))
+}
+
+func TestUpdateAndDeleteTag(t *testing.T) {
+	tag := createRandomTag(t, createRandomUser(t))
+
+	updated, err := testQueries.UpdateTag(context.Background(), UpdateTagParams{
+		ID: tag.ID,
+		Color: sql.NullString{String: "#00ff00", Valid: true},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, tag.Name, updated.Name)
+	require.Equal(t, "#00ff00", updated.Color)
+
+	require.NoError(t, testQueries.DeleteTag(context.Background(), tag.ID))
+
+	_, err = testQueries.GetTagByID(context.Background(), tag.ID)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCreateTaskTxWithTags(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	existing := createRandomTag(t, user)
+
+	result, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: user.ID,
+			Title: util.RandomString(6),
+			DueDate: util.RandomDate(),
+			Priority: TaskPriorityNone,
+		},
+		Tags: []string{existing.Name, "new-tag", " new-tag "},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Tags, 2)
+
+	tags, err := testQueries.ListTagsByUser(context.Background(), user.ID)
+
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	//Replacing the tags detaches the old ones but keeps them around for the user
+
+	updated, err := store.UpdateTaskTx(context.Background(), UpdateTaskTxParams{
+		UpdateTaskParams: UpdateTaskParams{ID: result.Task.ID},
+		SetTags: true,
+		Tags: []string{existing.Name},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, updated.Tags, 1)
+	require.Equal(t, existing.ID, updated.Tags[0].ID)
+
+	rows, err := testQueries.GetTagsForTasks(context.Background(), []uuid.UUID{result.Task.ID})
+
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
@@ -13,28 +13,135 @@ import (
 	"github.com/google/uuid"
 )
 
+const advanceRecurringTask = `-- name: AdvanceRecurringTask :one
+UPDATE tasks
+SET
+    due_date = $1,
+    status = 'open',
+    completed_at = NULL,
+    updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+type AdvanceRecurringTaskParams struct {
+	DueDate time.Time `json:"due_date"`
+	ID      uuid.UUID `json:"id"`
+}
+
+func (q *Queries) AdvanceRecurringTask(ctx context.Context, arg AdvanceRecurringTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, advanceRecurringTask,
+		arg.DueDate,
+		arg.ID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
+const completeTask = `-- name: CompleteTask :one
+UPDATE tasks
+SET
+    status = 'done',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+func (q *Queries) CompleteTask(ctx context.Context, id uuid.UUID) (Task, error) {
+	row := q.db.QueryRowContext(ctx, completeTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
 const createTask = `-- name: CreateTask :one
 INSERT INTO tasks (
     title,
     description,
     due_date,
-    reminder_date,
-    user_id
+    user_id,
+    priority,
+    important,
+    project_id,
+    parent_id,
+    recurrence_rule,
+    recurrence_start,
+    recurrence_timezone,
+    recurrence_exdates,
+    break_through_quiet_hours
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
-) RETURNING id, title, due_date, reminder_date, description, user_id, created_at, updated_at
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+) RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
 `
 
 type CreateTaskParams struct {
-	Title        string         `json:"title"`
-	Description  sql.NullString `json:"description"`
-	DueDate      time.Time      `json:"due_date"`
-	ReminderDate sql.NullTime   `json:"reminder_date"`
-	UserID       uuid.UUID      `json:"user_id"`
+	Title                  string         `json:"title"`
+	Description            sql.NullString `json:"description"`
+	DueDate                time.Time      `json:"due_date"`
+	UserID                 uuid.UUID      `json:"user_id"`
+	Priority               TaskPriority   `json:"priority"`
+	Important              bool           `json:"important"`
+	ProjectID              uuid.NullUUID  `json:"project_id"`
+	ParentID               uuid.NullUUID  `json:"parent_id"`
+	RecurrenceRule         sql.NullString `json:"recurrence_rule"`
+	RecurrenceStart        sql.NullTime   `json:"recurrence_start"`
+	RecurrenceTimezone     sql.NullString `json:"recurrence_timezone"`
+	RecurrenceExdates      sql.NullString `json:"recurrence_exdates"`
+	BreakThroughQuietHours bool           `json:"break_through_quiet_hours"`
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
@@ -42,26 +149,94 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		arg.Title,
 		arg.Description,
 		arg.DueDate,
-		arg.ReminderDate,
 		arg.UserID,
+		arg.Priority,
+		arg.Important,
+		arg.ProjectID,
+		arg.ParentID,
+		arg.RecurrenceRule,
+		arg.RecurrenceStart,
+		arg.RecurrenceTimezone,
+		arg.RecurrenceExdates,
+		arg.BreakThroughQuietHours,
 	)
 	var i Task
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.DueDate,
-		&i.ReminderDate,
 		&i.Description,
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
 	)
 	return i, err
 }
 
+const getOpenTasksByUser = `-- name: GetOpenTasksByUser :many
+SELECT id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours FROM tasks
+WHERE user_id = $1 AND deleted_at IS NULL AND status IN ('open', 'in_progress')
+ORDER BY due_date, id
+`
+
+func (q *Queries) GetOpenTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getOpenTasksByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.DueDate,
+			&i.Description,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Priority,
+			&i.Important,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.RecurrenceRule,
+			&i.RecurrenceStart,
+			&i.RecurrenceTimezone,
+			&i.RecurrenceExdates,
+			&i.BreakThroughQuietHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTaskByID = `-- name: GetTaskByID :one
-SELECT id, title, due_date, reminder_date, description, user_id, created_at, updated_at FROM tasks 
-WHERE id = $1 LIMIT 1
+SELECT id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours FROM tasks 
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetTaskByID(ctx context.Context, id uuid.UUID) (Task, error) {
@@ -71,22 +246,91 @@ func (q *Queries) GetTaskByID(ctx context.Context, id uuid.UUID) (Task, error) {
 		&i.ID,
 		&i.Title,
 		&i.DueDate,
-		&i.ReminderDate,
 		&i.Description,
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
 	)
 	return i, err
 }
 
 const getTasksByUser = `-- name: GetTasksByUser :many
-SELECT id, title, due_date, reminder_date, description, user_id, created_at, updated_at FROM tasks 
-WHERE user_id = $1
+SELECT id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours FROM tasks 
+WHERE user_id = $1 AND deleted_at IS NULL
+AND ($2::task_status IS NULL OR status = $2::task_status)
+`
+
+type GetTasksByUserParams struct {
+	UserID uuid.UUID      `json:"user_id"`
+	Status NullTaskStatus `json:"status"`
+}
+
+func (q *Queries) GetTasksByUser(ctx context.Context, arg GetTasksByUserParams) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getTasksByUser,
+		arg.UserID,
+		arg.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.DueDate,
+			&i.Description,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Priority,
+			&i.Important,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.RecurrenceRule,
+			&i.RecurrenceStart,
+			&i.RecurrenceTimezone,
+			&i.RecurrenceExdates,
+			&i.BreakThroughQuietHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedTasksByUser = `-- name: GetTrashedTasksByUser :many
+SELECT id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours FROM tasks
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
 `
 
-func (q *Queries) GetTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, getTasksByUser, userID)
+func (q *Queries) GetTrashedTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashedTasksByUser, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -98,11 +342,22 @@ func (q *Queries) GetTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task,
 			&i.ID,
 			&i.Title,
 			&i.DueDate,
-			&i.ReminderDate,
 			&i.Description,
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Priority,
+			&i.Important,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.RecurrenceRule,
+			&i.RecurrenceStart,
+			&i.RecurrenceTimezone,
+			&i.RecurrenceExdates,
+			&i.BreakThroughQuietHours,
 		); err != nil {
 			return nil, err
 		}
@@ -116,3 +371,229 @@ func (q *Queries) GetTasksByUser(ctx context.Context, userID uuid.UUID) ([]Task,
 	}
 	return items, nil
 }
+
+const purgeTrashedTasks = `-- name: PurgeTrashedTasks :execrows
+DELETE FROM tasks
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeTrashedTasks(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTrashedTasks, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reopenTask = `-- name: ReopenTask :one
+UPDATE tasks
+SET
+    status = 'open',
+    completed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+func (q *Queries) ReopenTask(ctx context.Context, id uuid.UUID) (Task, error) {
+	row := q.db.QueryRowContext(ctx, reopenTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
+const restoreTask = `-- name: RestoreTask :one
+UPDATE tasks
+SET
+    deleted_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+type RestoreTaskParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RestoreTask(ctx context.Context, arg RestoreTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, restoreTask,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
+const trashTask = `-- name: TrashTask :one
+UPDATE tasks
+SET
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+type TrashTaskParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) TrashTask(ctx context.Context, arg TrashTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, trashTask,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
+const updateTask = `-- name: UpdateTask :one
+UPDATE tasks
+SET
+    title = COALESCE($1, title),
+    description = CASE WHEN $2::boolean THEN $3::text ELSE description END,
+    due_date = COALESCE($4, due_date),
+    status = COALESCE($5::task_status, status),
+    completed_at = CASE
+        WHEN $5::task_status IS NULL THEN completed_at
+        WHEN $5::task_status = 'done' THEN COALESCE(completed_at, NOW())
+        ELSE NULL
+    END,
+    priority = COALESCE($6::task_priority, priority),
+    important = COALESCE($7::boolean, important),
+    break_through_quiet_hours = COALESCE($8::boolean, break_through_quiet_hours),
+    project_id = CASE WHEN $9::boolean THEN $10::uuid ELSE project_id END,
+    recurrence_rule = CASE WHEN $11::boolean THEN $12::text ELSE recurrence_rule END,
+    recurrence_start = CASE WHEN $11::boolean THEN $13::timestamptz ELSE recurrence_start END,
+    recurrence_timezone = CASE WHEN $11::boolean THEN $14::text ELSE recurrence_timezone END,
+    recurrence_exdates = CASE WHEN $11::boolean THEN $15::text ELSE recurrence_exdates END,
+    updated_at = NOW()
+WHERE id = $16 AND deleted_at IS NULL
+RETURNING id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours
+`
+
+type UpdateTaskParams struct {
+	Title                  sql.NullString   `json:"title"`
+	SetDescription         bool             `json:"set_description"`
+	Description            sql.NullString   `json:"description"`
+	DueDate                sql.NullTime     `json:"due_date"`
+	Status                 NullTaskStatus   `json:"status"`
+	Priority               NullTaskPriority `json:"priority"`
+	Important              sql.NullBool     `json:"important"`
+	BreakThroughQuietHours sql.NullBool     `json:"break_through_quiet_hours"`
+	SetProjectID           bool             `json:"set_project_id"`
+	ProjectID              uuid.NullUUID    `json:"project_id"`
+	SetRecurrence          bool             `json:"set_recurrence"`
+	RecurrenceRule         sql.NullString   `json:"recurrence_rule"`
+	RecurrenceStart        sql.NullTime     `json:"recurrence_start"`
+	RecurrenceTimezone     sql.NullString   `json:"recurrence_timezone"`
+	RecurrenceExdates      sql.NullString   `json:"recurrence_exdates"`
+	ID                     uuid.UUID        `json:"id"`
+}
+
+func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, updateTask,
+		arg.Title,
+		arg.SetDescription,
+		arg.Description,
+		arg.DueDate,
+		arg.Status,
+		arg.Priority,
+		arg.Important,
+		arg.BreakThroughQuietHours,
+		arg.SetProjectID,
+		arg.ProjectID,
+		arg.SetRecurrence,
+		arg.RecurrenceRule,
+		arg.RecurrenceStart,
+		arg.RecurrenceTimezone,
+		arg.RecurrenceExdates,
+		arg.ID,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
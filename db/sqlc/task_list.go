@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type TaskSortField string
+
+const (
+	TaskSortDueDate   TaskSortField = "due_date"
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+	TaskSortTitle     TaskSortField = "title"
+)
+
+// taskColumns must list the columns of tasks in the same order as scanTask reads them.
+const taskColumns = "id, title, due_date, description, user_id, created_at, updated_at, deleted_at, status, completed_at, priority, important, project_id, parent_id, " +
+	"recurrence_rule, recurrence_start, recurrence_timezone, recurrence_exdates, break_through_quiet_hours"
+
+// TaskCursor is the keyset position of the last task of a page. Value holds
+// the sort column of that task: a string when sorting by title, a time.Time otherwise.
+type TaskCursor struct {
+	Value interface{}
+	ID    uuid.UUID
+}
+
+type ListTasksParams struct {
+	UserID      uuid.UUID
+	ProjectID   uuid.NullUUID
+	Status      NullTaskStatus
+	DueFrom     sql.NullTime
+	DueTo       sql.NullTime
+	HasReminder sql.NullBool
+	CreatedFrom sql.NullTime
+	CreatedTo   sql.NullTime
+	UpdatedFrom sql.NullTime
+	UpdatedTo   sql.NullTime
+	Search      sql.NullString
+	// Tags limits the listing to tasks tagged with any of the given tag names,
+	// or with all of them when MatchAllTags is set.
+	Tags         []string
+	MatchAllTags bool
+	SortBy       TaskSortField
+	Descending   bool
+	After        *TaskCursor
+	Limit        int32
+}
+
+// ListTasks returns a page of a user's tasks. The filters, sort column and
+// direction are only known at runtime, which sqlc can't express, so the
+// query is built by hand.
+func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
+	sortBy := arg.SortBy
+	if sortBy == "" {
+		sortBy = TaskSortDueDate
+	}
+
+	switch sortBy {
+	case TaskSortDueDate, TaskSortCreatedAt, TaskSortUpdatedAt, TaskSortTitle:
+	default:
+		return nil, fmt.Errorf("unsupported sort field: %s", sortBy)
+	}
+
+	var query strings.Builder
+	args := []interface{}{arg.UserID}
+
+	query.WriteString("SELECT " + taskColumns + " FROM tasks WHERE user_id = $1 AND deleted_at IS NULL")
+
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		query.WriteString(" AND " + fmt.Sprintf(condition, placeholders...))
+	}
+
+	if arg.ProjectID.Valid {
+		where("project_id = $%d", arg.ProjectID.UUID)
+	}
+	if arg.Status.Valid {
+		where("status = $%d", arg.Status)
+	}
+	if arg.DueFrom.Valid {
+		where("due_date >= $%d", arg.DueFrom.Time)
+	}
+	if arg.DueTo.Valid {
+		where("due_date < $%d", arg.DueTo.Time)
+	}
+	if arg.HasReminder.Valid {
+		if arg.HasReminder.Bool {
+			query.WriteString(" AND EXISTS (SELECT 1 FROM task_reminders WHERE task_reminders.task_id = tasks.id)")
+		} else {
+			query.WriteString(" AND NOT EXISTS (SELECT 1 FROM task_reminders WHERE task_reminders.task_id = tasks.id)")
+		}
+	}
+	if arg.CreatedFrom.Valid {
+		where("created_at >= $%d", arg.CreatedFrom.Time)
+	}
+	if arg.CreatedTo.Valid {
+		where("created_at < $%d", arg.CreatedTo.Time)
+	}
+	if arg.UpdatedFrom.Valid {
+		where("updated_at >= $%d", arg.UpdatedFrom.Time)
+	}
+	if arg.UpdatedTo.Valid {
+		where("updated_at < $%d", arg.UpdatedTo.Time)
+	}
+	if arg.Search.Valid && arg.Search.String != "" {
+		where("title ILIKE '%%' || $%d || '%%'", escapeLike(arg.Search.String))
+	}
+
+	if len(arg.Tags) > 0 {
+		tags := uniqueStrings(arg.Tags)
+		subquery := "id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id" +
+			" WHERE tags.user_id = $1 AND tags.name = ANY($%d)"
+		if arg.MatchAllTags {
+			where(subquery+" GROUP BY task_tags.task_id HAVING COUNT(DISTINCT tags.id) = $%d)", pq.Array(tags), len(tags))
+		} else {
+			where(subquery+")", pq.Array(tags))
+		}
+	}
+
+	direction, comparison := "ASC", ">"
+	if arg.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if arg.After != nil {
+		if err := checkCursorValue(sortBy, arg.After.Value); err != nil {
+			return nil, err
+		}
+		where(fmt.Sprintf("(%s, id) %s ($%%d, $%%d)", sortBy, comparison), arg.After.Value, arg.After.ID)
+	}
+
+	query.WriteString(fmt.Sprintf(" ORDER BY %s %s, id %s", sortBy, direction, direction))
+
+	if arg.Limit > 0 {
+		args = append(args, arg.Limit)
+		query.WriteString(fmt.Sprintf(" LIMIT $%d", len(args)))
+	}
+
+	rows, err := q.db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		i, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanTask(rows *sql.Rows) (Task, error) {
+	var i Task
+	err := rows.Scan(
+		&i.ID,
+		&i.Title,
+		&i.DueDate,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Priority,
+		&i.Important,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.RecurrenceRule,
+		&i.RecurrenceStart,
+		&i.RecurrenceTimezone,
+		&i.RecurrenceExdates,
+		&i.BreakThroughQuietHours,
+	)
+	return i, err
+}
+
+func checkCursorValue(sortBy TaskSortField, value interface{}) error {
+	var ok bool
+	if sortBy == TaskSortTitle {
+		_, ok = value.(string)
+	} else {
+		_, ok = value.(time.Time)
+	}
+	if !ok {
+		return fmt.Errorf("cursor value %v doesn't match sort field %s", value, sortBy)
+	}
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
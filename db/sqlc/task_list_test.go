@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTasksPagination(t *testing.T) {
+	user := createRandomUser(t)
+
+	for i := 0; i < 5; i++ {
+		createRandomTask(t, user)
+	}
+
+	arg := ListTasksParams {
+		UserID: user.ID,
+		SortBy: TaskSortDueDate,
+		Limit: 2,
+	}
+
+	var seen []Task
+
+	for {
+		page, err := testQueries.ListTasks(context.Background(), arg)
+
+		require.NoError(t, err)
+
+		seen = append(seen, page...)
+
+		if len(page) < int(arg.Limit) {
+			break
+		}
+
+		last := page[len(page)-1]
+		arg.After = &TaskCursor{Value: last.DueDate, ID: last.ID}
+	}
+
+	require.Len(t, seen, 5)
+
+	for i := 1; i < len(seen); i++ {
+		require.False(t, seen[i].DueDate.Before(seen[i-1].DueDate))
+		require.NotEqual(t, seen[i].ID, seen[i-1].ID)
+	}
+}
+
+func TestListTasksFilters(t *testing.T) {
+	user := createRandomUser(t)
+
+	task := createRandomTask(t, user)
+
+	createRandomTask(t, user)
+
+	arg := ListTasksParams {
+		UserID: user.ID,
+		Search: sql.NullString{String: task.Title, Valid: true},
+		HasReminder: sql.NullBool{Bool: true, Valid: true},
+		DueFrom: sql.NullTime{Time: task.DueDate.Add(-time.Second), Valid: true},
+		DueTo: sql.NullTime{Time: task.DueDate.Add(time.Second), Valid: true},
+		SortBy: TaskSortTitle,
+		Descending: true,
+	}
+
+	tasks, err := testQueries.ListTasks(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.Len(t, tasks, 1)
+
+	require.Equal(t, task.ID, tasks[0].ID)
+}
+
+func TestListTasksCursorMismatch(t *testing.T) {
+	arg := ListTasksParams {
+		SortBy: TaskSortTitle,
+		After: &TaskCursor{Value: time.Now()},
+	}
+
+	_, err := testQueries.ListTasks(context.Background(), arg)
+
+	require.Error(t, err)
+}
+
+func TestListTasksTagFilter(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	both := createTaggedTask(t, store, user, "work", "urgent")
+	work := createTaggedTask(t, store, user, "work")
+	createTaggedTask(t, store, user, "home")
+
+	arg := ListTasksParams {
+		UserID: user.ID,
+		SortBy: TaskSortCreatedAt,
+		Tags: []string{"work", "urgent"},
+		MatchAllTags: true,
+	}
+
+	tasks, err := store.ListTasks(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, both.ID, tasks[0].ID)
+
+	arg.MatchAllTags = false
+
+	tasks, err = store.ListTasks(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	require.Equal(t, both.ID, tasks[0].ID)
+	require.Equal(t, work.ID, tasks[1].ID)
+}
+
+func createTaggedTask(t *testing.T, store Store, user User, tags ...string) Task {
+	result, err := store.CreateTaskTx(context.Background(), CreateTaskTxParams{
+		CreateTaskParams: CreateTaskParams {
+			UserID: user.ID,
+			Title: util.RandomString(6),
+			DueDate: util.RandomDate(),
+			Priority: TaskPriorityNone,
+		},
+		Tags: tags,
+	})
+
+	require.NoError(t, err)
+
+	return result.Task
+}
@@ -20,8 +20,8 @@ func createRandomTask(t *testing.T, user User) Task {
 		UserID: user.ID,
 		Title: util.RandomString(6),
 		Description: sql.NullString{String: util.RandomString(6), Valid: true},
-		ReminderDate: sql.NullTime{Time: util.RandomReminderDate(dueDate), Valid: true},
 		DueDate: dueDate,
+		Priority: TaskPriorityNone,
 	}
 
 	task, err := testQueries.CreateTask(context.Background(), arg)
@@ -36,8 +36,6 @@ func createRandomTask(t *testing.T, user User) Task {
 
 	require.Equal(t, arg.Description, task.Description)
 
-	require.WithinDuration(t, arg.ReminderDate.Time, task.ReminderDate.Time, time.Second)
-
 	require.WithinDuration(t, arg.DueDate, task.DueDate, time.Second)
 
 	require.NotZero(t, task.ID)
@@ -71,8 +69,6 @@ func TestGetTask(t *testing.T) {
 
 	require.Equal(t, task.Description, task2.Description)
 
-	require.WithinDuration(t, task.ReminderDate.Time, task2.ReminderDate.Time, time.Second)
-
 	require.WithinDuration(t, task.DueDate, task2.DueDate, time.Second)
 }
 
@@ -84,7 +80,7 @@ func TestGetTasksByUser(t *testing.T) {
 		createRandomTask(t, user)
 	}
 
-	tasks, err := testQueries.GetTasksByUser(context.Background(), user.ID)
+	tasks, err := testQueries.GetTasksByUser(context.Background(), GetTasksByUserParams{UserID: user.ID})
 
 	require.NoError(t, err)
 
@@ -99,4 +95,196 @@ func TestGetTasksByUser(t *testing.T) {
 
 		require.NotZero(t, task.CreatedAt)
 	}
-}
\ No newline at end of file
+}
+
+func TestUpdateTask(t *testing.T) {
+	user := createRandomUser(t)
+
+	task := createRandomTask(t, user)
+
+	arg := UpdateTaskParams {
+		ID: task.ID,
+		Title: sql.NullString{String: util.RandomString(6), Valid: true},
+		SetDescription: true,
+		Description: sql.NullString{},
+	}
+
+	task2, err := testQueries.UpdateTask(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.NotEmpty(t, task2)
+
+	require.Equal(t, task.ID, task2.ID)
+
+	require.Equal(t, arg.Title.String, task2.Title)
+
+	require.False(t, task2.Description.Valid)
+
+	//Fields that were not supplied must be left untouched
+
+	require.WithinDuration(t, task.DueDate, task2.DueDate, time.Second)
+
+	require.True(t, task2.UpdatedAt.After(task.UpdatedAt))
+}
+
+func TestTrashAndRestoreTask(t *testing.T) {
+	user := createRandomUser(t)
+
+	task := createRandomTask(t, user)
+
+	trashed, err := testQueries.TrashTask(context.Background(), TrashTaskParams{ID: task.ID, UserID: user.ID})
+
+	require.NoError(t, err)
+
+	require.True(t, trashed.DeletedAt.Valid)
+
+	//Trashed tasks are hidden from the regular read queries
+
+	_, err = testQueries.GetTaskByID(context.Background(), task.ID)
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	tasks, err := testQueries.GetTasksByUser(context.Background(), GetTasksByUserParams{UserID: user.ID})
+
+	require.NoError(t, err)
+
+	require.Empty(t, tasks)
+
+	trash, err := testQueries.GetTrashedTasksByUser(context.Background(), user.ID)
+
+	require.NoError(t, err)
+
+	require.Len(t, trash, 1)
+
+	require.Equal(t, task.ID, trash[0].ID)
+
+	restored, err := testQueries.RestoreTask(context.Background(), RestoreTaskParams{ID: task.ID, UserID: user.ID})
+
+	require.NoError(t, err)
+
+	require.False(t, restored.DeletedAt.Valid)
+
+	_, err = testQueries.GetTaskByID(context.Background(), task.ID)
+
+	require.NoError(t, err)
+}
+
+func TestTrashTaskOfOtherUser(t *testing.T) {
+	owner := createRandomUser(t)
+
+	other := createRandomUser(t)
+
+	task := createRandomTask(t, owner)
+
+	_, err := testQueries.TrashTask(context.Background(), TrashTaskParams{ID: task.ID, UserID: other.ID})
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPurgeTrashedTasks(t *testing.T) {
+	user := createRandomUser(t)
+
+	task := createRandomTask(t, user)
+
+	_, err := testQueries.TrashTask(context.Background(), TrashTaskParams{ID: task.ID, UserID: user.ID})
+
+	require.NoError(t, err)
+
+	purged, err := testQueries.PurgeTrashedTasks(context.Background(), time.Now().Add(time.Minute))
+
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, purged, int64(1))
+
+	trash, err := testQueries.GetTrashedTasksByUser(context.Background(), user.ID)
+
+	require.NoError(t, err)
+
+	require.Empty(t, trash)
+}
+
+func TestCompleteAndReopenTask(t *testing.T) {
+	user := createRandomUser(t)
+
+	task := createRandomTask(t, user)
+
+	require.Equal(t, TaskStatusOpen, task.Status)
+
+	require.False(t, task.CompletedAt.Valid)
+
+	done, err := testQueries.CompleteTask(context.Background(), task.ID)
+
+	require.NoError(t, err)
+
+	require.Equal(t, TaskStatusDone, done.Status)
+
+	require.True(t, done.CompletedAt.Valid)
+
+	reopened, err := testQueries.ReopenTask(context.Background(), task.ID)
+
+	require.NoError(t, err)
+
+	require.Equal(t, TaskStatusOpen, reopened.Status)
+
+	require.False(t, reopened.CompletedAt.Valid)
+}
+
+func TestGetTasksByUserStatusFilter(t *testing.T) {
+	user := createRandomUser(t)
+
+	open := createRandomTask(t, user)
+
+	done := createRandomTask(t, user)
+
+	_, err := testQueries.CompleteTask(context.Background(), done.ID)
+
+	require.NoError(t, err)
+
+	arg := GetTasksByUserParams {
+		UserID: user.ID,
+		Status: NullTaskStatus{TaskStatus: TaskStatusOpen, Valid: true},
+	}
+
+	tasks, err := testQueries.GetTasksByUser(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.Len(t, tasks, 1)
+
+	require.Equal(t, open.ID, tasks[0].ID)
+}
+
+func TestGetOpenTasksByUser(t *testing.T) {
+	user := createRandomUser(t)
+
+	open := createRandomTask(t, user)
+
+	done := createRandomTask(t, user)
+
+	_, err := testQueries.CompleteTask(context.Background(), done.ID)
+
+	require.NoError(t, err)
+
+	arg := UpdateTaskParams {
+		ID: open.ID,
+		Priority: NullTaskPriority{TaskPriority: TaskPriorityHigh, Valid: true},
+		Important: sql.NullBool{Bool: true, Valid: true},
+	}
+
+	updated, err := testQueries.UpdateTask(context.Background(), arg)
+
+	require.NoError(t, err)
+
+	require.Equal(t, TaskPriorityHigh, updated.Priority)
+
+	require.True(t, updated.Important)
+
+	tasks, err := testQueries.GetOpenTasksByUser(context.Background(), user.ID)
+
+	require.NoError(t, err)
+
+	require.Len(t, tasks, 1)
+
+	require.Equal(t, open.ID, tasks[0].ID)
+}
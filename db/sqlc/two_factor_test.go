@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTOTPCredential(t *testing.T, user User) TotpCredential {
+	arg := UpsertTOTPCredentialParams {
+		UserID: user.ID,
+		Secret: util.RandomString(32),
+	}
+
+	credential, err := testQueries.UpsertTOTPCredential(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Secret, credential.Secret)
+	require.False(t, credential.ConfirmedAt.Valid)
+
+	return credential
+}
+
+func randomRecoveryCodeHashes(n int) []string {
+	hashes := make([]string, n)
+
+	for i := range hashes {
+		hashes[i] = util.HashSecretToken(util.RandomString(16))
+	}
+
+	return hashes
+}
+
+func TestEnableTOTPTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	//Enrolling again before confirming replaces the secret
+	createRandomTOTPCredential(t, user)
+	credential := createRandomTOTPCredential(t, user)
+
+	hashes := randomRecoveryCodeHashes(3)
+
+	err := store.EnableTOTPTx(context.Background(), EnableTOTPTxParams {
+		UserID: user.ID,
+		Step: 100,
+		RecoveryCodeHashes: hashes,
+	})
+	require.NoError(t, err)
+
+	enabled, err := testQueries.GetTOTPCredential(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, credential.Secret, enabled.Secret)
+	require.True(t, enabled.ConfirmedAt.Valid)
+	require.Equal(t, int64(100), enabled.LastUsedStep)
+
+	left, err := testQueries.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(hashes)), left)
+
+	//Once confirmed the secret can be neither replaced nor confirmed again
+
+	_, err = testQueries.UpsertTOTPCredential(context.Background(), UpsertTOTPCredentialParams {
+		UserID: user.ID,
+		Secret: util.RandomString(32),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = store.EnableTOTPTx(context.Background(), EnableTOTPTxParams{UserID: user.ID, Step: 200})
+	require.ErrorIs(t, err, ErrTOTPNotPending)
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	createRandomTOTPCredential(t, user)
+
+	arg := UseTOTPStepParams{UserID: user.ID, Step: 101}
+
+	//Codes don't count until the secret is confirmed
+	used, err := testQueries.UseTOTPStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	err = store.EnableTOTPTx(context.Background(), EnableTOTPTxParams{UserID: user.ID, Step: 100})
+	require.NoError(t, err)
+
+	used, err = testQueries.UseTOTPStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	//A step is used once, and earlier ones can't be used after it
+
+	used, err = testQueries.UseTOTPStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	used, err = testQueries.UseTOTPStep(context.Background(), UseTOTPStepParams{UserID: user.ID, Step: 100})
+	require.NoError(t, err)
+	require.Zero(t, used)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	createRandomTOTPCredential(t, user)
+
+	hashes := randomRecoveryCodeHashes(2)
+
+	err := store.EnableTOTPTx(context.Background(), EnableTOTPTxParams {
+		UserID: user.ID,
+		Step: 1,
+		RecoveryCodeHashes: hashes,
+	})
+	require.NoError(t, err)
+
+	arg := UseRecoveryCodeParams{UserID: user.ID, CodeHash: hashes[0]}
+
+	used, err := testQueries.UseRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	used, err = testQueries.UseRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	//Codes of other users don't work
+	other := createRandomUser(t)
+
+	used, err = testQueries.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{UserID: other.ID, CodeHash: hashes[1]})
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	//Replacing the codes drops the old ones, used or not
+
+	fresh := randomRecoveryCodeHashes(3)
+
+	err = store.ReplaceRecoveryCodesTx(context.Background(), ReplaceRecoveryCodesTxParams{UserID: user.ID, RecoveryCodeHashes: fresh})
+	require.NoError(t, err)
+
+	used, err = testQueries.UseRecoveryCode(context.Background(), UseRecoveryCodeParams{UserID: user.ID, CodeHash: hashes[1]})
+	require.NoError(t, err)
+	require.Zero(t, used)
+
+	left, err := testQueries.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(fresh)), left)
+}
+
+func TestDisableTOTPTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	createRandomTOTPCredential(t, user)
+
+	err := store.EnableTOTPTx(context.Background(), EnableTOTPTxParams {
+		UserID: user.ID,
+		Step: 1,
+		RecoveryCodeHashes: randomRecoveryCodeHashes(2),
+	})
+	require.NoError(t, err)
+
+	err = store.DisableTOTPTx(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTOTPCredential(context.Background(), user.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	left, err := testQueries.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Zero(t, left)
+}
+
+func TestLoginChallengeAttempts(t *testing.T) {
+	user := createRandomUser(t)
+
+	challenge, err := testQueries.CreateLoginChallenge(context.Background(), CreateLoginChallengeParams {
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(util.RandomString(32)),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Zero(t, challenge.Attempts)
+
+	count, err := testQueries.CountLoginChallengesSince(context.Background(), CountLoginChallengesSinceParams {
+		UserID: user.ID,
+		CreatedAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	arg := ClaimLoginChallengeAttemptParams{ID: challenge.ID, MaxAttempts: 2}
+
+	for i := 0; i < 2; i++ {
+		claimed, err := testQueries.ClaimLoginChallengeAttempt(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), claimed)
+	}
+
+	claimed, err := testQueries.ClaimLoginChallengeAttempt(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, claimed)
+
+	used, err := testQueries.UseLoginChallenge(context.Background(), challenge.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), used)
+
+	used, err = testQueries.UseLoginChallenge(context.Background(), challenge.ID)
+	require.NoError(t, err)
+	require.Zero(t, used)
+}
+
+func TestLoginChallengeExpired(t *testing.T) {
+	user := createRandomUser(t)
+
+	challenge, err := testQueries.CreateLoginChallenge(context.Background(), CreateLoginChallengeParams {
+		UserID: user.ID,
+		TokenHash: util.HashSecretToken(util.RandomString(32)),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	claimed, err := testQueries.ClaimLoginChallengeAttempt(context.Background(), ClaimLoginChallengeAttemptParams{ID: challenge.ID, MaxAttempts: 5})
+	require.NoError(t, err)
+	require.Zero(t, claimed)
+}
+
+func TestClaimTOTPAttempt(t *testing.T) {
+	user := createRandomUser(t)
+
+	createRandomTOTPCredential(t, user)
+
+	arg := ClaimTOTPAttemptParams {
+		WindowStart: time.Now().Add(-time.Minute),
+		UserID: user.ID,
+		MaxAttempts: 2,
+	}
+
+	for i := 0; i < 2; i++ {
+		claimed, err := testQueries.ClaimTOTPAttempt(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), claimed)
+	}
+
+	claimed, err := testQueries.ClaimTOTPAttempt(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, claimed)
+
+	//Attempts made before the window don't count
+	later := arg
+	later.WindowStart = time.Now().Add(time.Minute)
+
+	claimed, err = testQueries.ClaimTOTPAttempt(context.Background(), later)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), claimed)
+
+	credential, err := testQueries.GetTOTPCredential(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), credential.FailedAttempts)
+
+	//An accepted code starts the count over
+	err = testQueries.ResetTOTPAttempts(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	claimed, err = testQueries.ClaimTOTPAttempt(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), claimed)
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"m1thrandir225/your_time/util"
+	"time"
+)
+
+// Location is the timezone of the user. An unknown zone falls back to UTC.
+func (user User) Location() *time.Location {
+	return util.LocationOrUTC(user.Timezone)
+}
+
+// QuietHours combines the quiet hour windows of the user, read in their
+// timezone, with their do-not-disturb.
+func (user User) QuietHours(windows []QuietHour) util.QuietHours {
+	quiet := util.QuietHours {
+		Location: user.Location(),
+		Windows: make([]util.QuietWindow, len(windows)),
+	}
+
+	for i, window := range windows {
+		quiet.Windows[i] = util.QuietWindow {
+			Weekday: time.Weekday(window.Weekday),
+			Start: int(window.StartMinute),
+			End: int(window.EndMinute),
+		}
+	}
+
+	if user.DndUntil.Valid {
+		quiet.DoNotDisturbUntil = user.DndUntil.Time
+	}
+
+	return quiet
+}
@@ -7,10 +7,36 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const claimVerificationEmail = `-- name: ClaimVerificationEmail :execrows
+UPDATE users
+SET verification_sent_at = NOW()
+WHERE id = $1
+AND email_verified_at IS NULL
+AND (verification_sent_at IS NULL OR verification_sent_at < $2)
+`
+
+type ClaimVerificationEmailParams struct {
+	ID         uuid.UUID `json:"id"`
+	SentBefore time.Time `json:"sent_before"`
+}
+
+func (q *Queries) ClaimVerificationEmail(ctx context.Context, arg ClaimVerificationEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimVerificationEmail,
+		arg.ID,
+		arg.SentBefore,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     first_name,
@@ -22,7 +48,7 @@ INSERT INTO users (
     $2,
     $3,
     $4
-) RETURNING id, first_name, last_name, email, password, created_at, updated_at
+) RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
 `
 
 type CreateUserParams struct {
@@ -48,12 +74,16 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, first_name, last_name, email, password, created_at, updated_at FROM users 
+SELECT id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at FROM users 
 WHERE email = $1 LIMIT 1
 `
 
@@ -68,12 +98,16 @@ func (q *Queries) GetUser(ctx context.Context, email string) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, first_name, last_name, email, password, created_at, updated_at FROM users 
+SELECT id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at FROM users 
 WHERE id = $1 LIMIT 1
 `
 
@@ -88,6 +122,197 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const setDoNotDisturb = `-- name: SetDoNotDisturb :one
+UPDATE users
+SET
+    dnd_until = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
+`
+
+type SetDoNotDisturbParams struct {
+	DndUntil sql.NullTime `json:"dnd_until"`
+	ID       uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) SetDoNotDisturb(ctx context.Context, arg SetDoNotDisturbParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setDoNotDisturb,
+		arg.DndUntil,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET
+    first_name = COALESCE($1, first_name),
+    last_name = COALESCE($2, last_name),
+    timezone = COALESCE($3, timezone),
+    updated_at = NOW()
+WHERE id = $4
+RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
+`
+
+type UpdateUserParams struct {
+	FirstName sql.NullString `json:"first_name"`
+	LastName  sql.NullString `json:"last_name"`
+	Timezone  sql.NullString `json:"timezone"`
+	ID        uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.FirstName,
+		arg.LastName,
+		arg.Timezone,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET
+    email = $1,
+    email_verified_at = NULL,
+    verification_sent_at = NULL,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
+`
+
+type UpdateUserEmailParams struct {
+	Email string    `json:"email"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserEmail,
+		arg.Email,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET
+    password = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
+`
+
+type UpdateUserPasswordParams struct {
+	Password string    `json:"password"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword,
+		arg.Password,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE id = $1
+AND email = $2
+RETURNING id, first_name, last_name, email, password, created_at, updated_at, timezone, dnd_until, email_verified_at, verification_sent_at
+`
+
+type VerifyUserEmailParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail,
+		arg.ID,
+		arg.Email,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
+		&i.DndUntil,
+		&i.EmailVerifiedAt,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
@@ -2,10 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"m1thrandir225/your_time/util"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -91,3 +93,108 @@ func TestGetUserByEmail(t *testing.T) {
 	require.WithinDuration(t, user.UpdatedAt, user2.UpdatedAt, time.Second)
 
 }
+
+func TestUpdateUser(t *testing.T) {
+	user := createRandomUser(t)
+	require.Equal(t, "UTC", user.Timezone)
+
+	user2, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		ID: user.ID,
+		Timezone: sql.NullString{String: "Europe/Skopje", Valid: true},
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, "Europe/Skopje", user2.Timezone)
+
+	require.Equal(t, user.FirstName, user2.FirstName)
+
+	require.Equal(t, user.LastName, user2.LastName)
+}
+
+func TestVerifyUserEmail(t *testing.T) {
+	user := createRandomUser(t)
+	require.False(t, user.EmailVerifiedAt.Valid)
+
+	//A link sent to another address doesn't verify the current one
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams {
+		ID: user.ID,
+		Email: util.RandomEmail(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	verified, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams {
+		ID: user.ID,
+		Email: user.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, verified.EmailVerifiedAt.Valid)
+
+	//Opening the link again keeps the first verification
+	again, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams {
+		ID: user.ID,
+		Email: user.Email,
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, verified.EmailVerifiedAt.Time, again.EmailVerifiedAt.Time, time.Microsecond)
+}
+
+func TestClaimVerificationEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := ClaimVerificationEmailParams {
+		ID: user.ID,
+		SentBefore: time.Now().Add(-time.Minute),
+	}
+
+	claimed, err := testQueries.ClaimVerificationEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), claimed)
+
+	//Throttled until a minute has passed
+	claimed, err = testQueries.ClaimVerificationEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), claimed)
+
+	_, err = testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{ID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	//Verified emails get no more links
+	arg.SentBefore = time.Now().Add(time.Minute)
+
+	claimed, err = testQueries.ClaimVerificationEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), claimed)
+}
+
+func TestChangeEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{ID: user.ID, Email: user.Email})
+	require.NoError(t, err)
+
+	session := createRandomSession(t, user, uuid.New())
+
+	arg := ChangeEmailTxParams {
+		UserID: user.ID,
+		Email: util.RandomEmail(),
+	}
+
+	changed, err := store.ChangeEmailTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Email, changed.Email)
+	require.False(t, changed.EmailVerifiedAt.Valid)
+	require.False(t, changed.VerificationSentAt.Valid)
+
+	session, err = testQueries.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, session.IsBlocked)
+
+	//Emails stay unique
+	other := createRandomUser(t)
+
+	_, err = store.ChangeEmailTx(context.Background(), ChangeEmailTxParams{UserID: other.ID, Email: arg.Email})
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"m1thrandir225/your_time/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, user User, eventTypes ...string) WebhookEndpoint {
+	arg := CreateWebhookEndpointParams {
+		UserID: user.ID,
+		Url: "https://example.com/" + util.RandomString(8),
+		Secret: "whsec_" + util.RandomString(32),
+		EventTypes: eventTypes,
+	}
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Url, endpoint.Url)
+	require.Equal(t, arg.EventTypes, endpoint.EventTypes)
+	require.True(t, endpoint.Active)
+	require.Zero(t, endpoint.FailureCount)
+
+	return endpoint
+}
+
+func TestEnqueueWebhookEvent(t *testing.T) {
+	user := createRandomUser(t)
+
+	subscribed := createRandomWebhookEndpoint(t, user, "task.created", "task.updated")
+	other := createRandomWebhookEndpoint(t, user, "reminder.due")
+
+	arg := EnqueueWebhookEventParams {
+		EventID: uuid.New(),
+		EventType: "task.created",
+		Payload: json.RawMessage(`{"type":"task.created"}`),
+		UserID: user.ID,
+	}
+
+	queued, err := testQueries.EnqueueWebhookEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), queued)
+
+	//The same event is only queued once per endpoint
+	queued, err = testQueries.EnqueueWebhookEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, queued)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{EndpointID: subscribed.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, arg.EventID, deliveries[0].EventID)
+	require.JSONEq(t, string(arg.Payload), string(deliveries[0].Payload))
+
+	deliveries, err = testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{EndpointID: other.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestRecordWebhookFailureDisablesEndpoint(t *testing.T) {
+	user := createRandomUser(t)
+
+	endpoint := createRandomWebhookEndpoint(t, user, "task.created")
+
+	arg := RecordWebhookFailureParams{ID: endpoint.ID, MaxFailures: 2}
+
+	endpoint, err := testQueries.RecordWebhookFailure(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, endpoint.Active)
+	require.Equal(t, int32(1), endpoint.FailureCount)
+
+	endpoint, err = testQueries.RecordWebhookFailure(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, endpoint.Active)
+	require.True(t, endpoint.DisabledAt.Valid)
+
+	//Disabled endpoints don't get new events
+	queued, err := testQueries.EnqueueWebhookEvent(context.Background(), EnqueueWebhookEventParams{
+		EventID: uuid.New(),
+		EventType: "task.created",
+		Payload: json.RawMessage(`{}`),
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, queued)
+
+	endpoint, err = testQueries.UpdateWebhookEndpoint(context.Background(), UpdateWebhookEndpointParams{
+		ID: endpoint.ID,
+		Active: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, endpoint.Active)
+	require.Zero(t, endpoint.FailureCount)
+	require.False(t, endpoint.DisabledAt.Valid)
+}
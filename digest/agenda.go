@@ -0,0 +1,164 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxSectionItems caps how many tasks a section of the digest lists.
+const maxSectionItems = 20
+
+// Reminder is a reminder that fires within the period of the digest.
+type Reminder struct {
+	Task db.Task
+	RemindAt time.Time
+}
+
+// Agenda is what a digest tells a user about: open tasks that are overdue,
+// tasks due within the period and reminders that fire within it. The period
+// starts at local midnight and is one day long, or a week for weekly digests.
+type Agenda struct {
+	Weekly bool
+	Location *time.Location
+	Start time.Time
+	End time.Time
+	Overdue []db.Task
+	Due []db.Task
+	Reminders []Reminder
+}
+
+// Build sorts the open tasks of a user and their reminders into an agenda
+// for the period that contains now.
+func Build(frequency db.DigestFrequency, loc *time.Location, now time.Time, tasks []db.Task, reminders []db.TaskReminder) Agenda {
+	local := now.In(loc)
+
+	agenda := Agenda {
+		Weekly: frequency == db.DigestFrequencyWeekly,
+		Location: loc,
+		Start: time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc),
+		Overdue: []db.Task{},
+		Due: []db.Task{},
+		Reminders: []Reminder{},
+	}
+
+	days := 1
+	if agenda.Weekly {
+		days = 7
+	}
+
+	agenda.End = time.Date(local.Year(), local.Month(), local.Day() + days, 0, 0, 0, 0, loc)
+
+	open := make(map[uuid.UUID]db.Task)
+
+	for _, task := range tasks {
+		open[task.ID] = task
+
+		switch {
+		case task.DueDate.Before(agenda.Start):
+			agenda.Overdue = append(agenda.Overdue, task)
+		case task.DueDate.Before(agenda.End):
+			agenda.Due = append(agenda.Due, task)
+		}
+	}
+
+	for _, reminder := range reminders {
+		task, ok := open[reminder.TaskID]
+
+		if !ok || reminder.DismissedAt.Valid || reminder.RemindAt.Before(now) || !reminder.RemindAt.Before(agenda.End) {
+			continue
+		}
+
+		agenda.Reminders = append(agenda.Reminders, Reminder{Task: task, RemindAt: reminder.RemindAt})
+	}
+
+	return agenda
+}
+
+// Load builds the agenda of user from their open tasks.
+func Load(ctx context.Context, store db.Querier, user db.User, frequency db.DigestFrequency, now time.Time) (Agenda, error) {
+	tasks, err := store.GetOpenTasksByUser(ctx, user.ID)
+
+	if err != nil {
+		return Agenda{}, err
+	}
+
+	reminders := []db.TaskReminder{}
+
+	if len(tasks) > 0 {
+		ids := make([]uuid.UUID, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+
+		reminders, err = store.GetRemindersForTasks(ctx, ids)
+
+		if err != nil {
+			return Agenda{}, err
+		}
+	}
+
+	return Build(frequency, user.Location(), now, tasks, reminders), nil
+}
+
+// Empty reports whether there is nothing to tell the user about.
+func (agenda Agenda) Empty() bool {
+	return len(agenda.Overdue) == 0 && len(agenda.Due) == 0 && len(agenda.Reminders) == 0
+}
+
+// Summary is a one line overview of the agenda, used for the inbox.
+func (agenda Agenda) Summary() string {
+	period := "today"
+	if agenda.Weekly {
+		period = "this week"
+	}
+
+	parts := []string{
+		fmt.Sprintf("%d overdue", len(agenda.Overdue)),
+		fmt.Sprintf("%d due %s", len(agenda.Due), period),
+		fmt.Sprintf("%d upcoming reminder", len(agenda.Reminders)),
+	}
+
+	if len(agenda.Reminders) != 1 {
+		parts[2] += "s"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Message renders the agenda as the digest email of user.
+func (agenda Agenda) Message(user db.User) (notify.Message, error) {
+	data := notify.DigestData {
+		FirstName: user.FirstName,
+		Weekly: agenda.Weekly,
+		Date: agenda.Start,
+	}
+
+	for i, task := range agenda.Overdue {
+		addItem(&data.Overdue, i, task.Title, task.DueDate.In(agenda.Location))
+	}
+
+	for i, task := range agenda.Due {
+		addItem(&data.Due, i, task.Title, task.DueDate.In(agenda.Location))
+	}
+
+	for i, reminder := range agenda.Reminders {
+		addItem(&data.Reminders, i, reminder.Task.Title, reminder.RemindAt.In(agenda.Location))
+	}
+
+	return notify.DigestMessage(user.Email, data)
+}
+
+func addItem(section *notify.DigestSection, i int, title string, at time.Time) {
+	if i >= maxSectionItems {
+		section.More++
+		return
+	}
+
+	section.Items = append(section.Items, notify.DigestItem{Title: title, At: at})
+}
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"database/sql"
+	db "m1thrandir225/your_time/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAgenda(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	//08:00 on Monday in New York
+	now := time.Date(2024, 5, 13, 8, 0, 0, 0, newYork)
+
+	task := func(title string, due time.Time) db.Task {
+		return db.Task{ID: uuid.New(), Title: title, DueDate: due, Status: db.TaskStatusOpen}
+	}
+
+	overdue := task("overdue", time.Date(2024, 5, 12, 23, 0, 0, 0, newYork))
+	earlier := task("earlier today", time.Date(2024, 5, 13, 7, 0, 0, 0, newYork))
+	tonight := task("tonight", time.Date(2024, 5, 13, 23, 30, 0, 0, newYork))
+	friday := task("friday", time.Date(2024, 5, 17, 12, 0, 0, 0, newYork))
+
+	tasks := []db.Task{overdue, earlier, tonight, friday}
+
+	reminders := []db.TaskReminder{
+		{ID: uuid.New(), TaskID: tonight.ID, RemindAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), TaskID: tonight.ID, RemindAt: now.Add(time.Hour)},
+		{ID: uuid.New(), TaskID: tonight.ID, RemindAt: now.Add(2 * time.Hour), DismissedAt: sql.NullTime{Time: now, Valid: true}},
+		{ID: uuid.New(), TaskID: friday.ID, RemindAt: friday.DueDate.Add(-time.Hour)},
+		{ID: uuid.New(), TaskID: uuid.New(), RemindAt: now.Add(time.Hour)},
+	}
+
+	agenda := Build(db.DigestFrequencyDaily, newYork, now, tasks, reminders)
+
+	require.Equal(t, []db.Task{overdue}, agenda.Overdue)
+	require.Equal(t, []db.Task{earlier, tonight}, agenda.Due)
+	require.Len(t, agenda.Reminders, 1)
+	require.Equal(t, tonight.ID, agenda.Reminders[0].Task.ID)
+	require.Equal(t, "1 overdue, 2 due today, 1 upcoming reminder", agenda.Summary())
+
+	agenda = Build(db.DigestFrequencyWeekly, newYork, now, tasks, reminders)
+
+	require.Equal(t, []db.Task{earlier, tonight, friday}, agenda.Due)
+	require.Len(t, agenda.Reminders, 2)
+	require.Equal(t, time.Date(2024, 5, 20, 0, 0, 0, 0, newYork), agenda.End)
+}
+
+func TestAgendaMessage(t *testing.T) {
+	now := time.Date(2024, 5, 13, 8, 0, 0, 0, time.UTC)
+
+	tasks := []db.Task{}
+	for i := 0; i < maxSectionItems + 5; i++ {
+		tasks = append(tasks, db.Task{ID: uuid.New(), Title: "late", DueDate: now.AddDate(0, 0, -1)})
+	}
+
+	agenda := Build(db.DigestFrequencyDaily, time.UTC, now, tasks, nil)
+
+	msg, err := agenda.Message(db.User{FirstName: "Jane", Email: "jane@example.com"})
+
+	require.NoError(t, err)
+	require.Equal(t, "jane@example.com", msg.To)
+	require.Equal(t, "Your agenda for Mon, 13 May", msg.Subject)
+	require.Contains(t, msg.Text, "...and 5 more")
+}
@@ -0,0 +1,58 @@
+package digest
+
+import (
+	"database/sql"
+	db "m1thrandir225/your_time/db/sqlc"
+	"time"
+)
+
+// MaxLateness is how late a digest can still be sent, e.g. after the
+// scheduler was down. A morning digest arriving in the evening is skipped.
+const MaxLateness = 6 * time.Hour
+
+// Schedule is when a user gets their digest: every day, or every week on
+// Weekday, at Hour in Location.
+type Schedule struct {
+	Frequency db.DigestFrequency
+	Hour int
+	Weekday time.Weekday
+	Location *time.Location
+}
+
+// Last returns the latest time at or before now a digest was scheduled for.
+// The hour is local, so a digest at 08:00 stays at 08:00 across DST changes.
+func (schedule Schedule) Last(now time.Time) time.Time {
+	local := now.In(schedule.Location)
+
+	day := local.Day()
+	step := 1
+
+	if schedule.Frequency == db.DigestFrequencyWeekly {
+		day -= (int(local.Weekday()) - int(schedule.Weekday) + 7) % 7
+		step = 7
+	}
+
+	at := time.Date(local.Year(), local.Month(), day, schedule.Hour, 0, 0, 0, schedule.Location)
+
+	if at.After(now) {
+		at = time.Date(local.Year(), local.Month(), day - step, schedule.Hour, 0, 0, 0, schedule.Location)
+	}
+
+	return at
+}
+
+// Due reports whether the digest last scheduled before now still has to be
+// sent, given when the previous one went out.
+func (schedule Schedule) Due(now time.Time, lastSentAt sql.NullTime) (scheduledAt time.Time, due bool) {
+	if schedule.Frequency != db.DigestFrequencyDaily && schedule.Frequency != db.DigestFrequencyWeekly {
+		return time.Time{}, false
+	}
+
+	scheduledAt = schedule.Last(now)
+
+	if now.Sub(scheduledAt) > MaxLateness {
+		return scheduledAt, false
+	}
+
+	return scheduledAt, !lastSentAt.Valid || lastSentAt.Time.Before(scheduledAt)
+}
@@ -0,0 +1,56 @@
+package digest
+
+import (
+	"database/sql"
+	db "m1thrandir225/your_time/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleDaily(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	schedule := Schedule{Frequency: db.DigestFrequencyDaily, Hour: 8, Location: tokyo}
+
+	//07:30 in Tokyo, today's digest isn't due yet
+	now := time.Date(2024, 5, 14, 7, 30, 0, 0, tokyo)
+	require.Equal(t, time.Date(2024, 5, 13, 8, 0, 0, 0, tokyo), schedule.Last(now))
+
+	_, due := schedule.Due(now, sql.NullTime{})
+	require.False(t, due, "yesterday's digest is too late to send")
+
+	now = time.Date(2024, 5, 14, 8, 5, 0, 0, tokyo)
+	scheduledAt, due := schedule.Due(now, sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true})
+	require.True(t, due)
+	require.Equal(t, time.Date(2024, 5, 14, 8, 0, 0, 0, tokyo), scheduledAt)
+
+	_, due = schedule.Due(now, sql.NullTime{Time: now.Add(-time.Minute), Valid: true})
+	require.False(t, due, "already sent")
+}
+
+func TestScheduleWeekly(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	//Monday at 07:00, the clocks in Berlin moved forward on Sunday 2024-03-31
+	schedule := Schedule{Frequency: db.DigestFrequencyWeekly, Hour: 7, Weekday: time.Monday, Location: berlin}
+
+	now := time.Date(2024, 4, 1, 7, 30, 0, 0, berlin)
+	require.Equal(t, time.Date(2024, 4, 1, 5, 0, 0, 0, time.UTC), schedule.Last(now).UTC())
+
+	now = time.Date(2024, 4, 1, 6, 0, 0, 0, berlin)
+	require.Equal(t, time.Date(2024, 3, 25, 6, 0, 0, 0, time.UTC), schedule.Last(now).UTC())
+
+	now = time.Date(2024, 4, 4, 12, 0, 0, 0, berlin)
+	require.Equal(t, time.Date(2024, 4, 1, 7, 0, 0, 0, berlin), schedule.Last(now))
+}
+
+func TestScheduleOff(t *testing.T) {
+	schedule := Schedule{Frequency: db.DigestFrequencyOff, Location: time.UTC}
+
+	_, due := schedule.Due(time.Now(), sql.NullTime{})
+	require.False(t, due)
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"m1thrandir225/your_time/api"
 	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/stream"
+	"m1thrandir225/your_time/token"
 	"m1thrandir225/your_time/util"
+	"m1thrandir225/your_time/worker"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -25,13 +34,133 @@ func main() {
 
 	store := db.NewStore(conn)
 
-	server, err := api.NewServer(config, store)
+	//Workers and the server stop on SIGINT or SIGTERM
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//Live event streams are served from memory, by the instance the client is connected to
+
+	events := stream.NewBroker(config.EventsReplaySize)
+
+	//Trashed tasks are kept forever unless a retention is configured
+
+	if config.TrashRetention > 0 {
+		interval := config.TrashPurgeInterval
+		if interval <= 0 {
+			interval = time.Hour
+		}
+
+		purger := worker.NewTrashPurger(store, config.TrashRetention, interval)
+		go purger.Start(ctx)
+	}
+
+	//Reminders are polled for and retried with a doubling backoff
+
+	pollInterval := config.ReminderPollInterval
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+
+	maxAttempts := config.ReminderMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	retryBackoff := config.ReminderRetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = time.Minute
+	}
+
+	//Reminders are only logged until an SMTP server is configured
+
+	var notifier worker.Notifier = worker.LogNotifier{}
+	var mailer notify.Notifier
+
+	if config.SMTPHost != "" {
+		mailer, err = notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host: config.SMTPHost,
+			Port: config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From: config.SMTPFrom,
+			TLSMode: config.SMTPTLSMode,
+		})
+
+		if err != nil {
+			log.Fatal("cannot create smtp notifier:", err)
+		}
+
+		notifier = worker.NewEmailNotifier(mailer)
+	}
+
+	dispatcher := worker.NewReminderDispatcher(store, notifier, events, pollInterval, maxAttempts, retryBackoff)
+	go dispatcher.Start(ctx)
+
+	//With the defaults a failing webhook is retried for about an hour
+
+	webhookInterval := config.WebhookPollInterval
+	if webhookInterval <= 0 {
+		webhookInterval = 10 * time.Second
+	}
+
+	webhookAttempts := config.WebhookMaxAttempts
+	if webhookAttempts <= 0 {
+		webhookAttempts = 8
+	}
+
+	webhookBackoff := config.WebhookRetryBackoff
+	if webhookBackoff <= 0 {
+		webhookBackoff = 30 * time.Second
+	}
+
+	webhookFailures := config.WebhookMaxFailures
+	if webhookFailures <= 0 {
+		webhookFailures = 20
+	}
+
+	webhooks := worker.NewWebhookDispatcher(store, nil, webhookInterval, webhookAttempts, webhookBackoff, webhookFailures)
+	go webhooks.Start(ctx)
+
+	//Digests always go to the inbox, and by email once SMTP is configured
+
+	digestInterval := config.DigestPollInterval
+	if digestInterval <= 0 {
+		digestInterval = 5 * time.Minute
+	}
+
+	digests := worker.NewDigestScheduler(store, mailer, digestInterval)
+	go digests.Start(ctx)
+
+	//Users stay signed in for a week unless configured otherwise
+
+	if config.RefreshTokenDuration <= 0 {
+		config.RefreshTokenDuration = 7 * 24 * time.Hour
+	}
+
+	//Links in emails point at the web app
+
+	if config.AppURL == "" {
+		config.AppURL = "http://localhost:3000"
+	}
+
+	//Revoked tokens are checked in memory and synced from the database
+
+	revocationInterval := config.RevocationSyncInterval
+	if revocationInterval <= 0 {
+		revocationInterval = 10 * time.Second
+	}
+
+	revocations := token.NewRevocationList(store)
+	go revocations.Start(ctx, revocationInterval)
+
+	server, err := api.NewServer(config, store, events, revocations, mailer)
 	
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
 
-	err = server.Start(config.ServerAddress)
+	err = server.Start(ctx, config.ServerAddress)
 
 	if err != nil {
 		log.Fatal("cannot start server:", err)
@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type receivedMail struct {
+	From string
+	To []string
+	Data string
+	TLS bool
+}
+
+// fakeSMTPServer speaks just enough SMTP for net/smtp to deliver a message.
+type fakeSMTPServer struct {
+	listener net.Listener
+	tlsMode string
+	tlsConfig *tls.Config
+	//roots trusts the certificate of the server
+	roots *x509.CertPool
+	username string
+	password string
+
+	mu sync.Mutex
+	mails []receivedMail
+}
+
+func newFakeSMTPServer(t *testing.T, tlsMode string, username string, password string) *fakeSMTPServer {
+	cert, roots := selfSignedCert(t)
+
+	server := &fakeSMTPServer {
+		tlsMode: tlsMode,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		roots: roots,
+		username: username,
+		password: password,
+	}
+
+	var err error
+
+	if tlsMode == TLSModeTLS {
+		server.listener, err = tls.Listen("tcp", "127.0.0.1:0", server.tlsConfig)
+	} else {
+		server.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+
+	require.NoError(t, err)
+
+	t.Cleanup(func() { server.listener.Close() })
+
+	go server.serve()
+
+	return server
+}
+
+func (server *fakeSMTPServer) port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (server *fakeSMTPServer) received() []receivedMail {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]receivedMail(nil), server.mails...)
+}
+
+func (server *fakeSMTPServer) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go server.handle(conn)
+	}
+}
+
+func (server *fakeSMTPServer) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	text := textproto.NewConn(conn)
+	isTLS := server.tlsMode == TLSModeTLS
+
+	var mail receivedMail
+
+	text.PrintfLine("220 localhost fake ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			lines := []string{"localhost"}
+			if server.tlsMode == TLSModeStartTLS && !isTLS {
+				lines = append(lines, "STARTTLS")
+			}
+			if server.username != "" {
+				lines = append(lines, "AUTH PLAIN")
+			}
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				text.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, server.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			isTLS = true
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			if string(decoded) == "\x00"+server.username+"\x00"+server.password {
+				text.PrintfLine("235 authenticated")
+			} else {
+				text.PrintfLine("535 invalid credentials")
+			}
+		case "MAIL":
+			mail = receivedMail{From: strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>"), TLS: isTLS}
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			mail.To = append(mail.To, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.Data = string(data)
+			server.mu.Lock()
+			server.mails = append(server.mails, mail)
+			server.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			text.PrintfLine("250 ok")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// selfSignedCert makes a certificate for 127.0.0.1 and a pool that trusts it.
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter: time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA: true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	parsed, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(parsed)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: parsed}, pool
+}
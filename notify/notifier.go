@@ -0,0 +1,19 @@
+package notify
+
+import (
+	"context"
+)
+
+// Message is a notification addressed to a single recipient. HTML is
+// optional, Text is always sent so every client can show something.
+type Message struct {
+	To string
+	Subject string
+	Text string
+	HTML string
+}
+
+// Notifier delivers messages over one channel, such as email.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
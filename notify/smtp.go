@@ -0,0 +1,276 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS modes of an SMTP connection.
+const (
+	//TLSModeNone talks plain text, which is only sensible for local relays
+	TLSModeNone = "none"
+	//TLSModeStartTLS upgrades a plain connection and fails if the server can't
+	TLSModeStartTLS = "starttls"
+	//TLSModeTLS connects over TLS from the start, usually on port 465
+	TLSModeTLS = "tls"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	//smtpTimeout bounds sending one email when ctx has no deadline of its own,
+	//so a stalled server can't hold up the caller forever
+	smtpTimeout = 30 * time.Second
+)
+
+var errStartTLSUnsupported = errors.New("smtp server doesn't support STARTTLS")
+
+type SMTPConfig struct {
+	Host string
+	Port int
+	Username string
+	Password string
+	From string
+	TLSMode string
+}
+
+// SMTPNotifier sends messages as email through an SMTP server.
+type SMTPNotifier struct {
+	config SMTPConfig
+	//from is the bare address of config.From, which may include a display name
+	from string
+	//tlsConfig overrides the TLS settings derived from the host, for tests
+	tlsConfig *tls.Config
+	//timeout is used when the context of Send has no deadline
+	timeout time.Duration
+}
+
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	if config.TLSMode == "" {
+		config.TLSMode = TLSModeStartTLS
+	}
+
+	switch config.TLSMode {
+	case TLSModeNone, TLSModeStartTLS, TLSModeTLS:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", config.TLSMode)
+	}
+
+	if config.Host == "" || config.From == "" {
+		return nil, errors.New("smtp host and from address are required")
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address: %w", err)
+	}
+
+	if config.Port == 0 {
+		config.Port = 587
+	}
+
+	return &SMTPNotifier{config: config, from: from.Address, timeout: smtpTimeout}, nil
+}
+
+func (notifier *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := msg.mime(notifier.config.From, notifier.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := notifier.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, notifier.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if notifier.config.TLSMode == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errStartTLSUnsupported
+		}
+
+		if err = client.StartTLS(notifier.tls()); err != nil {
+			return err
+		}
+	}
+
+	if notifier.config.Username != "" {
+		auth := smtp.PlainAuth("", notifier.config.Username, notifier.config.Password, notifier.config.Host)
+
+		if err = client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(notifier.from); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (notifier *SMTPNotifier) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(notifier.config.Host, strconv.Itoa(notifier.config.Port))
+
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+
+	if notifier.config.TLSMode == TLSModeTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: notifier.tls()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	//net/smtp has no context support, so the deadline of ctx bounds the whole conversation
+
+	deadline, ok := ctx.Deadline()
+
+	if !ok {
+		deadline = time.Now().Add(notifier.timeout)
+	}
+
+	conn.SetDeadline(deadline)
+
+	return conn, nil
+}
+
+func (notifier *SMTPNotifier) tls() *tls.Config {
+	if notifier.tlsConfig != nil {
+		return notifier.tlsConfig
+	}
+
+	return &tls.Config{ServerName: notifier.config.Host, MinVersion: tls.VersionTLS12}
+}
+
+// mime renders the message as a MIME email, with the HTML body as an
+// alternative to the text one when there is one.
+func (msg Message) mime(from string, fromAddress string, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from)
+	header.Set("To", msg.To)
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", now.Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID(fromAddress))
+	header.Set("MIME-Version", "1.0")
+
+	if msg.HTML == "" {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	header.Set("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+	writeHeader(&buf, header)
+	buf.Write(parts.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+func messageID(address string) string {
+	domain := "localhost"
+
+	if at := strings.LastIndexByte(address, '@'); at >= 0 {
+		domain = address[at+1:]
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
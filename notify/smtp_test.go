@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSMTPNotifier(t *testing.T, server *fakeSMTPServer, password string) *SMTPNotifier {
+	notifier, err := NewSMTPNotifier(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		Username: server.username,
+		Password: password,
+		From: "Your Time <noreply@yourtime.test>",
+		TLSMode: server.tlsMode,
+	})
+
+	require.NoError(t, err)
+
+	notifier.tlsConfig = &tls.Config{RootCAs: server.roots, ServerName: "127.0.0.1"}
+
+	return notifier
+}
+
+func testMessage() Message {
+	return Message {
+		To: "jane@example.com",
+		Subject: "Reminder: Pay rent ✓",
+		Text: "Pay the rent today.",
+		HTML: "<p>Pay the <strong>rent</strong> today.</p>",
+	}
+}
+
+func TestSMTPNotifierSend(t *testing.T) {
+	for _, mode := range []string{TLSModeNone, TLSModeStartTLS, TLSModeTLS} {
+		mode := mode
+
+		t.Run(mode, func(t *testing.T) {
+			server := newFakeSMTPServer(t, mode, "mailer", "secret")
+
+			notifier := newTestSMTPNotifier(t, server, "secret")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			require.NoError(t, notifier.Send(ctx, testMessage()))
+
+			mails := server.received()
+			require.Len(t, mails, 1)
+			require.Equal(t, "noreply@yourtime.test", mails[0].From)
+			require.Equal(t, []string{"jane@example.com"}, mails[0].To)
+			require.Equal(t, mode != TLSModeNone, mails[0].TLS)
+
+			requireMultipartBody(t, mails[0].Data)
+		})
+	}
+}
+
+func TestSMTPNotifierAuthFailure(t *testing.T) {
+	server := newFakeSMTPServer(t, TLSModeStartTLS, "mailer", "secret")
+
+	notifier := newTestSMTPNotifier(t, server, "wrong")
+
+	require.Error(t, notifier.Send(context.Background(), testMessage()))
+	require.Empty(t, server.received())
+}
+
+func TestSMTPNotifierStartTLSUnsupported(t *testing.T) {
+	server := newFakeSMTPServer(t, TLSModeNone, "", "")
+
+	notifier := newTestSMTPNotifier(t, server, "")
+	notifier.config.TLSMode = TLSModeStartTLS
+
+	require.ErrorIs(t, notifier.Send(context.Background(), testMessage()), errStartTLSUnsupported)
+	require.Empty(t, server.received())
+}
+
+func TestSMTPNotifierTextOnly(t *testing.T) {
+	server := newFakeSMTPServer(t, TLSModeNone, "", "")
+
+	notifier := newTestSMTPNotifier(t, server, "")
+
+	msg := testMessage()
+	msg.HTML = ""
+
+	require.NoError(t, notifier.Send(context.Background(), msg))
+
+	mails := server.received()
+	require.Len(t, mails, 1)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+	require.NoError(t, err)
+
+	mediaType, _, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "text/plain", mediaType)
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	require.NoError(t, err)
+	require.Equal(t, msg.Text, strings.TrimSpace(string(body)))
+}
+
+func TestSMTPNotifierStalledServer(t *testing.T) {
+	//Accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	notifier, err := NewSMTPNotifier(SMTPConfig {
+		Host: "127.0.0.1",
+		Port: listener.Addr().(*net.TCPAddr).Port,
+		From: "noreply@yourtime.test",
+		TLSMode: TLSModeNone,
+	})
+	require.NoError(t, err)
+	require.Equal(t, smtpTimeout, notifier.timeout)
+
+	notifier.timeout = 100 * time.Millisecond
+
+	start := time.Now()
+
+	//No deadline on the context
+	err = notifier.Send(context.Background(), testMessage())
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5 * time.Second)
+}
+
+func TestNewSMTPNotifierInvalidConfig(t *testing.T) {
+	_, err := NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "noreply@example.com", TLSMode: "ssl"})
+	require.Error(t, err)
+
+	_, err = NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "not an address"})
+	require.Error(t, err)
+
+	_, err = NewSMTPNotifier(SMTPConfig{From: "noreply@example.com"})
+	require.Error(t, err)
+
+	notifier, err := NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "noreply@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, TLSModeStartTLS, notifier.config.TLSMode)
+	require.Equal(t, 587, notifier.config.Port)
+}
+
+func requireMultipartBody(t *testing.T, data string) {
+	parsed, err := mail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Reminder: Pay rent ✓", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	var bodies []string
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		bodies = append(bodies, part.Header.Get("Content-Type")+"|"+string(body))
+	}
+
+	require.Equal(t, []string{
+		"text/plain; charset=utf-8|Pay the rent today.",
+		"text/html; charset=utf-8|<p>Pay the <strong>rent</strong> today.</p>",
+	}, bodies)
+}
@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// ReminderData is what the reminder templates render.
+type ReminderData struct {
+	FirstName string
+	TaskTitle string
+	Description string
+	DueDate time.Time
+}
+
+// ReminderMessage renders the reminder email for a task.
+func ReminderMessage(to string, data ReminderData) (Message, error) {
+	return render(to, "Reminder: "+data.TaskTitle, "reminder", data)
+}
+
+// DigestItem is a task or reminder listed in a digest, At is in the timezone of the user.
+type DigestItem struct {
+	Title string
+	At time.Time
+}
+
+// DigestSection lists up to a handful of items, More counts the ones left out.
+type DigestSection struct {
+	Items []DigestItem
+	More int
+}
+
+// DigestData is what the digest templates render.
+type DigestData struct {
+	FirstName string
+	Weekly bool
+	//Date is the first day the digest covers, in the timezone of the user
+	Date time.Time
+	Overdue DigestSection
+	Due DigestSection
+	Reminders DigestSection
+}
+
+// Empty reports whether there is nothing to tell the user about.
+func (data DigestData) Empty() bool {
+	return len(data.Overdue.Items) == 0 && len(data.Due.Items) == 0 && len(data.Reminders.Items) == 0
+}
+
+// DigestMessage renders the daily or weekly agenda email.
+func DigestMessage(to string, data DigestData) (Message, error) {
+	subject := "Your agenda for " + data.Date.Format("Mon, 2 Jan")
+
+	if data.Weekly {
+		subject = "Your week from " + data.Date.Format("Mon, 2 Jan")
+	}
+
+	return render(to, subject, "digest", data)
+}
+
+// PasswordResetData is what the password reset templates render.
+type PasswordResetData struct {
+	FirstName string
+	Link string
+	ExpiresInMinutes int
+}
+
+// PasswordResetMessage renders the email with the link to reset a forgotten password.
+func PasswordResetMessage(to string, data PasswordResetData) (Message, error) {
+	return render(to, "Reset your password", "password_reset", data)
+}
+
+// EmailVerificationData is what the email verification templates render.
+type EmailVerificationData struct {
+	FirstName string
+	Link string
+	ExpiresInHours int
+}
+
+// EmailVerificationMessage renders the email with the link that verifies the address it is sent to.
+func EmailVerificationMessage(to string, data EmailVerificationData) (Message, error) {
+	return render(to, "Verify your email", "email_verification", data)
+}
+
+// render builds a message from the text and HTML templates sharing name.
+func render(to string, subject string, name string, data interface{}) (Message, error) {
+	var text, html bytes.Buffer
+
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, err
+	}
+
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message {
+		To: to,
+		Subject: subject,
+		Text: text.String(),
+		HTML: html.String(),
+	}, nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderMessage(t *testing.T) {
+	data := ReminderData {
+		FirstName: "Jane",
+		TaskTitle: "Review <script>",
+		Description: "Bring the numbers",
+		DueDate: time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC),
+	}
+
+	msg, err := ReminderMessage("jane@example.com", data)
+
+	require.NoError(t, err)
+	require.Equal(t, "jane@example.com", msg.To)
+	require.Equal(t, "Reminder: Review <script>", msg.Subject)
+
+	require.Contains(t, msg.Text, "Hi Jane,")
+	require.Contains(t, msg.Text, `"Review <script>" is due Fri, 31 May 2024 17:00 UTC`)
+	require.Contains(t, msg.Text, "Bring the numbers")
+
+	require.Contains(t, msg.HTML, "<strong>Review &lt;script&gt;</strong>")
+	require.NotContains(t, msg.HTML, "<script>")
+}
+
+func TestDigestMessage(t *testing.T) {
+	at := time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC)
+
+	data := DigestData {
+		FirstName: "Jane",
+		Date: time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		Overdue: DigestSection{Items: []DigestItem{{Title: "Taxes <b>", At: at.AddDate(0, 0, -2)}}, More: 3},
+		Due: DigestSection{Items: []DigestItem{{Title: "Standup", At: at}}},
+	}
+
+	msg, err := DigestMessage("jane@example.com", data)
+
+	require.NoError(t, err)
+	require.Equal(t, "Your agenda for Mon, 13 May", msg.Subject)
+
+	require.Contains(t, msg.Text, "Overdue:\n  - Taxes <b> (was due Sat, 11 May 09:00)\n  ...and 3 more\n")
+	require.Contains(t, msg.Text, "Due today:\n  - Standup (Mon, 13 May 09:00)\n")
+	require.NotContains(t, msg.Text, "Upcoming reminders")
+
+	require.Contains(t, msg.HTML, "<strong>Taxes &lt;b&gt;</strong>")
+	require.Contains(t, msg.HTML, "and 3 more")
+
+	data = DigestData{FirstName: "Jane", Weekly: true, Date: data.Date}
+
+	msg, err = DigestMessage("jane@example.com", data)
+
+	require.NoError(t, err)
+	require.Equal(t, "Your week from Mon, 13 May", msg.Subject)
+	require.Contains(t, msg.Text, "Nothing is due this week.")
+}
+
+func TestPasswordResetMessage(t *testing.T) {
+	data := PasswordResetData {
+		FirstName: "Jane",
+		Link: "https://app.example.com/reset-password?token=abc&x=<y>",
+		ExpiresInMinutes: 60,
+	}
+
+	msg, err := PasswordResetMessage("jane@example.com", data)
+
+	require.NoError(t, err)
+	require.Equal(t, "Reset your password", msg.Subject)
+
+	require.Contains(t, msg.Text, data.Link)
+	require.Contains(t, msg.Text, "expires in 60 minutes")
+
+	require.Contains(t, msg.HTML, `href="https://app.example.com/reset-password?token=abc&amp;x=%3cy%3e"`)
+}
+
+func TestEmailVerificationMessage(t *testing.T) {
+	data := EmailVerificationData {
+		FirstName: "Jane",
+		Link: "https://app.example.com/verify-email?token=abc.def",
+		ExpiresInHours: 24,
+	}
+
+	msg, err := EmailVerificationMessage("jane@example.com", data)
+
+	require.NoError(t, err)
+	require.Equal(t, "jane@example.com", msg.To)
+	require.Equal(t, "Verify your email", msg.Subject)
+
+	require.Contains(t, msg.Text, data.Link)
+	require.Contains(t, msg.Text, "expires in 24 hours")
+
+	require.Contains(t, msg.HTML, `href="https://app.example.com/verify-email?token=abc.def"`)
+}
@@ -0,0 +1,213 @@
+package stream
+
+import (
+	"encoding/json"
+	"m1thrandir225/your_time/webhook"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	//DefaultReplaySize is how many recent events a broker keeps for resuming streams
+	DefaultReplaySize = 1024
+	//subscriberBuffer is how many events a subscriber may fall behind by
+	//before it is dropped
+	subscriberBuffer = 64
+)
+
+// Event is an event on the stream of a user. The data is the same JSON a
+// webhook receives for the event.
+type Event struct {
+	ID uint64
+	UserID uuid.UUID
+	Type webhook.EventType
+	Data []byte
+}
+
+// Broker fans the events of users out to their live streams. It keeps the most
+// recent events in a ring buffer, so a client that reconnects with the ID of the
+// last event it saw gets what it missed.
+//
+// Event IDs start at the time the broker was created, so IDs handed out by an
+// earlier process are always older than the buffer and can't be mistaken for
+// recent ones.
+type Broker struct {
+	mu sync.Mutex
+	firstID uint64
+	nextID uint64
+	buffer []Event
+	//head is where the next event goes once the buffer is full
+	head int
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the live events of a user on C. C is closed when the
+// subscriber falls too far behind or the broker shuts down, the client is then
+// expected to reconnect with the ID of the last event it got.
+type Subscription struct {
+	C <-chan Event
+	ch chan Event
+	userID uuid.UUID
+	broker *Broker
+}
+
+func NewBroker(replaySize int) *Broker {
+	if replaySize <= 0 {
+		replaySize = DefaultReplaySize
+	}
+
+	firstID := uint64(time.Now().UnixMicro())
+
+	return &Broker {
+		firstID: firstID,
+		nextID: firstID,
+		buffer: make([]Event, 0, replaySize),
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends event to the live streams of userID and keeps it for replay.
+func (broker *Broker) Publish(userID uuid.UUID, event webhook.Event) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		return nil
+	}
+
+	published := Event {
+		ID: broker.nextID,
+		UserID: userID,
+		Type: event.Type,
+		Data: data,
+	}
+
+	broker.nextID++
+
+	if len(broker.buffer) < cap(broker.buffer) {
+		broker.buffer = append(broker.buffer, published)
+	} else {
+		broker.buffer[broker.head] = published
+		broker.head = (broker.head + 1) % len(broker.buffer)
+	}
+
+	for sub := range broker.subscribers[userID] {
+		select {
+		case sub.ch <- published:
+		default:
+			//A slow client must not hold up everyone else, it catches
+			//up from the buffer once it reconnects
+			broker.remove(sub)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe starts a live stream of the events of userID. lastEventID is the
+// ID of the last event the client saw, if it is resuming. The events it missed
+// since are returned as replay. complete is false when some of them are no
+// longer buffered, or the ID is unknown, and the client has to reload its state.
+func (broker *Broker) Subscribe(userID uuid.UUID, lastEventID string) (sub *Subscription, replay []Event, complete bool) {
+	ch := make(chan Event, subscriberBuffer)
+
+	sub = &Subscription {
+		C: ch,
+		ch: ch,
+		userID: userID,
+		broker: broker,
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	replay = []Event{}
+	complete = true
+
+	if lastEventID != "" {
+		replay, complete = broker.since(userID, lastEventID)
+	}
+
+	if broker.closed {
+		close(ch)
+		return sub, replay, complete
+	}
+
+	if broker.subscribers[userID] == nil {
+		broker.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	broker.subscribers[userID][sub] = struct{}{}
+
+	return sub, replay, complete
+}
+
+// Close stops every live stream. Publishing afterwards does nothing.
+func (broker *Broker) Close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	broker.closed = true
+
+	for _, subs := range broker.subscribers {
+		for sub := range subs {
+			broker.remove(sub)
+		}
+	}
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (sub *Subscription) Close() {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+
+	if _, ok := sub.broker.subscribers[sub.userID][sub]; ok {
+		sub.broker.remove(sub)
+	}
+}
+
+// remove unregisters sub and closes its channel. The lock must be held.
+func (broker *Broker) remove(sub *Subscription) {
+	delete(broker.subscribers[sub.userID], sub)
+
+	if len(broker.subscribers[sub.userID]) == 0 {
+		delete(broker.subscribers, sub.userID)
+	}
+
+	close(sub.ch)
+}
+
+// since returns the buffered events of userID after lastEventID. The lock must be held.
+func (broker *Broker) since(userID uuid.UUID, lastEventID string) ([]Event, bool) {
+	lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+
+	if err != nil || lastID < broker.firstID || lastID >= broker.nextID {
+		return []Event{}, false
+	}
+
+	//Events older than the buffer may have been for this user too
+
+	oldest := broker.nextID - uint64(len(broker.buffer))
+	complete := lastID + 1 >= oldest
+
+	events := []Event{}
+
+	for i := range broker.buffer {
+		event := broker.buffer[(broker.head + i) % len(broker.buffer)]
+
+		if event.ID > lastID && event.UserID == userID {
+			events = append(events, event)
+		}
+	}
+
+	return events, complete
+}
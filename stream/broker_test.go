@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"m1thrandir225/your_time/webhook"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(eventType webhook.EventType) webhook.Event {
+	return webhook.Event {
+		ID: uuid.New(),
+		Type: eventType,
+		Data: webhook.Test{Message: "hello"},
+	}
+}
+
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker(8)
+
+	userID := uuid.New()
+
+	sub, replay, complete := broker.Subscribe(userID, "")
+	defer sub.Close()
+
+	require.Empty(t, replay)
+	require.True(t, complete)
+
+	other, _, _ := broker.Subscribe(uuid.New(), "")
+	defer other.Close()
+
+	event := testEvent(webhook.EventTaskCreated)
+	require.NoError(t, broker.Publish(userID, event))
+
+	received := <-sub.C
+	require.Equal(t, userID, received.UserID)
+	require.Equal(t, webhook.EventTaskCreated, received.Type)
+
+	var data webhook.Event
+	require.NoError(t, json.Unmarshal(received.Data, &data))
+	require.Equal(t, event.ID, data.ID)
+
+	//Events only go to the streams of their user
+	require.Len(t, other.C, 0)
+}
+
+func TestBrokerReplay(t *testing.T) {
+	broker := NewBroker(4)
+
+	userID := uuid.New()
+
+	var ids []uint64
+
+	for i := 0; i < 3; i++ {
+		sub, _, _ := broker.Subscribe(userID, "")
+		require.NoError(t, broker.Publish(userID, testEvent(webhook.EventTaskUpdated)))
+		require.NoError(t, broker.Publish(uuid.New(), testEvent(webhook.EventTaskUpdated)))
+		ids = append(ids, (<-sub.C).ID)
+		sub.Close()
+	}
+
+	sub, replay, complete := broker.Subscribe(userID, fmt.Sprint(ids[1]))
+	defer sub.Close()
+
+	require.True(t, complete)
+	require.Len(t, replay, 1)
+	require.Equal(t, ids[2], replay[0].ID)
+
+	//The first event of the user has been pushed out of the buffer
+	_, replay, complete = broker.Subscribe(userID, fmt.Sprint(ids[0]))
+	require.False(t, complete)
+	require.Len(t, replay, 2)
+
+	for _, lastEventID := range []string{"not-a-number", "1", fmt.Sprint(ids[2] + 100)} {
+		_, replay, complete = broker.Subscribe(userID, lastEventID)
+		require.False(t, complete)
+		require.Empty(t, replay)
+	}
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := NewBroker(DefaultReplaySize)
+
+	userID := uuid.New()
+
+	sub, _, _ := broker.Subscribe(userID, "")
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		require.NoError(t, broker.Publish(userID, testEvent(webhook.EventTaskUpdated)))
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+
+	require.Equal(t, subscriberBuffer, received)
+
+	//Closing a dropped subscription does nothing
+	sub.Close()
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker := NewBroker(DefaultReplaySize)
+
+	sub, _, _ := broker.Subscribe(uuid.New(), "")
+
+	broker.Close()
+
+	_, ok := <-sub.C
+	require.False(t, ok)
+
+	late, _, _ := broker.Subscribe(uuid.New(), "")
+
+	_, ok = <-late.C
+	require.False(t, ok)
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"m1thrandir225/your_time/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerifier(t *testing.T) {
+	verifier, err := NewEmailVerifier(util.RandomString(32))
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	email := util.RandomEmail()
+
+	token, err := verifier.CreateToken(userID, email, time.Minute)
+	require.NoError(t, err)
+
+	claims, err := verifier.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, userID, claims.UserID)
+	require.Equal(t, email, claims.Email)
+	require.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiredAt, time.Second)
+}
+
+func TestEmailVerifierExpired(t *testing.T) {
+	verifier, err := NewEmailVerifier(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := verifier.CreateToken(uuid.New(), util.RandomEmail(), -time.Minute)
+	require.NoError(t, err)
+
+	claims, err := verifier.VerifyToken(token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+	require.Nil(t, claims)
+}
+
+func TestEmailVerifierTampered(t *testing.T) {
+	verifier, err := NewEmailVerifier(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := verifier.CreateToken(uuid.New(), "jane@example.com", time.Minute)
+	require.NoError(t, err)
+
+	//Claims for another address with the original signature
+	other, err := verifier.CreateToken(uuid.New(), "mallory@example.com", time.Minute)
+	require.NoError(t, err)
+
+	forged := strings.Split(other, ".")[0] + "." + strings.Split(token, ".")[1]
+
+	_, err = verifier.VerifyToken(forged)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	//Signed with another key
+	otherVerifier, err := NewEmailVerifier(util.RandomString(32))
+	require.NoError(t, err)
+
+	_, err = otherVerifier.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = verifier.VerifyToken("not-a-token")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestEmailVerifierInvalidKey(t *testing.T) {
+	verifier, err := NewEmailVerifier(util.RandomString(8))
+	require.Error(t, err)
+	require.Nil(t, verifier)
+}
@@ -23,16 +23,18 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(email string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(email, duration)
+func (maker *JWTMaker) CreateToken(email string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(email, tokenType, duration)
 
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 
-	return token.SignedString([]byte(maker.secretKey))
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+
+	return token, payload, err
 
 }
 
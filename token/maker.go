@@ -3,7 +3,8 @@ package token
 import "time"
 
 type Maker interface {
-	CreateToken(email string, duration time.Duration) (string, error)
+	//CreateToken also returns the payload of the token, whose ID identifies it
+	CreateToken(email string, tokenType TokenType, duration time.Duration) (string, *Payload, error)
 
 	VerifyToken(token string) (*Payload, error)
 }
\ No newline at end of file
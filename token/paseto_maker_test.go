@@ -23,7 +23,7 @@ func TestPasetoMaker(t *testing.T) {
 	expiredAt := issuedAt.Add(duration)
 
 
-	token, err := maker.CreateToken(email, duration)
+	token, created, err := maker.CreateToken(email, TokenTypeAccess, duration)
 
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
@@ -32,8 +32,11 @@ func TestPasetoMaker(t *testing.T) {
 
 	require.NoError(t, err)
 
+	require.Equal(t, created.ID, payload.ID)
+
 	require.NotZero(t, payload.ID)
 	require.Equal(t, email, payload.Email)
+	require.Equal(t, TokenTypeAccess, payload.Type)
 
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 
@@ -47,7 +50,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 
 	require.NoError(t, err)
 
-	token, err := maker.CreateToken(util.RandomEmail(), -time.Minute)
+	token, _, err := maker.CreateToken(util.RandomEmail(), TokenTypeAccess, -time.Minute)
 
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
@@ -60,7 +63,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 }
 
 func TestInvalidPasetoToken(t *testing.T) {
-	payload, err := NewPayload(util.RandomEmail(), time.Minute)
+	payload, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
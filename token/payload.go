@@ -7,10 +7,19 @@ import (
 	"github.com/google/uuid"
 )
 
+// TokenType tells access tokens apart from refresh tokens, so one can't be
+// used in place of the other.
+type TokenType string
+
+const (
+	TokenTypeAccess TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
 
 type Payload struct {
 	ID uuid.UUID `json:"id"`
 	Email string `json:"email"`
+	Type TokenType `json:"type"`
 	IssuedAt time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
@@ -18,11 +27,12 @@ type Payload struct {
 var (
 	ErrExpiredToken = errors.New("token has expired")
 	ErrInvalidToken = errors.New("token is invalid")
+	ErrWrongTokenType = errors.New("token is of the wrong type")
 )
 
 
-//Create a new payload with a given email and duration
-func NewPayload(email string, duration time.Duration) (*Payload, error) {
+//Create a new payload of tokenType with a given email and duration
+func NewPayload(email string, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	tokenId, err := uuid.NewRandom()
 
 	if err != nil {
@@ -32,6 +42,7 @@ func NewPayload(email string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID: tokenId,
 		Email: email,
+		Type: tokenType,
 		IssuedAt: time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -0,0 +1,172 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// revocationSyncOverlap is how far back every sync looks again, so revocations
+// committed by slower transactions on other instances aren't missed.
+const revocationSyncOverlap = time.Minute
+
+var ErrRevokedToken = errors.New("token has been revoked")
+
+// RevocationList is the set of tokens that were revoked before they expired.
+// Revocations are stored in Postgres so every instance sees them, and kept in
+// memory so checking a token doesn't hit the database. Tokens revoked on this
+// instance are rejected right away, ones revoked on other instances once the
+// list syncs.
+type RevocationList struct {
+	store db.Querier
+
+	mu sync.RWMutex
+	//revoked maps the ID of a revoked token to when it expires
+	revoked map[uuid.UUID]time.Time
+	syncedAt time.Time
+}
+
+func NewRevocationList(store db.Querier) *RevocationList {
+	return &RevocationList {
+		store: store,
+		revoked: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Start syncs the list every interval, deleting expired revocations, until ctx is cancelled.
+func (list *RevocationList) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := list.Sync(ctx); err != nil {
+			log.Println("cannot sync revoked tokens:", err)
+		}
+
+		if _, err := list.Purge(ctx); err != nil {
+			log.Println("cannot purge revoked tokens:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync loads the tokens revoked since the last sync.
+func (list *RevocationList) Sync(ctx context.Context) error {
+	list.mu.RLock()
+	since := list.syncedAt
+	list.mu.RUnlock()
+
+	now := time.Now()
+
+	if !since.IsZero() {
+		since = since.Add(-revocationSyncOverlap)
+	}
+
+	rows, err := list.store.ListRevokedTokens(ctx, since)
+
+	if err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	for _, row := range rows {
+		list.revoked[row.ID] = row.ExpiresAt
+	}
+
+	list.syncedAt = now
+
+	return nil
+}
+
+// Purge forgets expired revocations and deletes them from the database,
+// returning how many rows were deleted.
+func (list *RevocationList) Purge(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	list.mu.Lock()
+	for id, expiresAt := range list.revoked {
+		if now.After(expiresAt) {
+			delete(list.revoked, id)
+		}
+	}
+	list.mu.Unlock()
+
+	return list.store.DeleteExpiredRevokedTokens(ctx, now)
+}
+
+// Revoke revokes the token of payload, issued to the user with userID.
+func (list *RevocationList) Revoke(ctx context.Context, userID uuid.UUID, payload *Payload) error {
+	err := list.store.RevokeToken(ctx, db.RevokeTokenParams {
+		ID: payload.ID,
+		UserID: userID,
+		ExpiresAt: payload.ExpiredAt,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	list.revoked[payload.ID] = payload.ExpiredAt
+	list.mu.Unlock()
+
+	return nil
+}
+
+// RevokeUser revokes every access token of the user that hasn't expired yet.
+func (list *RevocationList) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	rows, err := list.store.RevokeUserAccessTokens(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	for _, row := range rows {
+		list.revoked[row.ID] = row.ExpiresAt
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every access token issued through the sessions
+// rotated from the same login that hasn't expired yet.
+func (list *RevocationList) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	rows, err := list.store.RevokeSessionFamilyAccessTokens(ctx, familyID)
+
+	if err != nil {
+		return err
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	for _, row := range rows {
+		list.revoked[row.ID] = row.ExpiresAt
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the token of payload was revoked.
+func (list *RevocationList) IsRevoked(payload *Payload) bool {
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+
+	_, ok := list.revoked[payload.ID]
+	return ok
+}
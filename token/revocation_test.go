@@ -0,0 +1,150 @@
+package token
+
+import (
+	"context"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationListRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	list := NewRevocationList(store)
+
+	payload, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+
+	store.EXPECT().
+		RevokeToken(gomock.Any(), gomock.Eq(db.RevokeTokenParams{ID: payload.ID, UserID: userID, ExpiresAt: payload.ExpiredAt})).
+		Times(1).
+		Return(nil)
+
+	require.False(t, list.IsRevoked(payload))
+	require.NoError(t, list.Revoke(context.Background(), userID, payload))
+	require.True(t, list.IsRevoked(payload))
+}
+
+func TestRevocationListRevokeUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	list := NewRevocationList(store)
+
+	revoked, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	other, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+
+	store.EXPECT().
+		RevokeUserAccessTokens(gomock.Any(), gomock.Eq(userID)).
+		Times(1).
+		Return([]db.RevokeUserAccessTokensRow{{ID: revoked.ID, ExpiresAt: revoked.ExpiredAt}}, nil)
+
+	require.NoError(t, list.RevokeUser(context.Background(), userID))
+	require.True(t, list.IsRevoked(revoked))
+	require.False(t, list.IsRevoked(other))
+}
+
+func TestRevocationListRevokeFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	list := NewRevocationList(store)
+
+	revoked, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	other, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	familyID := uuid.New()
+
+	store.EXPECT().
+		RevokeSessionFamilyAccessTokens(gomock.Any(), gomock.Eq(familyID)).
+		Times(1).
+		Return([]db.RevokeSessionFamilyAccessTokensRow{{ID: revoked.ID, ExpiresAt: revoked.ExpiredAt}}, nil)
+
+	require.NoError(t, list.RevokeFamily(context.Background(), familyID))
+	require.True(t, list.IsRevoked(revoked))
+	require.False(t, list.IsRevoked(other))
+}
+
+func TestRevocationListSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	list := NewRevocationList(store)
+
+	payload, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	//The first sync loads every revocation, later ones only recent ones
+
+	store.EXPECT().
+		ListRevokedTokens(gomock.Any(), gomock.Eq(time.Time{})).
+		Times(1).
+		Return([]db.ListRevokedTokensRow{}, nil)
+
+	store.EXPECT().
+		ListRevokedTokens(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, revokedAfter time.Time) ([]db.ListRevokedTokensRow, error) {
+			require.WithinDuration(t, time.Now().Add(-revocationSyncOverlap), revokedAfter, time.Second)
+			return []db.ListRevokedTokensRow{{ID: payload.ID, ExpiresAt: payload.ExpiredAt}}, nil
+		})
+
+	require.NoError(t, list.Sync(context.Background()))
+	require.False(t, list.IsRevoked(payload))
+
+	require.NoError(t, list.Sync(context.Background()))
+	require.True(t, list.IsRevoked(payload))
+}
+
+func TestRevocationListPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	list := NewRevocationList(store)
+
+	expired, err := NewPayload(util.RandomEmail(), TokenTypeAccess, -time.Minute)
+	require.NoError(t, err)
+
+	live, err := NewPayload(util.RandomEmail(), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	store.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+	store.EXPECT().DeleteExpiredRevokedTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+
+	require.NoError(t, list.Revoke(context.Background(), uuid.New(), expired))
+	require.NoError(t, list.Revoke(context.Background(), uuid.New(), live))
+
+	deleted, err := list.Purge(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	require.False(t, list.IsRevoked(expired))
+	require.True(t, list.IsRevoked(live))
+}
@@ -0,0 +1,68 @@
+package totp
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+const (
+	//RecoveryCodeCount is how many recovery codes a user gets at a time
+	RecoveryCodeCount = 10
+	recoveryAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+	//recoveryCodeLength characters of 5 bits each, enough that a fast hash of a code can't be reversed
+	recoveryCodeLength = 16
+	recoveryGroupLength = 4
+)
+
+// NewRecoveryCodes generates the codes a user can sign in with instead of a
+// TOTP code, each one working once.
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+
+	for i := range codes {
+		raw := make([]byte, recoveryCodeLength)
+
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+
+		var sb strings.Builder
+
+		for j, b := range raw {
+			if j > 0 && j % recoveryGroupLength == 0 {
+				sb.WriteByte('-')
+			}
+			//The alphabet has 32 characters, so every one is equally likely
+			sb.WriteByte(recoveryAlphabet[int(b) % len(recoveryAlphabet)])
+		}
+
+		codes[i] = sb.String()
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode is the form of a recovery code that is hashed, so
+// codes typed in capitals or without dashes still match.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+
+	return code
+}
+
+// IsCode reports whether input looks like a TOTP code rather than a recovery code.
+func IsCode(input string) bool {
+	if len(input) != Digits {
+		return false
+	}
+
+	for _, c := range input {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
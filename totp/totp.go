@@ -0,0 +1,118 @@
+// Package totp implements time-based one-time passwords (RFC 6238) as used
+// by authenticator apps, and the recovery codes that stand in for them.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	//Period is how long a code is valid, authenticator apps assume 30 seconds
+	Period = 30 * time.Second
+	Digits = 6
+	//Skew is how many periods before and after now are accepted, for clocks that drift
+	Skew = 1
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret generates a secret, base32 encoded the way authenticator apps expect it.
+func NewSecret() (string, error) {
+	key := make([]byte, secretSize)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(key), nil
+}
+
+// URI is the otpauth URI of secret, usually shown as a QR code for
+// authenticator apps to scan.
+func URI(issuer string, account string, secret string) string {
+	query := url.Values {
+		"secret": {secret},
+		"issuer": {issuer},
+		"algorithm": {"SHA1"},
+		"digits": {fmt.Sprint(Digits)},
+		"period": {fmt.Sprint(int(Period.Seconds()))},
+	}
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step is the number of the period t falls in.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code is the code of secret for the period t falls in.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+
+	if err != nil {
+		return "", err
+	}
+
+	return code(key, uint64(Step(t)), Digits), nil
+}
+
+// Validate checks code against the periods around t. It returns the step of
+// the period the code belongs to, callers should reject steps that were used
+// before so a code can't be replayed.
+func Validate(secret string, code string, t time.Time) (step int64, ok bool) {
+	key, err := decodeSecret(secret)
+
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	now := Step(t)
+
+	for step := now - Skew; step <= now + Skew; step++ {
+		if hmac.Equal([]byte(codeAt(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func codeAt(key []byte, step int64) string {
+	return code(key, uint64(step), Digits)
+}
+
+// code is the HOTP (RFC 4226) value of counter.
+func code(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value % mod)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
@@ -0,0 +1,113 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA1 test vectors of RFC 6238, appendix B.
+func TestCodeRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, vector := range vectors {
+		step := Step(time.Unix(vector.unix, 0))
+		require.Equal(t, vector.code, code(key, uint64(step), 8), vector.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+
+	current, err := Code(secret, now)
+	require.NoError(t, err)
+	require.Len(t, current, Digits)
+
+	step, ok := Validate(secret, current, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	//A code from the previous period is accepted for clocks that drift
+	previous, err := Code(secret, now.Add(-Period))
+	require.NoError(t, err)
+
+	step, ok = Validate(secret, previous, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now) - 1, step)
+
+	//Older ones aren't
+	old, err := Code(secret, now.Add(-3 * Period))
+	require.NoError(t, err)
+
+	next, err := Code(secret, now.Add(Period))
+	require.NoError(t, err)
+
+	//Codes are 6 digits, so one in a million times an old one equals a recent one
+	if old != previous && old != current && old != next {
+		_, ok = Validate(secret, old, now)
+		require.False(t, ok)
+	}
+
+	_, ok = Validate(secret, "12345", now)
+	require.False(t, ok)
+
+	_, ok = Validate("not base32!", current, now)
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Your Time", "jane@example.com", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+
+	require.Equal(t, "otpauth", parsed.Scheme)
+	require.Equal(t, "totp", parsed.Host)
+	require.Equal(t, "/Your Time:jane@example.com", parsed.Path)
+
+	query := parsed.Query()
+	require.Equal(t, "JBSWY3DPEHPK3PXP", query.Get("secret"))
+	require.Equal(t, "Your Time", query.Get("issuer"))
+	require.Equal(t, "6", query.Get("digits"))
+	require.Equal(t, "30", query.Get("period"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := NewRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := make(map[string]bool)
+
+	for _, code := range codes {
+		require.Len(t, code, 19)
+		require.Len(t, strings.Split(code, "-"), 4)
+		require.False(t, IsCode(code))
+		require.False(t, seen[code])
+		seen[code] = true
+
+		require.Equal(t, NormalizeRecoveryCode(code), NormalizeRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", " "))))
+	}
+
+	require.True(t, IsCode("012345"))
+	require.False(t, IsCode("01234a"))
+}
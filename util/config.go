@@ -12,6 +12,40 @@ type Config struct {
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
 	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	//PasswordResetDuration is how long a password reset link works, an hour by default
+	PasswordResetDuration time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+	//AppURL is where the web app is served, links in emails point there
+	AppURL string `mapstructure:"APP_URL"`
+	//EmailVerification is one of off, restrict or login and defaults to off. With
+	//restrict, users with an unverified email can't use webhooks or email digests,
+	//with login they can't sign in either
+	EmailVerification string `mapstructure:"EMAIL_VERIFICATION"`
+	//RevocationSyncInterval is how long a token revoked on one instance can still be used on the others
+	RevocationSyncInterval time.Duration `mapstructure:"REVOCATION_SYNC_INTERVAL"`
+	TrashRetention time.Duration `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+	UrgencyHorizon time.Duration `mapstructure:"URGENCY_HORIZON"`
+	ReminderPollInterval time.Duration `mapstructure:"REMINDER_POLL_INTERVAL"`
+	ReminderMaxAttempts int32 `mapstructure:"REMINDER_MAX_ATTEMPTS"`
+	ReminderRetryBackoff time.Duration `mapstructure:"REMINDER_RETRY_BACKOFF"`
+	SMTPHost string `mapstructure:"SMTP_HOST"`
+	SMTPPort int `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom string `mapstructure:"SMTP_FROM"`
+	//SMTPTLSMode is one of none, starttls or tls and defaults to starttls
+	SMTPTLSMode string `mapstructure:"SMTP_TLS_MODE"`
+	WebhookPollInterval time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookMaxAttempts int32 `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryBackoff time.Duration `mapstructure:"WEBHOOK_RETRY_BACKOFF"`
+	//WebhookMaxFailures is how many failed attempts in a row disable an endpoint
+	WebhookMaxFailures int32 `mapstructure:"WEBHOOK_MAX_FAILURES"`
+	//DigestPollInterval is how often due digests are looked for, it should be well under an hour
+	DigestPollInterval time.Duration `mapstructure:"DIGEST_POLL_INTERVAL"`
+	//EventsReplaySize is how many recent events are kept for resuming event streams
+	EventsReplaySize int `mapstructure:"EVENTS_REPLAY_SIZE"`
+	EventsHeartbeatInterval time.Duration `mapstructure:"EVENTS_HEARTBEAT_INTERVAL"`
 }
 
 
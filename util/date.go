@@ -5,3 +5,14 @@ import "time"
 func IsReminderBeforeDue(reminder, due time.Time) bool {
 	return reminder.Before(due)
 }
+
+// LocationOrUTC loads the IANA zone name. Unknown or empty zones fall back to UTC.
+func LocationOrUTC(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+
+	if err != nil || name == "" {
+		return time.UTC
+	}
+
+	return loc
+}
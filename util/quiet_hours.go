@@ -0,0 +1,87 @@
+package util
+
+import (
+	"time"
+)
+
+// QuietWindow is a stretch of a week during which a user doesn't want to be
+// notified. It starts on Weekday at Start and ends at End, both in minutes after
+// local midnight. A window that ends before it starts runs into the next day.
+type QuietWindow struct {
+	Weekday time.Weekday
+	Start int
+	End int
+}
+
+// QuietHours are the quiet windows of a user, in Location, together with an
+// ad-hoc do-not-disturb that silences everything until DoNotDisturbUntil.
+type QuietHours struct {
+	Location *time.Location
+	Windows []QuietWindow
+	DoNotDisturbUntil time.Time
+}
+
+// DeliveryTime returns the earliest time at or after at that falls outside of
+// every quiet window and the do-not-disturb. Windows are evaluated in local time,
+// so 22:00-07:00 stays 22:00-07:00 across DST changes.
+func (q QuietHours) DeliveryTime(at time.Time) time.Time {
+	//Every step moves at to the end of a window, so back to back windows settle
+	//after one step each. The bound only matters when the windows cover the whole week.
+	for i := 0; i < 2*len(q.Windows)+2; i++ {
+		next := q.deferOnce(at)
+
+		if next.Equal(at) {
+			return at
+		}
+
+		at = next
+	}
+
+	return at
+}
+
+func (q QuietHours) deferOnce(at time.Time) time.Time {
+	if at.Before(q.DoNotDisturbUntil) {
+		return q.DoNotDisturbUntil
+	}
+
+	local := at.In(q.location())
+
+	for _, window := range q.Windows {
+		//A window running past midnight may have started the day before
+		for _, day := range []int{local.Day(), local.Day() - 1} {
+			start, end, ok := window.on(local.Year(), local.Month(), day, q.location())
+
+			if ok && !at.Before(start) && at.Before(end) {
+				return end
+			}
+		}
+	}
+
+	return at
+}
+
+// on returns when the window starts and ends if it starts on the given day.
+func (window QuietWindow) on(year int, month time.Month, day int, loc *time.Location) (start time.Time, end time.Time, ok bool) {
+	if time.Date(year, month, day, 0, 0, 0, 0, loc).Weekday() != window.Weekday {
+		return
+	}
+
+	endDay := day
+	if window.End <= window.Start {
+		endDay++
+	}
+
+	start = time.Date(year, month, day, window.Start / 60, window.Start % 60, 0, 0, loc)
+	end = time.Date(year, month, endDay, window.End / 60, window.End % 60, 0, 0, loc)
+
+	return start, end, true
+}
+
+func (q QuietHours) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+
+	return q.Location
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHoursOvernight(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	//22:00-07:00 every weeknight, Sunday to Thursday
+	quiet := QuietHours {
+		Location: newYork,
+	}
+
+	for day := time.Sunday; day <= time.Thursday; day++ {
+		quiet.Windows = append(quiet.Windows, QuietWindow{Weekday: day, Start: 22 * 60, End: 7 * 60})
+	}
+
+	//Wednesday 03:00 falls in the window that started on Tuesday
+	at := time.Date(2024, time.May, 15, 3, 0, 0, 0, newYork)
+	require.Equal(t, time.Date(2024, time.May, 15, 7, 0, 0, 0, newYork), quiet.DeliveryTime(at))
+
+	//Wednesday 21:59 is still fine
+	at = time.Date(2024, time.May, 15, 21, 59, 0, 0, newYork)
+	require.Equal(t, at, quiet.DeliveryTime(at))
+
+	//Friday night has no window
+	at = time.Date(2024, time.May, 17, 23, 0, 0, 0, newYork)
+	require.Equal(t, at, quiet.DeliveryTime(at))
+
+	//Thursday night runs into Friday morning
+	at = time.Date(2024, time.May, 16, 23, 30, 0, 0, newYork)
+	require.Equal(t, time.Date(2024, time.May, 17, 7, 0, 0, 0, newYork), quiet.DeliveryTime(at))
+}
+
+func TestQuietHoursAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	//Clocks in Berlin moved forward on the night of Sunday 2024-03-31
+	quiet := QuietHours {
+		Location: berlin,
+		Windows: []QuietWindow{{Weekday: time.Saturday, Start: 23 * 60, End: 8 * 60}},
+	}
+
+	at := time.Date(2024, time.March, 30, 23, 30, 0, 0, berlin)
+	deliverAt := quiet.DeliveryTime(at)
+
+	require.Equal(t, 8, deliverAt.In(berlin).Hour())
+	require.Equal(t, 6, deliverAt.UTC().Hour())
+}
+
+func TestQuietHoursChained(t *testing.T) {
+	quiet := QuietHours {
+		Location: time.UTC,
+		Windows: []QuietWindow{
+			{Weekday: time.Monday, Start: 12 * 60, End: 13 * 60},
+			{Weekday: time.Monday, Start: 13 * 60, End: 14 * 60},
+		},
+	}
+
+	at := time.Date(2024, time.May, 13, 12, 30, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, time.May, 13, 14, 0, 0, 0, time.UTC), quiet.DeliveryTime(at))
+}
+
+func TestQuietHoursDoNotDisturb(t *testing.T) {
+	at := time.Date(2024, time.May, 13, 12, 0, 0, 0, time.UTC)
+
+	//Do-not-disturb ends inside a quiet window, so the window wins
+	quiet := QuietHours {
+		Windows: []QuietWindow{{Weekday: time.Monday, Start: 14 * 60, End: 15 * 60}},
+		DoNotDisturbUntil: at.Add(2*time.Hour + 30*time.Minute),
+	}
+
+	require.Equal(t, time.Date(2024, time.May, 13, 15, 0, 0, 0, time.UTC), quiet.DeliveryTime(at))
+
+	//A do-not-disturb in the past changes nothing
+	quiet.DoNotDisturbUntil = at.Add(-time.Hour)
+	require.Equal(t, at, quiet.DeliveryTime(at))
+}
+
+func TestQuietHoursWholeWeek(t *testing.T) {
+	quiet := QuietHours{}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		quiet.Windows = append(quiet.Windows, QuietWindow{Weekday: day, Start: 0, End: 0})
+	}
+
+	//The windows never end, DeliveryTime still returns
+	at := time.Date(2024, time.May, 13, 12, 0, 0, 0, time.UTC)
+	require.True(t, quiet.DeliveryTime(at).After(at))
+}
@@ -0,0 +1,147 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Recurrence describes a repeating task: an RFC 5545 RRULE expanded from
+// Start, skipping ExDates. Occurrences are computed in Timezone, so a task
+// repeating at 09:00 stays at 09:00 local time across DST changes.
+type Recurrence struct {
+	Rule     string
+	Start    time.Time
+	Timezone string
+	ExDates  []time.Time
+}
+
+var (
+	ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+	ErrRecurrenceTooLong = errors.New("too many occurrences to expand")
+)
+
+//Expanding always starts at Start, so a frequent rule with an old start has to
+//walk through every occurrence since then. This bounds how far that goes,
+//about 270 years of a daily rule.
+const maxRecurrenceIterations = 100000
+
+func (r Recurrence) set() (*rrule.Set, error) {
+	if strings.ContainsAny(r.Rule, "\r\n") {
+		return nil, fmt.Errorf("%w: only a single RRULE is supported", ErrInvalidRecurrence)
+	}
+
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	option, err := rrule.StrToROptionInLocation(r.Rule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	//rrule only works with second precision
+	option.Dtstart = r.Start.In(loc).Truncate(time.Second)
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	set := &rrule.Set{}
+	set.RRule(rule)
+
+	for _, exdate := range r.ExDates {
+		set.ExDate(exdate.In(loc).Truncate(time.Second))
+	}
+
+	return set, nil
+}
+
+// Validate reports whether the rule and timezone can be expanded. Rules
+// repeating more often than daily aren't accepted.
+func (r Recurrence) Validate() error {
+	set, err := r.set()
+	if err != nil {
+		return err
+	}
+
+	if set.GetRRule().OrigOptions.Freq > rrule.DAILY {
+		return fmt.Errorf("%w: repeating more often than daily isn't supported", ErrInvalidRecurrence)
+	}
+
+	return nil
+}
+
+// Next returns the first occurrence strictly after t. ok is false once the
+// rule has run out of occurrences.
+func (r Recurrence) Next(t time.Time) (next time.Time, ok bool, err error) {
+	set, err := r.set()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	iterator := set.Iterator()
+	for i := 0; i < maxRecurrenceIterations; i++ {
+		occurrence, ok := iterator()
+		if !ok {
+			return time.Time{}, false, nil
+		}
+		if occurrence.After(t) {
+			return occurrence, true, nil
+		}
+	}
+
+	return time.Time{}, false, ErrRecurrenceTooLong
+}
+
+// Between returns up to limit occurrences in the window [from, to).
+// ErrRecurrenceTooLong is returned if the window is too far past Start.
+func (r Recurrence) Between(from, to time.Time, limit int) ([]time.Time, error) {
+	set, err := r.set()
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []time.Time{}
+
+	next := set.Iterator()
+	for i := 0; len(occurrences) < limit; i++ {
+		if i == maxRecurrenceIterations {
+			return nil, ErrRecurrenceTooLong
+		}
+
+		occurrence, ok := next()
+		if !ok || !occurrence.Before(to) {
+			break
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// FormatExDates encodes dates as an RFC 5545 EXDATE value list in UTC.
+func FormatExDates(dates []time.Time) string {
+	values := make([]string, len(dates))
+	for i, date := range dates {
+		values[i] = date.UTC().Format(rrule.DateTimeFormat)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// ParseExDates decodes a list written by FormatExDates.
+func ParseExDates(value string) ([]time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	return rrule.StrToDates(value)
+}
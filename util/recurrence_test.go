@@ -0,0 +1,112 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	//Clocks in Berlin moved forward on 2024-03-31
+	recurrence := Recurrence {
+		Rule: "FREQ=DAILY",
+		Start: time.Date(2024, 3, 29, 9, 0, 0, 0, berlin),
+		Timezone: "Europe/Berlin",
+	}
+
+	occurrences, err := recurrence.Between(recurrence.Start, recurrence.Start.AddDate(0, 0, 4), 10)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 4)
+
+	for _, occurrence := range occurrences {
+		require.Equal(t, 9, occurrence.In(berlin).Hour())
+	}
+
+	require.Equal(t, 8, occurrences[0].UTC().Hour())
+	require.Equal(t, 7, occurrences[3].UTC().Hour())
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	start := time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC)
+
+	recurrence := Recurrence {
+		Rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+		Start: start,
+		Timezone: "UTC",
+	}
+
+	next, ok, err := recurrence.Next(start)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 6, 28, 17, 0, 0, 0, time.UTC), next.UTC())
+
+	next, ok, err = recurrence.Next(next)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2024, 7, 26, 17, 0, 0, 0, time.UTC), next.UTC())
+
+	_, ok, err = recurrence.Next(next)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestRecurrenceExDates(t *testing.T) {
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	recurrence := Recurrence {
+		Rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		Start: start,
+		Timezone: "UTC",
+		ExDates: []time.Time{start.AddDate(0, 0, 1)},
+	}
+
+	occurrences, err := recurrence.Between(start, start.AddDate(0, 0, 7), 10)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 4)
+
+	for _, occurrence := range occurrences {
+		require.NotEqual(t, time.Tuesday, occurrence.Weekday())
+	}
+
+	exdates, err := ParseExDates(FormatExDates(recurrence.ExDates))
+	require.NoError(t, err)
+	require.Len(t, exdates, 1)
+	require.True(t, recurrence.ExDates[0].Equal(exdates[0]))
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	start := time.Now()
+
+	require.NoError(t, Recurrence{Rule: "FREQ=WEEKLY", Start: start, Timezone: "America/New_York"}.Validate())
+	require.ErrorIs(t, Recurrence{Rule: "FREQ=SOMETIMES", Start: start}.Validate(), ErrInvalidRecurrence)
+	require.ErrorIs(t, Recurrence{Rule: "FREQ=DAILY", Start: start, Timezone: "Mars/Olympus"}.Validate(), ErrInvalidRecurrence)
+	require.ErrorIs(t, Recurrence{Rule: "FREQ=DAILY\nRRULE:FREQ=WEEKLY", Start: start}.Validate(), ErrInvalidRecurrence)
+	require.ErrorIs(t, Recurrence{Rule: "FREQ=HOURLY", Start: start}.Validate(), ErrInvalidRecurrence)
+	require.ErrorIs(t, Recurrence{Rule: "FREQ=SECONDLY", Start: start}.Validate(), ErrInvalidRecurrence)
+}
+
+func TestRecurrenceTooLong(t *testing.T) {
+	//Rules like this can't be created anymore but may already be stored
+	recurrence := Recurrence {
+		Rule: "FREQ=SECONDLY",
+		Start: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Timezone: "UTC",
+	}
+
+	now := time.Now()
+
+	_, err := recurrence.Between(now, now.Add(time.Hour), 10)
+	require.ErrorIs(t, err, ErrRecurrenceTooLong)
+
+	_, _, err = recurrence.Next(now)
+	require.ErrorIs(t, err, ErrRecurrenceTooLong)
+
+	//Close enough to the start it still works
+	occurrences, err := recurrence.Between(recurrence.Start, recurrence.Start.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 10)
+}
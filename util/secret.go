@@ -0,0 +1,28 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewSecretToken generates a random token for links and codes sent to users,
+// safe to put in URLs.
+func NewSecretToken() (string, error) {
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+// HashSecretToken is what is stored instead of a secret token, so a leaked
+// database can't be used to redeem it. Tokens are random enough that a
+// fast hash is fine, unlike passwords.
+func HashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
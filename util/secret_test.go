@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretToken(t *testing.T) {
+	token, err := NewSecretToken()
+	require.NoError(t, err)
+	require.Len(t, token, 43)
+
+	other, err := NewSecretToken()
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+
+	require.Equal(t, HashSecretToken(token), HashSecretToken(token))
+	require.NotEqual(t, HashSecretToken(token), HashSecretToken(other))
+	require.NotContains(t, HashSecretToken(token), token)
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("webhook endpoint resolves to an address that isn't public")
+
+//sharedAddressSpace is the carrier-grade NAT range, which IsPrivate doesn't cover
+var sharedAddressSpace = net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// NewClient is the HTTP client webhooks are sent with. Endpoints are chosen by
+// users, so it only connects to public addresses: the check runs on the
+// address actually dialed, which also covers hostnames that resolve to
+// something else by the time the request is sent. Redirects aren't followed,
+// a redirecting endpoint gets the 3xx recorded as a failed delivery.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer {
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return ErrForbiddenAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	//A proxy would make the connection on our behalf, past the check above
+	transport.Proxy = nil
+
+	return &http.Client {
+		Timeout: timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublicIP reports whether ip can be reached on the internet, as opposed to
+// loopback, private, link-local (e.g. cloud metadata services) and other
+// special purpose addresses.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	return !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	db "m1thrandir225/your_time/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType names what happened. Endpoints subscribe to a list of them.
+type EventType string
+
+const (
+	EventTaskCreated EventType = "task.created"
+	EventTaskUpdated EventType = "task.updated"
+	EventTaskCompleted EventType = "task.completed"
+	//EventTaskDeleted is sent when a task is moved to the trash
+	EventTaskDeleted EventType = "task.deleted"
+	EventReminderDue EventType = "reminder.due"
+	//EventTest is only sent on request and can't be subscribed to
+	EventTest EventType = "webhook.test"
+)
+
+// EventTypes are the events an endpoint can subscribe to.
+var EventTypes = []EventType{EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted, EventReminderDue}
+
+// IsSubscribable reports whether endpoints can subscribe to eventType.
+func IsSubscribable(eventType string) bool {
+	for _, t := range EventTypes {
+		if string(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON body POSTed to an endpoint. Its ID stays the same across
+// retries, so receivers can use it to drop duplicates.
+type Event struct {
+	ID uuid.UUID `json:"id"`
+	Type EventType `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data interface{} `json:"data"`
+}
+
+// Task is how a task appears in the data of an event.
+type Task struct {
+	ID uuid.UUID `json:"id"`
+	Title string `json:"title"`
+	Description *string `json:"description"`
+	DueDate time.Time `json:"due_date"`
+	Status db.TaskStatus `json:"status"`
+	Priority db.TaskPriority `json:"priority"`
+	Important bool `json:"important"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	RecurrenceRule *string `json:"recurrence_rule"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Reminder is the data of a reminder.due event.
+type Reminder struct {
+	ID uuid.UUID `json:"id"`
+	RemindAt time.Time `json:"remind_at"`
+	Task Task `json:"task"`
+}
+
+// Test is the data of a webhook.test event.
+type Test struct {
+	EndpointID uuid.UUID `json:"endpoint_id"`
+	Message string `json:"message"`
+}
+
+func NewTask(task db.Task) Task {
+	data := Task {
+		ID: task.ID,
+		Title: task.Title,
+		DueDate: task.DueDate,
+		Status: task.Status,
+		Priority: task.Priority,
+		Important: task.Important,
+		CreatedAt: task.CreatedAt,
+		UpdatedAt: task.UpdatedAt,
+	}
+
+	if task.Description.Valid {
+		data.Description = &task.Description.String
+	}
+
+	if task.ProjectID.Valid {
+		data.ProjectID = &task.ProjectID.UUID
+	}
+
+	if task.ParentID.Valid {
+		data.ParentID = &task.ParentID.UUID
+	}
+
+	if task.RecurrenceRule.Valid {
+		data.RecurrenceRule = &task.RecurrenceRule.String
+	}
+
+	if task.CompletedAt.Valid {
+		data.CompletedAt = &task.CompletedAt.Time
+	}
+
+	return data
+}
+
+func NewTaskEvent(eventType EventType, task db.Task) Event {
+	return Event {
+		ID: uuid.New(),
+		Type: eventType,
+		CreatedAt: time.Now().UTC(),
+		Data: NewTask(task),
+	}
+}
+
+// NewReminderEvent builds the reminder.due event of a delivery. The event
+// reuses the ID of the delivery, so retrying the delivery doesn't publish it twice.
+func NewReminderEvent(delivery db.ReminderDelivery, task db.Task) Event {
+	return Event {
+		ID: delivery.ID,
+		Type: EventReminderDue,
+		CreatedAt: time.Now().UTC(),
+		Data: Reminder {
+			ID: delivery.ReminderID,
+			RemindAt: delivery.RemindAt,
+			Task: NewTask(task),
+		},
+	}
+}
+
+func NewTestEvent(endpoint db.WebhookEndpoint) Event {
+	return Event {
+		ID: uuid.New(),
+		Type: EventTest,
+		CreatedAt: time.Now().UTC(),
+		Data: Test {
+			EndpointID: endpoint.ID,
+			Message: "This is a test event. Your endpoint is set up correctly.",
+		},
+	}
+}
+
+// Publish queues event for every active endpoint of the user that subscribed to its type.
+func Publish(ctx context.Context, store db.Querier, userID uuid.UUID, event Event) error {
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = store.EnqueueWebhookEvent(ctx, db.EnqueueWebhookEventParams{
+		EventID: event.ID,
+		EventType: string(event.Type),
+		Payload: payload,
+		UserID: userID,
+	})
+
+	return err
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//SignatureHeader carries the HMAC of the request, see Sign
+	SignatureHeader = "X-Webhook-Signature"
+	//TimestampHeader carries the unix time the request was signed at
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader = "X-Webhook-Event"
+	EventIDHeader = "X-Webhook-ID"
+
+	signaturePrefix = "sha256="
+	secretPrefix = "whsec_"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrSignatureExpired = errors.New("webhook signature is too old")
+)
+
+// NewSecret generates the secret an endpoint's requests are signed with.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return secretPrefix + hex.EncodeToString(key), nil
+}
+
+// Sign computes the signature header of a request. The timestamp is signed
+// together with the body, so a captured request can't be replayed later with
+// a fresh timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a request the way a
+// receiver should. Requests signed more than tolerance ago are rejected.
+func Verify(secret string, signature string, timestamp string, body []byte, tolerance time.Duration) error {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	signedAt := time.Unix(unix, 0)
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, signedAt, body))) {
+		return ErrInvalidSignature
+	}
+
+	if time.Since(signedAt) > tolerance {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
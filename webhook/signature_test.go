@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, "whsec_"))
+
+	body := []byte(`{"type":"task.created"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	signature := Sign(secret, now, body)
+	require.True(t, strings.HasPrefix(signature, "sha256="))
+
+	require.NoError(t, Verify(secret, signature, timestamp, body, time.Minute))
+
+	require.ErrorIs(t, Verify(secret, signature, timestamp, []byte(`{"type":"task.updated"}`), time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify("whsec_other", signature, timestamp, body, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, signature, strconv.FormatInt(now.Unix()+1, 10), body, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, strings.TrimPrefix(signature, "sha256="), timestamp, body, time.Minute), ErrInvalidSignature)
+
+	old := now.Add(-time.Hour)
+	require.ErrorIs(t, Verify(secret, Sign(secret, old, body), strconv.FormatInt(old.Unix(), 10), body, time.Minute), ErrSignatureExpired)
+}
@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/digest"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/util"
+	"time"
+)
+
+// DigestScheduler sends users their daily or weekly agenda at the hour they
+// picked, in their timezone. Digests go to the inbox and, when a mailer is
+// configured, out by email.
+type DigestScheduler struct {
+	store db.Store
+	mailer notify.Notifier
+	interval time.Duration
+}
+
+// NewDigestScheduler creates a scheduler. mailer may be nil, digests are then only added to the inbox.
+func NewDigestScheduler(store db.Store, mailer notify.Notifier, interval time.Duration) *DigestScheduler {
+	return &DigestScheduler{
+		store: store,
+		mailer: mailer,
+		interval: interval,
+	}
+}
+
+// Start runs a dispatch every interval until ctx is cancelled.
+func (scheduler *DigestScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := scheduler.Dispatch(ctx); err != nil {
+			log.Println("cannot dispatch digests:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch sends every digest that has come due. It returns the number of digests that were sent.
+func (scheduler *DigestScheduler) Dispatch(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	subscribers, err := scheduler.store.ListDigestSubscribers(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, subscriber := range subscribers {
+		schedule := digest.Schedule {
+			Frequency: subscriber.Frequency,
+			Hour: int(subscriber.Hour),
+			Weekday: time.Weekday(subscriber.Weekday),
+			Location: util.LocationOrUTC(subscriber.Timezone),
+		}
+
+		scheduledAt, due := schedule.Due(now, subscriber.LastSentAt)
+
+		if !due {
+			continue
+		}
+
+		ok, err := scheduler.send(ctx, subscriber, scheduledAt, now)
+
+		if err != nil {
+			log.Println("cannot send digest:", err)
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+func (scheduler *DigestScheduler) send(ctx context.Context, subscriber db.ListDigestSubscribersRow, scheduledAt time.Time, now time.Time) (bool, error) {
+	//Claiming the digest first makes sure only one instance sends it. A digest
+	//that fails afterwards is skipped rather than risking sending it twice
+
+	claimed, err := scheduler.store.ClaimDigest(ctx, db.ClaimDigestParams {
+		UserID: subscriber.UserID,
+		SentAt: now,
+		ScheduledAt: scheduledAt,
+	})
+
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	user, err := scheduler.store.GetUserByID(ctx, subscriber.UserID)
+
+	if err != nil {
+		return false, err
+	}
+
+	agenda, err := digest.Load(ctx, scheduler.store, user, subscriber.Frequency, now)
+
+	if err != nil {
+		return false, err
+	}
+
+	//Nothing to report is better told by not sending anything
+
+	if agenda.Empty() {
+		return false, nil
+	}
+
+	msg, err := agenda.Message(user)
+
+	if err != nil {
+		return false, err
+	}
+
+	_, err = scheduler.store.CreateNotification(ctx, db.CreateNotificationParams {
+		UserID: user.ID,
+		Type: db.NotificationTypeDigest,
+		Title: msg.Subject,
+		Body: agenda.Summary(),
+		DedupeKey: sql.NullString{String: "digest:" + scheduledAt.UTC().Format(time.RFC3339), Valid: true},
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if scheduler.mailer != nil {
+		if err := scheduler.mailer.Send(ctx, msg); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
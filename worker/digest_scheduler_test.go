@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/notify"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	messages []notify.Message
+}
+
+func (mailer *fakeMailer) Send(ctx context.Context, msg notify.Message) error {
+	mailer.messages = append(mailer.messages, msg)
+	return nil
+}
+
+func TestDigestScheduler(t *testing.T) {
+	user := db.User{ID: uuid.New(), Email: util.RandomEmail(), FirstName: "Jane", Timezone: "UTC"}
+
+	//Scheduled for the current hour, so the digest is due right away
+	subscriber := db.ListDigestSubscribersRow {
+		UserID: user.ID,
+		Frequency: db.DigestFrequencyDaily,
+		Hour: int32(time.Now().UTC().Hour()),
+		Timezone: "UTC",
+	}
+
+	sentAlready := subscriber
+	sentAlready.LastSentAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	overdue := db.Task {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: time.Now().AddDate(0, 0, -2),
+		Status: db.TaskStatusOpen,
+	}
+
+	testCases := []struct {
+		name string
+		subscriber db.ListDigestSubscribersRow
+		build func(store *mockdb.MockStore)
+		sent int
+		mailed int
+	}{
+		{
+			name: "Sent",
+			subscriber: subscriber,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ClaimDigest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ClaimDigestParams) (int64, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, subscriber.Hour, int32(arg.ScheduledAt.Hour()))
+						require.Zero(t, arg.ScheduledAt.Minute())
+						return 1, nil
+					})
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetOpenTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{overdue}, nil)
+				store.EXPECT().GetRemindersForTasks(gomock.Any(), gomock.Any()).Times(1).Return([]db.TaskReminder{}, nil)
+				store.EXPECT().
+					CreateNotification(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (int64, error) {
+						require.Equal(t, db.NotificationTypeDigest, arg.Type)
+						require.Equal(t, "1 overdue, 0 due today, 0 upcoming reminders", arg.Body)
+						require.Contains(t, arg.DedupeKey.String, "digest:")
+						return 1, nil
+					})
+			},
+			sent: 1,
+			mailed: 1,
+		},
+		{
+			name: "ClaimedElsewhere",
+			subscriber: subscriber,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimDigest(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "NothingToReport",
+			subscriber: subscriber,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimDigest(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetOpenTasksByUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Task{}, nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "AlreadySent",
+			subscriber: sentAlready,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimDigest(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().ListDigestSubscribers(gomock.Any()).Times(1).Return([]db.ListDigestSubscribersRow{tc.subscriber}, nil)
+
+			tc.build(store)
+
+			mailer := &fakeMailer{}
+
+			scheduler := NewDigestScheduler(store, mailer, time.Minute)
+
+			sent, err := scheduler.Dispatch(context.Background())
+
+			require.NoError(t, err)
+			require.Equal(t, tc.sent, sent)
+			require.Len(t, mailer.messages, tc.mailed)
+		})
+	}
+}
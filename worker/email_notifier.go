@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"m1thrandir225/your_time/notify"
+)
+
+// EmailNotifier sends reminders as email through a notify.Notifier.
+type EmailNotifier struct {
+	mailer notify.Notifier
+}
+
+func NewEmailNotifier(mailer notify.Notifier) *EmailNotifier {
+	return &EmailNotifier{mailer: mailer}
+}
+
+func (notifier *EmailNotifier) NotifyReminder(ctx context.Context, reminder Reminder) error {
+	msg, err := notify.ReminderMessage(reminder.User.Email, notify.ReminderData{
+		FirstName: reminder.User.FirstName,
+		TaskTitle: reminder.Task.Title,
+		Description: reminder.Task.Description.String,
+		DueDate: reminder.Task.DueDate,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return notifier.mailer.Send(ctx, msg)
+}
@@ -0,0 +1,289 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/stream"
+	"m1thrandir225/your_time/webhook"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	//reminderBatchSize caps how many deliveries a single dispatch claims
+	reminderBatchSize = 50
+	//reminderSendTimeout bounds handing a single reminder to the notifier
+	reminderSendTimeout = 30 * time.Second
+	//reminderLease is how long a claimed delivery stays hidden from other
+	//dispatchers before it is considered abandoned and claimed again. The
+	//batch is sent one reminder at a time, so it has to outlast all of them,
+	//the margin covers the database updates.
+	reminderLease = reminderBatchSize * reminderSendTimeout + time.Minute
+	//reminderLookback stops reminders that were due long ago (e.g. before the
+	//dispatcher was first deployed) from firing all at once
+	reminderLookback = 24 * time.Hour
+	maxReminderBackoff = 6 * time.Hour
+)
+
+// Reminder is everything a notifier needs to tell a user about a due task.
+type Reminder struct {
+	Delivery db.ReminderDelivery
+	Task db.Task
+	User db.User
+}
+
+// Notifier delivers a due reminder to its user. Returning an error schedules
+// another attempt.
+type Notifier interface {
+	NotifyReminder(ctx context.Context, reminder Reminder) error
+}
+
+// LogNotifier writes reminders to the log. It is used when no other channel
+// is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyReminder(ctx context.Context, reminder Reminder) error {
+	log.Printf("reminder for %s: %q is due %s", reminder.User.Email, reminder.Task.Title, reminder.Task.DueDate.Format(time.RFC3339))
+	return nil
+}
+
+// ReminderDispatcher turns the reminders of tasks into deliveries and hands
+// them to a notifier. Deliveries are claimed with FOR UPDATE SKIP LOCKED so
+// several instances can run side by side without sending twice.
+type ReminderDispatcher struct {
+	store db.Store
+	notifier Notifier
+	events *stream.Broker
+	interval time.Duration
+	maxAttempts int32
+	backoff time.Duration
+}
+
+// NewReminderDispatcher creates a dispatcher. events may be nil, fired reminders are then not sent to live streams.
+func NewReminderDispatcher(store db.Store, notifier Notifier, events *stream.Broker, interval time.Duration, maxAttempts int32, backoff time.Duration) *ReminderDispatcher {
+	return &ReminderDispatcher{
+		store: store,
+		notifier: notifier,
+		events: events,
+		interval: interval,
+		maxAttempts: maxAttempts,
+		backoff: backoff,
+	}
+}
+
+// Start runs a dispatch every interval until ctx is cancelled.
+func (dispatcher *ReminderDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dispatcher.Dispatch(ctx); err != nil {
+			log.Println("cannot dispatch reminders:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch enqueues reminders that have come due, then claims and delivers
+// one batch. It returns the number of deliveries that were sent.
+func (dispatcher *ReminderDispatcher) Dispatch(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	_, err := dispatcher.store.EnqueueDueReminders(ctx, db.EnqueueDueRemindersParams{
+		Since: now.Add(-reminderLookback),
+		Until: now,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	deliveries, err := dispatcher.store.ClaimDueReminders(ctx, db.ClaimDueRemindersParams{
+		Now: now,
+		BatchSize: reminderBatchSize,
+		LeaseUntil: now.Add(reminderLease),
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, delivery := range deliveries {
+		ok, err := dispatcher.deliver(ctx, delivery)
+
+		//A claimed delivery that couldn't be updated is picked up again once its lease runs out
+		if err != nil {
+			log.Println("cannot deliver reminder:", err)
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+func (dispatcher *ReminderDispatcher) deliver(ctx context.Context, delivery db.ReminderDelivery) (bool, error) {
+	//The reminder may have been removed or moved, and the task finished or trashed, since the delivery was enqueued
+
+	reminder, err := dispatcher.store.GetTaskReminderByID(ctx, delivery.ReminderID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			_, err = dispatcher.store.CancelReminder(ctx, delivery.ID)
+		}
+		return false, err
+	}
+
+	task, err := dispatcher.store.GetTaskByID(ctx, delivery.TaskID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			_, err = dispatcher.store.CancelReminder(ctx, delivery.ID)
+		}
+		return false, err
+	}
+
+	if !reminderStillDue(task, reminder, delivery) {
+		_, err = dispatcher.store.CancelReminder(ctx, delivery.ID)
+		return false, err
+	}
+
+	user, err := dispatcher.store.GetUserByID(ctx, task.UserID)
+
+	if err != nil {
+		return false, err
+	}
+
+	deferred, err := dispatcher.deferQuietHours(ctx, task, user, delivery)
+
+	if err != nil || deferred {
+		return false, err
+	}
+
+	//The event has the ID of the delivery, so a retry doesn't queue it again.
+	//Live streams may see it once per attempt, clients drop it by its ID
+
+	event := webhook.NewReminderEvent(delivery, task)
+
+	if err := webhook.Publish(ctx, dispatcher.store, task.UserID, event); err != nil {
+		return false, err
+	}
+
+	if dispatcher.events != nil {
+		if err := dispatcher.events.Publish(task.UserID, event); err != nil {
+			log.Println("cannot publish stream event:", err)
+		}
+	}
+
+	if _, err := dispatcher.store.CreateNotification(ctx, reminderNotification(task, delivery)); err != nil {
+		return false, err
+	}
+
+	notifyCtx, cancel := context.WithTimeout(ctx, reminderSendTimeout)
+	defer cancel()
+
+	notifyErr := dispatcher.notifier.NotifyReminder(notifyCtx, Reminder{Delivery: delivery, Task: task, User: user})
+
+	if notifyErr == nil {
+		_, err = dispatcher.store.MarkReminderSent(ctx, delivery.ID)
+		return err == nil, err
+	}
+
+	arg := db.MarkReminderFailedParams {
+		ID: delivery.ID,
+		Status: db.DeliveryStatusPending,
+		LastError: sql.NullString{String: notifyErr.Error(), Valid: true},
+		NextAttemptAt: time.Now().Add(dispatcher.retryDelay(delivery.Attempts)),
+	}
+
+	if delivery.Attempts >= dispatcher.maxAttempts {
+		arg.Status = db.DeliveryStatusFailed
+	}
+
+	_, err = dispatcher.store.MarkReminderFailed(ctx, arg)
+
+	return false, err
+}
+
+// deferQuietHours puts a delivery that came due during the quiet hours or the
+// do-not-disturb of the user back until they end. It doesn't count as an attempt.
+func (dispatcher *ReminderDispatcher) deferQuietHours(ctx context.Context, task db.Task, user db.User, delivery db.ReminderDelivery) (bool, error) {
+	if task.BreakThroughQuietHours {
+		return false, nil
+	}
+
+	windows, err := dispatcher.store.ListQuietHours(ctx, user.ID)
+
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+
+	deliverAt := user.QuietHours(windows).DeliveryTime(now)
+
+	if !deliverAt.After(now) {
+		return false, nil
+	}
+
+	_, err = dispatcher.store.DeferReminderDelivery(ctx, db.DeferReminderDeliveryParams {
+		ID: delivery.ID,
+		NextAttemptAt: deliverAt,
+	})
+
+	return err == nil, err
+}
+
+// retryDelay doubles the backoff for every attempt that has already failed.
+func (dispatcher *ReminderDispatcher) retryDelay(attempts int32) time.Duration {
+	delay := dispatcher.backoff
+
+	for i := int32(1); i < attempts && delay < maxReminderBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxReminderBackoff {
+		delay = maxReminderBackoff
+	}
+
+	return delay
+}
+
+// reminderNotification is the inbox entry of a due reminder. It is keyed by
+// the delivery, so a retry doesn't add it twice.
+func reminderNotification(task db.Task, delivery db.ReminderDelivery) db.CreateNotificationParams {
+	return db.CreateNotificationParams {
+		UserID: task.UserID,
+		Type: db.NotificationTypeReminder,
+		Title: task.Title,
+		Body: "Due " + task.DueDate.UTC().Format("Mon, 2 Jan 2006 15:04 MST"),
+		TaskID: uuid.NullUUID{UUID: task.ID, Valid: true},
+		DedupeKey: sql.NullString{String: "reminder:" + delivery.ID.String(), Valid: true},
+	}
+}
+
+func reminderStillDue(task db.Task, reminder db.TaskReminder, delivery db.ReminderDelivery) bool {
+	if task.DeletedAt.Valid || task.Status == db.TaskStatusDone || task.Status == db.TaskStatusCancelled {
+		return false
+	}
+
+	if reminder.DismissedAt.Valid {
+		return false
+	}
+
+	return reminder.RemindAt.Equal(delivery.RemindAt)
+}
@@ -0,0 +1,366 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct {
+	err error
+	reminders []Reminder
+}
+
+func (notifier *fakeNotifier) NotifyReminder(ctx context.Context, reminder Reminder) error {
+	notifier.reminders = append(notifier.reminders, reminder)
+	return notifier.err
+}
+
+func TestReminderDispatcher(t *testing.T) {
+	user := db.User{ID: uuid.New(), Email: util.RandomEmail()}
+
+	remindAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	task := db.Task {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: remindAt.Add(time.Hour),
+		Status: db.TaskStatusOpen,
+	}
+
+	reminder := db.TaskReminder {
+		ID: uuid.New(),
+		TaskID: task.ID,
+		OffsetSeconds: sql.NullInt32{Int32: 3600, Valid: true},
+		RemindAt: remindAt,
+	}
+
+	delivery := db.ReminderDelivery {
+		ID: uuid.New(),
+		TaskID: task.ID,
+		ReminderID: reminder.ID,
+		RemindAt: remindAt,
+		Status: db.DeliveryStatusPending,
+		Attempts: 1,
+	}
+
+	completed := task
+	completed.Status = db.TaskStatusDone
+
+	rescheduled := reminder
+	rescheduled.RemindAt = remindAt.Add(time.Hour)
+
+	dismissed := reminder
+	dismissed.DismissedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	lastAttempt := delivery
+	lastAttempt.Attempts = 3
+
+	dndUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	dnd := user
+	dnd.DndUntil = sql.NullTime{Time: dndUntil, Valid: true}
+
+	breakThrough := task
+	breakThrough.BreakThroughQuietHours = true
+
+	testCases := []struct {
+		name 	string
+		delivery 	db.ReminderDelivery
+		notifyErr 	error
+		build 	func(store *mockdb.MockStore)
+		sent 	int
+		notified 	int
+	}{
+		{
+			name: "Sent",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+				store.EXPECT().
+					EnqueueWebhookEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.EnqueueWebhookEventParams) (int64, error) {
+						require.Equal(t, delivery.ID, arg.EventID)
+						require.Equal(t, "reminder.due", arg.EventType)
+						require.Equal(t, user.ID, arg.UserID)
+						return 1, nil
+					})
+				store.EXPECT().
+					CreateNotification(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (int64, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, db.NotificationTypeReminder, arg.Type)
+						require.Equal(t, task.Title, arg.Title)
+						require.Equal(t, uuid.NullUUID{UUID: task.ID, Valid: true}, arg.TaskID)
+						require.Equal(t, "reminder:"+delivery.ID.String(), arg.DedupeKey.String)
+						return 1, nil
+					})
+				store.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+			sent: 1,
+			notified: 1,
+		},
+		{
+			name: "Retry",
+			delivery: delivery,
+			notifyErr: errors.New("connection refused"),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+				store.EXPECT().EnqueueWebhookEvent(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().
+					MarkReminderFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkReminderFailedParams) (db.ReminderDelivery, error) {
+						require.Equal(t, db.DeliveryStatusPending, arg.Status)
+						require.Equal(t, "connection refused", arg.LastError.String)
+						require.WithinDuration(t, time.Now().Add(time.Minute), arg.NextAttemptAt, time.Second)
+						return delivery, nil
+					})
+			},
+			notified: 1,
+		},
+		{
+			name: "GiveUp",
+			delivery: lastAttempt,
+			notifyErr: errors.New("connection refused"),
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+				store.EXPECT().EnqueueWebhookEvent(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().
+					MarkReminderFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkReminderFailedParams) (db.ReminderDelivery, error) {
+						require.Equal(t, db.DeliveryStatusFailed, arg.Status)
+						return lastAttempt, nil
+					})
+			},
+			notified: 1,
+		},
+		{
+			name: "DoNotDisturb",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(dnd, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+				store.EXPECT().
+					DeferReminderDelivery(gomock.Any(), gomock.Eq(db.DeferReminderDeliveryParams{ID: delivery.ID, NextAttemptAt: dndUntil})).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().EnqueueWebhookEvent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "BreakThroughQuietHours",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(breakThrough, nil)
+				store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(dnd, nil)
+				store.EXPECT().ListQuietHours(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().EnqueueWebhookEvent(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+			sent: 1,
+			notified: 1,
+		},
+		{
+			name: "TaskCompleted",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(completed, nil)
+				store.EXPECT().CancelReminder(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+		},
+		{
+			name: "ReminderRescheduled",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(rescheduled, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CancelReminder(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+		},
+		{
+			name: "ReminderDismissed",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(dismissed, nil)
+				store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CancelReminder(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+		},
+		{
+			name: "ReminderRemoved",
+			delivery: delivery,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(db.TaskReminder{}, sql.ErrNoRows)
+				store.EXPECT().CancelReminder(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().
+				EnqueueDueReminders(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.EnqueueDueRemindersParams) (int64, error) {
+					require.WithinDuration(t, time.Now(), arg.Until, time.Second)
+					require.Equal(t, reminderLookback, arg.Until.Sub(arg.Since))
+					return 1, nil
+				})
+
+			store.EXPECT().
+				ClaimDueReminders(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.ClaimDueRemindersParams) ([]db.ReminderDelivery, error) {
+					require.Equal(t, reminderLease, arg.LeaseUntil.Sub(arg.Now))
+					return []db.ReminderDelivery{tc.delivery}, nil
+				})
+
+			tc.build(store)
+
+			notifier := &fakeNotifier{err: tc.notifyErr}
+
+			dispatcher := NewReminderDispatcher(store, notifier, nil, time.Minute, 3, time.Minute)
+
+			sent, err := dispatcher.Dispatch(context.Background())
+
+			require.NoError(t, err)
+			require.Equal(t, tc.sent, sent)
+			require.Len(t, notifier.reminders, tc.notified)
+		})
+	}
+}
+
+type notifierFunc func(ctx context.Context, reminder Reminder) error
+
+func (f notifierFunc) NotifyReminder(ctx context.Context, reminder Reminder) error {
+	return f(ctx, reminder)
+}
+
+func TestReminderLeaseCoversSending(t *testing.T) {
+	user := db.User{ID: uuid.New(), Email: util.RandomEmail()}
+
+	task := db.Task {
+		ID: uuid.New(),
+		UserID: user.ID,
+		Title: util.RandomString(6),
+		DueDate: time.Now().Add(time.Hour),
+		Status: db.TaskStatusOpen,
+	}
+
+	reminder := db.TaskReminder {
+		ID: uuid.New(),
+		TaskID: task.ID,
+		RemindAt: time.Now().Add(-time.Minute).Truncate(time.Second),
+	}
+
+	delivery := db.ReminderDelivery {
+		ID: uuid.New(),
+		TaskID: task.ID,
+		ReminderID: reminder.ID,
+		RemindAt: reminder.RemindAt,
+		Status: db.DeliveryStatusPending,
+		Attempts: 1,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	//The store hands the delivery out again only once its lease has run out,
+	//the way ClaimDueReminders does
+
+	var claimedAt, leaseUntil time.Time
+
+	store.EXPECT().EnqueueDueReminders(gomock.Any(), gomock.Any()).Times(2).Return(int64(0), nil)
+	store.EXPECT().
+		ClaimDueReminders(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ context.Context, arg db.ClaimDueRemindersParams) ([]db.ReminderDelivery, error) {
+			if arg.Now.Before(leaseUntil) {
+				return []db.ReminderDelivery{}, nil
+			}
+			claimedAt, leaseUntil = arg.Now, arg.LeaseUntil
+			return []db.ReminderDelivery{delivery}, nil
+		})
+
+	store.EXPECT().GetTaskReminderByID(gomock.Any(), gomock.Eq(reminder.ID)).Times(1).Return(reminder, nil)
+	store.EXPECT().GetTaskByID(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+	store.EXPECT().GetUserByID(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().ListQuietHours(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.QuietHour{}, nil)
+	store.EXPECT().EnqueueWebhookEvent(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+	store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+	store.EXPECT().MarkReminderSent(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+
+	other := NewReminderDispatcher(store, notifierFunc(func(ctx context.Context, reminder Reminder) error {
+		t.Error("the delivery was sent by a second dispatcher")
+		return nil
+	}), nil, time.Minute, 3, time.Minute)
+
+	notifier := notifierFunc(func(ctx context.Context, reminder Reminder) error {
+		//Sending can't go past the lease, even as the last of a full batch
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.False(t, deadline.After(time.Now().Add(reminderSendTimeout)))
+		require.True(t, claimedAt.Add(reminderBatchSize * reminderSendTimeout).Before(leaseUntil))
+
+		sent, err := other.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, sent)
+
+		return nil
+	})
+
+	dispatcher := NewReminderDispatcher(store, notifier, nil, time.Minute, 3, time.Minute)
+
+	sent, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+}
+
+func TestReminderRetryDelay(t *testing.T) {
+	dispatcher := NewReminderDispatcher(nil, LogNotifier{}, nil, time.Minute, 10, time.Minute)
+
+	require.Equal(t, time.Minute, dispatcher.retryDelay(1))
+	require.Equal(t, 2*time.Minute, dispatcher.retryDelay(2))
+	require.Equal(t, 8*time.Minute, dispatcher.retryDelay(4))
+	require.Equal(t, maxReminderBackoff, dispatcher.retryDelay(20))
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"time"
+)
+
+// TrashPurger permanently deletes tasks that have been in the trash for
+// longer than the configured retention.
+type TrashPurger struct {
+	store db.Store
+	retention time.Duration
+	interval time.Duration
+}
+
+func NewTrashPurger(store db.Store, retention time.Duration, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		store: store,
+		retention: retention,
+		interval: interval,
+	}
+}
+
+// Start runs a purge every interval until ctx is cancelled.
+func (purger *TrashPurger) Start(ctx context.Context) {
+	ticker := time.NewTicker(purger.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := purger.Purge(ctx); err != nil {
+			log.Println("cannot purge trash:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (purger *TrashPurger) Purge(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-purger.retention)
+
+	return purger.store.PurgeTrashedTasks(ctx, before)
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	mockdb "m1thrandir225/your_time/db/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashPurger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	retention := 30 * 24 * time.Hour
+
+	store.EXPECT().
+		PurgeTrashedTasks(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-retention), before, time.Second)
+			return 3, nil
+		})
+
+	purger := NewTrashPurger(store, retention, time.Hour)
+
+	purged, err := purger.Purge(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+}
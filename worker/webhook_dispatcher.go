@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"time"
+)
+
+const (
+	webhookBatchSize = 50
+	webhookTimeout = 10 * time.Second
+	//webhookLease has to outlast sending the whole batch, which goes out one
+	//delivery at a time, otherwise another dispatcher could claim the rest of
+	//the batch and send it twice. The margin covers the database updates.
+	webhookLease = webhookBatchSize * webhookTimeout + time.Minute
+	maxWebhookBackoff = 12 * time.Hour
+	//maxDrainSize caps how much of a response is read so the connection can be reused
+	maxDrainSize = 4096
+)
+
+// WebhookDispatcher POSTs queued webhook events to their endpoints. Failed
+// deliveries are retried with a doubling backoff, and an endpoint that keeps
+// failing is disabled.
+type WebhookDispatcher struct {
+	store db.Store
+	client *http.Client
+	interval time.Duration
+	maxAttempts int32
+	backoff time.Duration
+	maxFailures int32
+}
+
+func NewWebhookDispatcher(store db.Store, client *http.Client, interval time.Duration, maxAttempts int32, backoff time.Duration, maxFailures int32) *WebhookDispatcher {
+	if client == nil {
+		client = webhook.NewClient(webhookTimeout)
+	}
+
+	return &WebhookDispatcher{
+		store: store,
+		client: client,
+		interval: interval,
+		maxAttempts: maxAttempts,
+		backoff: backoff,
+		maxFailures: maxFailures,
+	}
+}
+
+// Start runs a dispatch every interval until ctx is cancelled.
+func (dispatcher *WebhookDispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dispatcher.Dispatch(ctx); err != nil {
+			log.Println("cannot dispatch webhooks:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch claims one batch of due deliveries and sends them. It returns the
+// number of deliveries that were accepted by their endpoint.
+func (dispatcher *WebhookDispatcher) Dispatch(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	deliveries, err := dispatcher.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		Now: now,
+		BatchSize: webhookBatchSize,
+		LeaseUntil: now.Add(webhookLease),
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	for _, delivery := range deliveries {
+		ok, err := dispatcher.deliver(ctx, delivery)
+
+		//A claimed delivery that couldn't be updated is picked up again once its lease runs out
+		if err != nil {
+			log.Println("cannot deliver webhook:", err)
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+func (dispatcher *WebhookDispatcher) deliver(ctx context.Context, delivery db.WebhookDelivery) (bool, error) {
+	endpoint, err := dispatcher.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+
+	if err != nil {
+		return false, err
+	}
+
+	status, sendErr := dispatcher.send(ctx, endpoint, delivery)
+
+	responseStatus := sql.NullInt32{Int32: int32(status), Valid: status != 0}
+
+	if sendErr == nil {
+		_, err = dispatcher.store.MarkWebhookDeliverySent(ctx, db.MarkWebhookDeliverySentParams{
+			ID: delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return true, dispatcher.store.RecordWebhookSuccess(ctx, endpoint.ID)
+	}
+
+	arg := db.MarkWebhookDeliveryFailedParams {
+		ID: delivery.ID,
+		Status: db.DeliveryStatusPending,
+		ResponseStatus: responseStatus,
+		LastError: sql.NullString{String: sendErr.Error(), Valid: true},
+		NextAttemptAt: time.Now().Add(dispatcher.retryDelay(delivery.Attempts)),
+	}
+
+	if delivery.Attempts >= dispatcher.maxAttempts {
+		arg.Status = db.DeliveryStatusFailed
+	}
+
+	if _, err = dispatcher.store.MarkWebhookDeliveryFailed(ctx, arg); err != nil {
+		return false, err
+	}
+
+	endpoint, err = dispatcher.store.RecordWebhookFailure(ctx, db.RecordWebhookFailureParams{
+		ID: endpoint.ID,
+		MaxFailures: dispatcher.maxFailures,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	//Whatever is still queued for a disabled endpoint is dropped rather than
+	//sent in a burst once the endpoint is enabled again
+
+	if !endpoint.Active {
+		log.Printf("disabled webhook endpoint %s after %d failures", endpoint.ID, endpoint.FailureCount)
+		_, err = dispatcher.store.CancelWebhookDeliveries(ctx, endpoint.ID)
+	}
+
+	return false, err
+}
+
+// send POSTs the payload of delivery, signed with the secret of endpoint. It
+// returns the status code of the response, or 0 when there was none.
+func (dispatcher *WebhookDispatcher) send(ctx context.Context, endpoint db.WebhookEndpoint, delivery db.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "YourTime-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.EventIDHeader, delivery.EventID.String())
+
+	now := time.Now()
+	req.Header.Set(webhook.TimestampHeader, fmt.Sprint(now.Unix()))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(endpoint.Secret, now, delivery.Payload))
+
+	res, err := dispatcher.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	//Draining the body lets the connection be reused. It never ends up in the
+	//delivery log, which the user can read, so an endpoint pointed somewhere
+	//it shouldn't be doesn't leak what it got back
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainSize))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("endpoint responded with %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
+
+// retryDelay doubles the backoff for every attempt that has already failed.
+func (dispatcher *WebhookDispatcher) retryDelay(attempts int32) time.Duration {
+	delay := dispatcher.backoff
+
+	for i := int32(1); i < attempts && delay < maxWebhookBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxWebhookBackoff {
+		delay = maxWebhookBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	mockdb "m1thrandir225/your_time/db/mock"
+	db "m1thrandir225/your_time/db/sqlc"
+	"m1thrandir225/your_time/webhook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	endpoint := db.WebhookEndpoint {
+		ID: uuid.New(),
+		UserID: uuid.New(),
+		Secret: "whsec_test",
+		EventTypes: []string{"task.created"},
+		Active: true,
+	}
+
+	delivery := db.WebhookDelivery {
+		ID: uuid.New(),
+		EndpointID: endpoint.ID,
+		EventID: uuid.New(),
+		EventType: "task.created",
+		Payload: []byte(`{"type":"task.created"}`),
+		Status: db.DeliveryStatusPending,
+		Attempts: 1,
+	}
+
+	lastAttempt := delivery
+	lastAttempt.Attempts = 3
+
+	disabled := endpoint
+	disabled.Active = false
+	disabled.FailureCount = 5
+
+	testCases := []struct {
+		name 	string
+		delivery 	db.WebhookDelivery
+		status 	int
+		build 	func(store *mockdb.MockStore)
+		sent 	int
+	}{
+		{
+			name: "Sent",
+			delivery: delivery,
+			status: http.StatusNoContent,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkWebhookDeliverySent(gomock.Any(), gomock.Eq(db.MarkWebhookDeliverySentParams{
+						ID: delivery.ID,
+						ResponseStatus: sql.NullInt32{Int32: http.StatusNoContent, Valid: true},
+					})).
+					Times(1).
+					Return(delivery, nil)
+				store.EXPECT().RecordWebhookSuccess(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(nil)
+			},
+			sent: 1,
+		},
+		{
+			name: "Retry",
+			delivery: delivery,
+			status: http.StatusInternalServerError,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+						require.Equal(t, db.DeliveryStatusPending, arg.Status)
+						require.Equal(t, sql.NullInt32{Int32: http.StatusInternalServerError, Valid: true}, arg.ResponseStatus)
+						require.Equal(t, "endpoint responded with 500 Internal Server Error", arg.LastError.String)
+						require.WithinDuration(t, time.Now().Add(time.Minute), arg.NextAttemptAt, time.Second)
+						return delivery, nil
+					})
+				store.EXPECT().
+					RecordWebhookFailure(gomock.Any(), gomock.Eq(db.RecordWebhookFailureParams{ID: endpoint.ID, MaxFailures: 5})).
+					Times(1).
+					Return(endpoint, nil)
+			},
+		},
+		{
+			name: "GiveUp",
+			delivery: lastAttempt,
+			status: http.StatusBadGateway,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+						require.Equal(t, db.DeliveryStatusFailed, arg.Status)
+						return lastAttempt, nil
+					})
+				store.EXPECT().RecordWebhookFailure(gomock.Any(), gomock.Any()).Times(1).Return(endpoint, nil)
+			},
+		},
+		{
+			name: "DisableEndpoint",
+			delivery: delivery,
+			status: http.StatusGone,
+			build: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(1).Return(delivery, nil)
+				store.EXPECT().RecordWebhookFailure(gomock.Any(), gomock.Any()).Times(1).Return(disabled, nil)
+				store.EXPECT().CancelWebhookDeliveries(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(int64(3), nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, string(tc.delivery.Payload), string(body))
+
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.Equal(t, tc.delivery.EventType, r.Header.Get(webhook.EventHeader))
+				require.Equal(t, tc.delivery.EventID.String(), r.Header.Get(webhook.EventIDHeader))
+
+				err = webhook.Verify(endpoint.Secret, r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, time.Minute)
+				require.NoError(t, err)
+
+				w.WriteHeader(tc.status)
+				w.Write([]byte("boom"))
+			}))
+			defer receiver.Close()
+
+			target := endpoint
+			target.Url = receiver.URL
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().
+				ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+					require.Equal(t, webhookLease, arg.LeaseUntil.Sub(arg.Now))
+					return []db.WebhookDelivery{tc.delivery}, nil
+				})
+
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(target, nil)
+
+			tc.build(store)
+
+			dispatcher := NewWebhookDispatcher(store, receiver.Client(), time.Minute, 3, time.Minute, 5)
+
+			sent, err := dispatcher.Dispatch(context.Background())
+
+			require.NoError(t, err)
+			require.Equal(t, tc.sent, sent)
+			require.Equal(t, 1, requests)
+		})
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	dispatcher := NewWebhookDispatcher(nil, nil, time.Minute, 10, time.Minute, 5)
+
+	require.Equal(t, time.Minute, dispatcher.retryDelay(1))
+	require.Equal(t, 4*time.Minute, dispatcher.retryDelay(3))
+	require.Equal(t, maxWebhookBackoff, dispatcher.retryDelay(30))
+}